@@ -5,10 +5,10 @@ go 1.17
 require (
 	github.com/lestrrat-go/jwx/v2 v2.0.8
 	github.com/urfave/cli/v2 v2.24.4
-	golang.org/x/crypto v0.7.0
 )
 
 require (
+	github.com/cloudflare/circl v1.3.3 // indirect
 	github.com/cpuguy83/go-md2man/v2 v2.0.2 // indirect
 	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.1.0 // indirect
 	github.com/goccy/go-json v0.10.0 // indirect
@@ -18,6 +18,12 @@ require (
 	github.com/lestrrat-go/iter v1.0.2 // indirect
 	github.com/lestrrat-go/option v1.0.1 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
-	github.com/stretchr/testify v1.8.2 // indirect
 	github.com/xrash/smetrics v0.0.0-20201216005158-039620a65673 // indirect
+	golang.org/x/crypto v0.7.0 // indirect
+	golang.org/x/sys v0.6.0 // indirect
 )
+
+// jwx uses APIs that are not available in a released version of
+// github.com/lestrrat-go/jwx/v2 yet. Remove this and update the
+// requirement above once they are released.
+replace github.com/lestrrat-go/jwx/v2 v2.0.8 => ../..
@@ -54,7 +54,7 @@ func makeJweEncryptCmd() *cli.Command {
 		&cli.StringFlag{
 			Name:     "content-encryption",
 			Aliases:  []string{"C"},
-			Usage:    "Content encryption algorithm name `NAME` (e.g. A128CBC-HS256, A192GCM, A256GCM, etc)",
+			Usage:    "Content encryption algorithm name `NAME` (e.g. A128CBC-HS256, A192GCM, A256GCM, C20P, XC20P, etc)",
 			Required: true,
 		},
 		&cli.BoolFlag{
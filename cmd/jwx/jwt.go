@@ -0,0 +1,530 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/urfave/cli/v2"
+)
+
+func init() {
+	topLevelCommands = append(topLevelCommands, makeJwtCmd())
+}
+
+func makeJwtCmd() *cli.Command {
+	var cmd cli.Command
+	cmd.Name = "jwt"
+	cmd.Usage = "Work with JWT tokens"
+
+	cmd.Subcommands = []*cli.Command{
+		makeJwtParseCmd(),
+		makeJwtSignCmd(),
+		makeJwtVerifyCmd(),
+		makeJwtValidateCmd(),
+	}
+	return &cmd
+}
+
+// optionalKeyFlag is the same as keyFlag, except that the key may be omitted
+func optionalKeyFlag(use string) cli.Flag {
+	return &cli.StringFlag{
+		Name:    "key",
+		Aliases: []string{"k"},
+		Usage:   "`FILE` containing the key to " + use + " with",
+	}
+}
+
+func matchKidFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "match-kid",
+		Value: false,
+		Usage: "instead of using alg, attempt to verify only if the key ID (kid) matches",
+	}
+}
+
+// jwtVerifyOptions creates the options required to verify the signature
+// of a JWT using the key(s) specified in the command line. If no key
+// is specified, returns nil.
+func jwtVerifyOptions(c *cli.Context) ([]jwt.ParseOption, error) {
+	keyfile := c.String("key")
+	if keyfile == "" {
+		return nil, nil
+	}
+
+	keyset, err := getKeyFile(keyfile, c.String("key-format"))
+	if err != nil {
+		return nil, err
+	}
+
+	keyset, err = jwk.PublicSetOf(keyset)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to retrieve public key: %w`, err)
+	}
+
+	if c.Bool("match-kid") {
+		return []jwt.ParseOption{jwt.WithKeySet(keyset)}, nil
+	}
+
+	var alg jwa.SignatureAlgorithm
+	givenalg := c.String("alg")
+	if givenalg == "" {
+		return nil, fmt.Errorf(`option --alg must be given`)
+	}
+
+	if err := alg.Accept(givenalg); err != nil {
+		return nil, fmt.Errorf(`invalid alg %s`, givenalg)
+	}
+
+	var options []jwt.ParseOption
+	ctx := context.Background()
+	for iter := keyset.Keys(ctx); iter.Next(ctx); {
+		pair := iter.Pair()
+		//nolint:forcetypeassert
+		options = append(options, jwt.WithKey(alg, pair.Value.(jwk.Key)))
+	}
+	return options, nil
+}
+
+// jwtValidateOptions creates the options required to validate the
+// claims of a JWT from the command line.
+func jwtValidateOptions(c *cli.Context) []jwt.ParseOption {
+	var options []jwt.ParseOption
+	if v := c.String("issuer"); v != "" {
+		options = append(options, jwt.WithIssuer(v))
+	}
+	if v := c.String("subject"); v != "" {
+		options = append(options, jwt.WithSubject(v))
+	}
+	for _, v := range c.StringSlice("audience") {
+		options = append(options, jwt.WithAudience(v))
+	}
+	for _, v := range c.StringSlice("required-claim") {
+		options = append(options, jwt.WithRequiredClaim(v))
+	}
+	if v := c.Duration("skew"); v > 0 {
+		options = append(options, jwt.WithAcceptableSkew(v))
+	}
+	if v := c.Timestamp("clock"); v != nil {
+		now := *v
+		options = append(options, jwt.WithClock(jwt.ClockFunc(func() time.Time { return now })))
+	}
+	return options
+}
+
+func readJwtSource(c *cli.Context) ([]byte, error) {
+	src, err := getSource(c.Args().Get(0))
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	buf, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read data from source: %w`, err)
+	}
+	return buf, nil
+}
+
+// dumpJWT writes out the protected headers (if available) and the claims
+// of the given token in a human readable JSON format
+func dumpJWT(dst io.Writer, buf []byte, token jwt.Token) error {
+	var v struct {
+		Headers interface{} `json:"headers,omitempty"`
+		Claims  jwt.Token   `json:"claims"`
+	}
+	v.Claims = token
+
+	if jwx.GuessFormat(buf) == jwx.JWS {
+		msg, err := jws.Parse(buf)
+		if err != nil {
+			return fmt.Errorf(`failed to parse message: %w`, err)
+		}
+
+		sigs := msg.Signatures()
+		if len(sigs) == 1 {
+			v.Headers = sigs[0].ProtectedHeaders()
+		} else {
+			list := make([]jws.Headers, len(sigs))
+			for i, sig := range sigs {
+				list[i] = sig.ProtectedHeaders()
+			}
+			v.Headers = list
+		}
+	}
+
+	if err := dumpJSON(dst, v); err != nil {
+		return err
+	}
+	fmt.Fprintf(dst, "\n")
+	return nil
+}
+
+func makeJwtParseCmd() *cli.Command {
+	var cmd cli.Command
+	cmd.Name = "parse"
+	cmd.Usage = "Parse JWT token"
+	cmd.UsageText = `jwx jwt parse [command options] FILE
+
+   Parse FILE and display the protected headers and the claims in
+   a JWT token. Use "-" as FILE to read from STDIN.
+
+   If --key is given, the signature of the token is verified before
+   its contents are displayed. Otherwise the token is parsed without
+   any verification. In both cases the claims are NOT validated:
+   use "jwx jwt validate" for that.
+`
+	cmd.Flags = []cli.Flag{
+		jwsAlgorithmFlag("verify"),
+		optionalKeyFlag("verify"),
+		keyFormatFlag(),
+		matchKidFlag(),
+		outputFlag(),
+	}
+
+	// jwx jwt parse <file>
+	cmd.Action = func(c *cli.Context) error {
+		buf, err := readJwtSource(c)
+		if err != nil {
+			return err
+		}
+
+		options, err := jwtVerifyOptions(c)
+		if err != nil {
+			return err
+		}
+
+		var token jwt.Token
+		if len(options) > 0 {
+			options = append(options, jwt.WithValidate(false))
+			token, err = jwt.Parse(buf, options...)
+		} else {
+			token, err = jwt.ParseInsecure(buf)
+		}
+		if err != nil {
+			return fmt.Errorf(`failed to parse token: %w`, err)
+		}
+
+		output, err := getOutput(c.String("output"))
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		return dumpJWT(output, buf, token)
+	}
+	return &cmd
+}
+
+func makeJwtVerifyCmd() *cli.Command {
+	var cmd cli.Command
+	cmd.Name = "verify"
+	cmd.Aliases = []string{"ver"}
+	cmd.Usage = "Verify JWT tokens"
+	cmd.UsageText = `jwx jwt verify [command options] FILE
+
+   Parses a JWT token in FILE, verifies its signature using the
+   specified method, and validates the standard time based claims
+   ("exp", "iat", and "nbf"). The claims are displayed upon success.
+   Use "-" as FILE to read from STDIN.
+
+   The key selection works the same as "jwx jws verify": either
+   specify the algorithm with --alg, or use --match-kid to select
+   the key by its key ID ("kid").
+`
+	cmd.Flags = []cli.Flag{
+		jwsAlgorithmFlag("verify"),
+		keyFlag("verify"),
+		keyFormatFlag(),
+		matchKidFlag(),
+		outputFlag(),
+	}
+
+	// jwx jwt verify <file>
+	cmd.Action = func(c *cli.Context) error {
+		buf, err := readJwtSource(c)
+		if err != nil {
+			return err
+		}
+
+		options, err := jwtVerifyOptions(c)
+		if err != nil {
+			return err
+		}
+
+		token, err := jwt.Parse(buf, options...)
+		if err != nil {
+			return fmt.Errorf(`failed to verify token: %w`, err)
+		}
+
+		output, err := getOutput(c.String("output"))
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		return dumpJWT(output, buf, token)
+	}
+	return &cmd
+}
+
+func makeJwtValidateCmd() *cli.Command {
+	var cmd cli.Command
+	cmd.Name = "validate"
+	cmd.Usage = "Validate the claims in a JWT token"
+	cmd.UsageText = `jwx jwt validate [command options] FILE
+
+   Parses a JWT token in FILE, and validates its claims. The claims are
+   displayed upon success. Use "-" as FILE to read from STDIN.
+
+   If --key is given, the signature is also verified in the same
+   manner as "jwx jwt verify". Otherwise only the claims are validated.
+`
+	cmd.Flags = []cli.Flag{
+		jwsAlgorithmFlag("verify"),
+		optionalKeyFlag("verify"),
+		keyFormatFlag(),
+		matchKidFlag(),
+		&cli.StringFlag{
+			Name:  "issuer",
+			Usage: "expected value `ISSUER` of the \"iss\" claim",
+		},
+		&cli.StringFlag{
+			Name:  "subject",
+			Usage: "expected value `SUBJECT` of the \"sub\" claim",
+		},
+		&cli.StringSliceFlag{
+			Name:  "audience",
+			Usage: "`AUDIENCE` that must be included in the \"aud\" claim (may be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "required-claim",
+			Usage: "`NAME` of a claim that must exist in the token (may be repeated)",
+		},
+		&cli.DurationFlag{
+			Name:  "skew",
+			Usage: "acceptable clock skew `DURATION` for time based claims",
+		},
+		&cli.TimestampFlag{
+			Name:   "clock",
+			Usage:  "use the fixed time `TIME` (RFC3339) instead of the current time",
+			Layout: time.RFC3339,
+		},
+		outputFlag(),
+	}
+
+	// jwx jwt validate <file>
+	cmd.Action = func(c *cli.Context) error {
+		buf, err := readJwtSource(c)
+		if err != nil {
+			return err
+		}
+
+		options, err := jwtVerifyOptions(c)
+		if err != nil {
+			return err
+		}
+		if len(options) == 0 {
+			options = append(options, jwt.WithVerify(false))
+		}
+		options = append(options, jwtValidateOptions(c)...)
+
+		token, err := jwt.Parse(buf, options...)
+		if err != nil {
+			return fmt.Errorf(`failed to validate token: %w`, err)
+		}
+
+		output, err := getOutput(c.String("output"))
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		return dumpJWT(output, buf, token)
+	}
+	return &cmd
+}
+
+func makeJwtSignCmd() *cli.Command {
+	var cmd cli.Command
+	cmd.Name = "sign"
+	cmd.Aliases = []string{"sig"}
+	cmd.Usage = "Create a signed JWT token"
+	cmd.UsageText = `jwx jwt sign [command options] [FILE]
+
+   Signs the claims in FILE and generates a JWT token in compact format.
+   FILE must contain a JSON object. Use "-" as FILE to read from STDIN.
+   If FILE is omitted, the token is created solely from the claims
+   specified in the command line options.
+
+   Claims specified in the command line options take precedence over
+   those in FILE.
+`
+	cmd.Flags = []cli.Flag{
+		jwsAlgorithmFlag("sign"),
+		keyFlag("sign"),
+		keyFormatFlag(),
+		&cli.StringFlag{
+			Name:  "header",
+			Usage: "header object to inject into JWS message protected header",
+		},
+		&cli.StringFlag{
+			Name:  "issuer",
+			Usage: "value `ISSUER` of the \"iss\" claim",
+		},
+		&cli.StringFlag{
+			Name:  "subject",
+			Usage: "value `SUBJECT` of the \"sub\" claim",
+		},
+		&cli.StringSliceFlag{
+			Name:  "audience",
+			Usage: "`AUDIENCE` to include in the \"aud\" claim (may be repeated)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "claim",
+			Usage: "arbitrary claim in the form of `NAME=VALUE` (may be repeated). VALUE is parsed as JSON if possible, otherwise it is treated as a string",
+		},
+		&cli.BoolFlag{
+			Name:  "iat",
+			Usage: "set the \"iat\" claim to the current time, if not already present",
+			Value: true,
+		},
+		&cli.DurationFlag{
+			Name:  "expires-in",
+			Usage: "set the \"exp\" claim to the current time + `DURATION`",
+		},
+		&cli.BoolFlag{
+			Name:  "jti",
+			Usage: "set the \"jti\" claim to a random value, if not already present",
+		},
+		outputFlag(),
+	}
+
+	// jwx jwt sign <file>
+	cmd.Action = func(c *cli.Context) error {
+		keyset, err := getKeyFile(c.String("key"), c.String("key-format"))
+		if err != nil {
+			return err
+		}
+
+		if keyset.Len() != 1 {
+			return fmt.Errorf(`jwk file must contain exactly one key`)
+		}
+		key, _ := keyset.Key(0)
+
+		token := jwt.New()
+		if c.Args().Get(0) != "" {
+			buf, err := readJwtSource(c)
+			if err != nil {
+				return err
+			}
+			if err := json.Unmarshal(buf, token); err != nil {
+				return fmt.Errorf(`failed to parse claims: %w`, err)
+			}
+		}
+
+		if err := setJwtClaims(c, token, time.Now()); err != nil {
+			return err
+		}
+
+		var alg jwa.SignatureAlgorithm
+		givenalg := c.String("alg")
+		if givenalg == "" {
+			return fmt.Errorf(`option --alg must be given`)
+		}
+
+		if err := alg.Accept(givenalg); err != nil {
+			return fmt.Errorf(`invalid alg %s`, givenalg)
+		}
+
+		var suboptions []jwt.Option
+		if hdrbuf := c.String("header"); hdrbuf != "" {
+			h := jws.NewHeaders()
+			if err := json.Unmarshal([]byte(hdrbuf), h); err != nil {
+				return fmt.Errorf(`failed to parse header: %w`, err)
+			}
+			suboptions = append(suboptions, jws.WithProtectedHeaders(h))
+		}
+
+		signed, err := jwt.Sign(token, jwt.WithKey(alg, key, suboptions...))
+		if err != nil {
+			return fmt.Errorf(`failed to sign token: %w`, err)
+		}
+
+		output, err := getOutput(c.String("output"))
+		if err != nil {
+			return err
+		}
+		defer output.Close()
+
+		fmt.Fprintf(output, "%s", signed)
+		return nil
+	}
+	return &cmd
+}
+
+// setJwtClaims sets the claims specified in the command line options
+func setJwtClaims(c *cli.Context, token jwt.Token, now time.Time) error {
+	if v := c.String("issuer"); v != "" {
+		if err := token.Set(jwt.IssuerKey, v); err != nil {
+			return fmt.Errorf(`failed to set "iss": %w`, err)
+		}
+	}
+	if v := c.String("subject"); v != "" {
+		if err := token.Set(jwt.SubjectKey, v); err != nil {
+			return fmt.Errorf(`failed to set "sub": %w`, err)
+		}
+	}
+	if v := c.StringSlice("audience"); len(v) > 0 {
+		if err := token.Set(jwt.AudienceKey, v); err != nil {
+			return fmt.Errorf(`failed to set "aud": %w`, err)
+		}
+	}
+
+	for _, claim := range c.StringSlice("claim") {
+		i := strings.IndexByte(claim, '=')
+		if i <= 0 {
+			return fmt.Errorf(`invalid claim %q (expected NAME=VALUE)`, claim)
+		}
+		name, raw := claim[:i], claim[i+1:]
+
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		if err := token.Set(name, value); err != nil {
+			return fmt.Errorf(`failed to set %q: %w`, name, err)
+		}
+	}
+
+	if c.Bool("iat") && token.IssuedAt().IsZero() {
+		if err := token.Set(jwt.IssuedAtKey, now); err != nil {
+			return fmt.Errorf(`failed to set "iat": %w`, err)
+		}
+	}
+	if v := c.Duration("expires-in"); v > 0 {
+		if err := token.Set(jwt.ExpirationKey, now.Add(v)); err != nil {
+			return fmt.Errorf(`failed to set "exp": %w`, err)
+		}
+	}
+	if c.Bool("jti") && token.JwtID() == "" {
+		var buf [16]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			return fmt.Errorf(`failed to generate random value for "jti": %w`, err)
+		}
+		if err := token.Set(jwt.JwtIDKey, base64.RawURLEncoding.EncodeToString(buf[:])); err != nil {
+			return fmt.Errorf(`failed to set "jti": %w`, err)
+		}
+	}
+	return nil
+}
@@ -8,19 +8,26 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/big"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/lestrrat-go/jwx/v2/cert"
 	"github.com/lestrrat-go/jwx/v2/internal/ecutil"
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -119,6 +126,25 @@ func GenerateEd25519Jwk() (jwk.Key, error) {
 	return k, nil
 }
 
+func GenerateEd448Key() (ed448.PrivateKey, error) {
+	_, priv, err := ed448.GenerateKey(rand.Reader)
+	return priv, err
+}
+
+func GenerateEd448Jwk() (jwk.Key, error) {
+	key, err := GenerateEd448Key()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate Ed448 private key: %w`, err)
+	}
+
+	k, err := jwk.FromRaw(key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate jwk.OKPPrivateKey: %w`, err)
+	}
+
+	return k, nil
+}
+
 func GenerateX25519Key() (x25519.PrivateKey, error) {
 	_, priv, err := x25519.GenerateKey(rand.Reader)
 	return priv, err
@@ -138,6 +164,25 @@ func GenerateX25519Jwk() (jwk.Key, error) {
 	return k, nil
 }
 
+func GenerateX448Key() (x448.PrivateKey, error) {
+	_, priv, err := x448.GenerateKey(rand.Reader)
+	return priv, err
+}
+
+func GenerateX448Jwk() (jwk.Key, error) {
+	key, err := GenerateX448Key()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate X448 private key: %w`, err)
+	}
+
+	k, err := jwk.FromRaw(key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate jwk.OKPPrivateKey: %w`, err)
+	}
+
+	return k, nil
+}
+
 func WriteFile(template string, src io.Reader) (string, func(), error) {
 	file, cleanup, err := CreateTempFile(template)
 	if err != nil {
@@ -355,3 +400,76 @@ func SignJwsFile(ctx context.Context, payload []byte, alg jwa.SignatureAlgorithm
 
 	return WriteFile("jwx-test-*.jws", bytes.NewReader(buf))
 }
+
+// GenerateX509Chain generates a self-signed root certificate, an
+// intermediate certificate, and a leaf certificate for a newly generated
+// ECDSA P-256 key. The returned chain contains the leaf and the
+// intermediate certificates, in that order, and is suitable to be used
+// in "x5c" fields. The certificates are valid for one hour.
+func GenerateX509Chain(extKeyUsages ...x509.ExtKeyUsage) (*ecdsa.PrivateKey, *cert.Chain, *x509.Certificate, error) {
+	now := time.Now()
+	var serial int64
+	create := func(name string, isCA bool, parent *x509.Certificate, pub, priv interface{}) (*x509.Certificate, []byte, error) {
+		serial++
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(serial),
+			Subject:               pkix.Name{CommonName: name},
+			NotBefore:             now.Add(-time.Minute),
+			NotAfter:              now.Add(time.Hour),
+			BasicConstraintsValid: true,
+			IsCA:                  isCA,
+		}
+		if isCA {
+			template.KeyUsage = x509.KeyUsageCertSign
+		} else {
+			template.KeyUsage = x509.KeyUsageDigitalSignature
+			template.ExtKeyUsage = extKeyUsages
+		}
+		if parent == nil {
+			parent = template
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, parent, pub, priv)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`failed to create certificate %q: %w`, name, err)
+		}
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`failed to parse certificate %q: %w`, name, err)
+		}
+		return c, der, nil
+	}
+
+	var keys [3]*ecdsa.PrivateKey
+	for i := range keys {
+		key, err := GenerateEcdsaKey(jwa.P256)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf(`failed to generate key: %w`, err)
+		}
+		keys[i] = key
+	}
+
+	root, _, err := create(`root`, true, nil, &keys[0].PublicKey, keys[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	intermediate, intermediateDER, err := create(`intermediate`, true, root, &keys[1].PublicKey, keys[0])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	_, leafDER, err := create(`leaf`, false, intermediate, &keys[2].PublicKey, keys[1])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var chain cert.Chain
+	for _, der := range [][]byte{leafDER, intermediateDER} {
+		encoded, err := cert.EncodeBase64(der)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf(`failed to encode certificate: %w`, err)
+		}
+		if err := chain.Add(encoded); err != nil {
+			return nil, nil, nil, fmt.Errorf(`failed to add certificate to chain: %w`, err)
+		}
+	}
+	return keys[2], &chain, root, nil
+}
@@ -19,6 +19,8 @@ const (
 	A192GCM       ContentEncryptionAlgorithm = "A192GCM"       // AES-GCM (192)
 	A256CBC_HS512 ContentEncryptionAlgorithm = "A256CBC-HS512" // AES-CBC + HMAC-SHA512 (256)
 	A256GCM       ContentEncryptionAlgorithm = "A256GCM"       // AES-GCM (256)
+	C20P          ContentEncryptionAlgorithm = "C20P"          // ChaCha20-Poly1305
+	XC20P         ContentEncryptionAlgorithm = "XC20P"         // XChaCha20-Poly1305
 )
 
 var allContentEncryptionAlgorithms = map[ContentEncryptionAlgorithm]struct{}{
@@ -28,22 +30,55 @@ var allContentEncryptionAlgorithms = map[ContentEncryptionAlgorithm]struct{}{
 	A192GCM:       {},
 	A256CBC_HS512: {},
 	A256GCM:       {},
+	C20P:          {},
+	XC20P:         {},
 }
 
-var listContentEncryptionAlgorithmOnce sync.Once
+var muContentEncryptionAlgorithms sync.RWMutex
 var listContentEncryptionAlgorithm []ContentEncryptionAlgorithm
 
+func init() {
+	muContentEncryptionAlgorithms.Lock()
+	defer muContentEncryptionAlgorithms.Unlock()
+	rebuildContentEncryptionAlgorithm()
+}
+
+// RegisterContentEncryptionAlgorithm registers a new ContentEncryptionAlgorithm so that the jwx can properly handle the new value.
+// Duplicates will silently be ignored
+func RegisterContentEncryptionAlgorithm(v ContentEncryptionAlgorithm) {
+	muContentEncryptionAlgorithms.Lock()
+	defer muContentEncryptionAlgorithms.Unlock()
+	if _, ok := allContentEncryptionAlgorithms[v]; !ok {
+		allContentEncryptionAlgorithms[v] = struct{}{}
+		rebuildContentEncryptionAlgorithm()
+	}
+}
+
+// UnregisterContentEncryptionAlgorithm unregisters a ContentEncryptionAlgorithm from its known database.
+// Non-existent entries will silently be ignored
+func UnregisterContentEncryptionAlgorithm(v ContentEncryptionAlgorithm) {
+	muContentEncryptionAlgorithms.Lock()
+	defer muContentEncryptionAlgorithms.Unlock()
+	if _, ok := allContentEncryptionAlgorithms[v]; ok {
+		delete(allContentEncryptionAlgorithms, v)
+		rebuildContentEncryptionAlgorithm()
+	}
+}
+
+func rebuildContentEncryptionAlgorithm() {
+	listContentEncryptionAlgorithm = make([]ContentEncryptionAlgorithm, 0, len(allContentEncryptionAlgorithms))
+	for v := range allContentEncryptionAlgorithms {
+		listContentEncryptionAlgorithm = append(listContentEncryptionAlgorithm, v)
+	}
+	sort.Slice(listContentEncryptionAlgorithm, func(i, j int) bool {
+		return string(listContentEncryptionAlgorithm[i]) < string(listContentEncryptionAlgorithm[j])
+	})
+}
+
 // ContentEncryptionAlgorithms returns a list of all available values for ContentEncryptionAlgorithm
 func ContentEncryptionAlgorithms() []ContentEncryptionAlgorithm {
-	listContentEncryptionAlgorithmOnce.Do(func() {
-		listContentEncryptionAlgorithm = make([]ContentEncryptionAlgorithm, 0, len(allContentEncryptionAlgorithms))
-		for v := range allContentEncryptionAlgorithms {
-			listContentEncryptionAlgorithm = append(listContentEncryptionAlgorithm, v)
-		}
-		sort.Slice(listContentEncryptionAlgorithm, func(i, j int) bool {
-			return string(listContentEncryptionAlgorithm[i]) < string(listContentEncryptionAlgorithm[j])
-		})
-	})
+	muContentEncryptionAlgorithms.RLock()
+	defer muContentEncryptionAlgorithms.RUnlock()
 	return listContentEncryptionAlgorithm
 }
 
@@ -65,6 +100,9 @@ func (v *ContentEncryptionAlgorithm) Accept(value interface{}) error {
 		}
 		tmp = ContentEncryptionAlgorithm(s)
 	}
+
+	muContentEncryptionAlgorithms.RLock()
+	defer muContentEncryptionAlgorithms.RUnlock()
 	if _, ok := allContentEncryptionAlgorithms[tmp]; !ok {
 		return fmt.Errorf(`invalid jwa.ContentEncryptionAlgorithm value`)
 	}
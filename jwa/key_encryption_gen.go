@@ -20,6 +20,10 @@ const (
 	A256GCMKW          KeyEncryptionAlgorithm = "A256GCMKW"          // AES-GCM key wrap (256)
 	A256KW             KeyEncryptionAlgorithm = "A256KW"             // AES key wrap (256)
 	DIRECT             KeyEncryptionAlgorithm = "dir"                // Direct encryption
+	ECDH_1PU           KeyEncryptionAlgorithm = "ECDH-1PU"           // ECDH-1PU
+	ECDH_1PU_A128KW    KeyEncryptionAlgorithm = "ECDH-1PU+A128KW"    // ECDH-1PU + AES key wrap (128)
+	ECDH_1PU_A192KW    KeyEncryptionAlgorithm = "ECDH-1PU+A192KW"    // ECDH-1PU + AES key wrap (192)
+	ECDH_1PU_A256KW    KeyEncryptionAlgorithm = "ECDH-1PU+A256KW"    // ECDH-1PU + AES key wrap (256)
 	ECDH_ES            KeyEncryptionAlgorithm = "ECDH-ES"            // ECDH-ES
 	ECDH_ES_A128KW     KeyEncryptionAlgorithm = "ECDH-ES+A128KW"     // ECDH-ES + AES key wrap (128)
 	ECDH_ES_A192KW     KeyEncryptionAlgorithm = "ECDH-ES+A192KW"     // ECDH-ES + AES key wrap (192)
@@ -40,6 +44,10 @@ var allKeyEncryptionAlgorithms = map[KeyEncryptionAlgorithm]struct{}{
 	A256GCMKW:          {},
 	A256KW:             {},
 	DIRECT:             {},
+	ECDH_1PU:           {},
+	ECDH_1PU_A128KW:    {},
+	ECDH_1PU_A192KW:    {},
+	ECDH_1PU_A256KW:    {},
 	ECDH_ES:            {},
 	ECDH_ES_A128KW:     {},
 	ECDH_ES_A192KW:     {},
@@ -52,20 +60,51 @@ var allKeyEncryptionAlgorithms = map[KeyEncryptionAlgorithm]struct{}{
 	RSA_OAEP_256:       {},
 }
 
-var listKeyEncryptionAlgorithmOnce sync.Once
+var muKeyEncryptionAlgorithms sync.RWMutex
 var listKeyEncryptionAlgorithm []KeyEncryptionAlgorithm
 
+func init() {
+	muKeyEncryptionAlgorithms.Lock()
+	defer muKeyEncryptionAlgorithms.Unlock()
+	rebuildKeyEncryptionAlgorithm()
+}
+
+// RegisterKeyEncryptionAlgorithm registers a new KeyEncryptionAlgorithm so that the jwx can properly handle the new value.
+// Duplicates will silently be ignored
+func RegisterKeyEncryptionAlgorithm(v KeyEncryptionAlgorithm) {
+	muKeyEncryptionAlgorithms.Lock()
+	defer muKeyEncryptionAlgorithms.Unlock()
+	if _, ok := allKeyEncryptionAlgorithms[v]; !ok {
+		allKeyEncryptionAlgorithms[v] = struct{}{}
+		rebuildKeyEncryptionAlgorithm()
+	}
+}
+
+// UnregisterKeyEncryptionAlgorithm unregisters a KeyEncryptionAlgorithm from its known database.
+// Non-existent entries will silently be ignored
+func UnregisterKeyEncryptionAlgorithm(v KeyEncryptionAlgorithm) {
+	muKeyEncryptionAlgorithms.Lock()
+	defer muKeyEncryptionAlgorithms.Unlock()
+	if _, ok := allKeyEncryptionAlgorithms[v]; ok {
+		delete(allKeyEncryptionAlgorithms, v)
+		rebuildKeyEncryptionAlgorithm()
+	}
+}
+
+func rebuildKeyEncryptionAlgorithm() {
+	listKeyEncryptionAlgorithm = make([]KeyEncryptionAlgorithm, 0, len(allKeyEncryptionAlgorithms))
+	for v := range allKeyEncryptionAlgorithms {
+		listKeyEncryptionAlgorithm = append(listKeyEncryptionAlgorithm, v)
+	}
+	sort.Slice(listKeyEncryptionAlgorithm, func(i, j int) bool {
+		return string(listKeyEncryptionAlgorithm[i]) < string(listKeyEncryptionAlgorithm[j])
+	})
+}
+
 // KeyEncryptionAlgorithms returns a list of all available values for KeyEncryptionAlgorithm
 func KeyEncryptionAlgorithms() []KeyEncryptionAlgorithm {
-	listKeyEncryptionAlgorithmOnce.Do(func() {
-		listKeyEncryptionAlgorithm = make([]KeyEncryptionAlgorithm, 0, len(allKeyEncryptionAlgorithms))
-		for v := range allKeyEncryptionAlgorithms {
-			listKeyEncryptionAlgorithm = append(listKeyEncryptionAlgorithm, v)
-		}
-		sort.Slice(listKeyEncryptionAlgorithm, func(i, j int) bool {
-			return string(listKeyEncryptionAlgorithm[i]) < string(listKeyEncryptionAlgorithm[j])
-		})
-	})
+	muKeyEncryptionAlgorithms.RLock()
+	defer muKeyEncryptionAlgorithms.RUnlock()
 	return listKeyEncryptionAlgorithm
 }
 
@@ -87,6 +126,9 @@ func (v *KeyEncryptionAlgorithm) Accept(value interface{}) error {
 		}
 		tmp = KeyEncryptionAlgorithm(s)
 	}
+
+	muKeyEncryptionAlgorithms.RLock()
+	defer muKeyEncryptionAlgorithms.RUnlock()
 	if _, ok := allKeyEncryptionAlgorithms[tmp]; !ok {
 		return fmt.Errorf(`invalid jwa.KeyEncryptionAlgorithm value`)
 	}
@@ -27,20 +27,51 @@ var allKeyTypes = map[KeyType]struct{}{
 	RSA:      {},
 }
 
-var listKeyTypeOnce sync.Once
+var muKeyTypes sync.RWMutex
 var listKeyType []KeyType
 
+func init() {
+	muKeyTypes.Lock()
+	defer muKeyTypes.Unlock()
+	rebuildKeyType()
+}
+
+// RegisterKeyType registers a new KeyType so that the jwx can properly handle the new value.
+// Duplicates will silently be ignored
+func RegisterKeyType(v KeyType) {
+	muKeyTypes.Lock()
+	defer muKeyTypes.Unlock()
+	if _, ok := allKeyTypes[v]; !ok {
+		allKeyTypes[v] = struct{}{}
+		rebuildKeyType()
+	}
+}
+
+// UnregisterKeyType unregisters a KeyType from its known database.
+// Non-existent entries will silently be ignored
+func UnregisterKeyType(v KeyType) {
+	muKeyTypes.Lock()
+	defer muKeyTypes.Unlock()
+	if _, ok := allKeyTypes[v]; ok {
+		delete(allKeyTypes, v)
+		rebuildKeyType()
+	}
+}
+
+func rebuildKeyType() {
+	listKeyType = make([]KeyType, 0, len(allKeyTypes))
+	for v := range allKeyTypes {
+		listKeyType = append(listKeyType, v)
+	}
+	sort.Slice(listKeyType, func(i, j int) bool {
+		return string(listKeyType[i]) < string(listKeyType[j])
+	})
+}
+
 // KeyTypes returns a list of all available values for KeyType
 func KeyTypes() []KeyType {
-	listKeyTypeOnce.Do(func() {
-		listKeyType = make([]KeyType, 0, len(allKeyTypes))
-		for v := range allKeyTypes {
-			listKeyType = append(listKeyType, v)
-		}
-		sort.Slice(listKeyType, func(i, j int) bool {
-			return string(listKeyType[i]) < string(listKeyType[j])
-		})
-	})
+	muKeyTypes.RLock()
+	defer muKeyTypes.RUnlock()
 	return listKeyType
 }
 
@@ -62,6 +93,9 @@ func (v *KeyType) Accept(value interface{}) error {
 		}
 		tmp = KeyType(s)
 	}
+
+	muKeyTypes.RLock()
+	defer muKeyTypes.RUnlock()
 	if _, ok := allKeyTypes[tmp]; !ok {
 		return fmt.Errorf(`invalid jwa.KeyType value`)
 	}
@@ -48,20 +48,51 @@ var allSignatureAlgorithms = map[SignatureAlgorithm]struct{}{
 	RS512:       {},
 }
 
-var listSignatureAlgorithmOnce sync.Once
+var muSignatureAlgorithms sync.RWMutex
 var listSignatureAlgorithm []SignatureAlgorithm
 
+func init() {
+	muSignatureAlgorithms.Lock()
+	defer muSignatureAlgorithms.Unlock()
+	rebuildSignatureAlgorithm()
+}
+
+// RegisterSignatureAlgorithm registers a new SignatureAlgorithm so that the jwx can properly handle the new value.
+// Duplicates will silently be ignored
+func RegisterSignatureAlgorithm(v SignatureAlgorithm) {
+	muSignatureAlgorithms.Lock()
+	defer muSignatureAlgorithms.Unlock()
+	if _, ok := allSignatureAlgorithms[v]; !ok {
+		allSignatureAlgorithms[v] = struct{}{}
+		rebuildSignatureAlgorithm()
+	}
+}
+
+// UnregisterSignatureAlgorithm unregisters a SignatureAlgorithm from its known database.
+// Non-existent entries will silently be ignored
+func UnregisterSignatureAlgorithm(v SignatureAlgorithm) {
+	muSignatureAlgorithms.Lock()
+	defer muSignatureAlgorithms.Unlock()
+	if _, ok := allSignatureAlgorithms[v]; ok {
+		delete(allSignatureAlgorithms, v)
+		rebuildSignatureAlgorithm()
+	}
+}
+
+func rebuildSignatureAlgorithm() {
+	listSignatureAlgorithm = make([]SignatureAlgorithm, 0, len(allSignatureAlgorithms))
+	for v := range allSignatureAlgorithms {
+		listSignatureAlgorithm = append(listSignatureAlgorithm, v)
+	}
+	sort.Slice(listSignatureAlgorithm, func(i, j int) bool {
+		return string(listSignatureAlgorithm[i]) < string(listSignatureAlgorithm[j])
+	})
+}
+
 // SignatureAlgorithms returns a list of all available values for SignatureAlgorithm
 func SignatureAlgorithms() []SignatureAlgorithm {
-	listSignatureAlgorithmOnce.Do(func() {
-		listSignatureAlgorithm = make([]SignatureAlgorithm, 0, len(allSignatureAlgorithms))
-		for v := range allSignatureAlgorithms {
-			listSignatureAlgorithm = append(listSignatureAlgorithm, v)
-		}
-		sort.Slice(listSignatureAlgorithm, func(i, j int) bool {
-			return string(listSignatureAlgorithm[i]) < string(listSignatureAlgorithm[j])
-		})
-	})
+	muSignatureAlgorithms.RLock()
+	defer muSignatureAlgorithms.RUnlock()
 	return listSignatureAlgorithm
 }
 
@@ -83,6 +114,9 @@ func (v *SignatureAlgorithm) Accept(value interface{}) error {
 		}
 		tmp = SignatureAlgorithm(s)
 	}
+
+	muSignatureAlgorithms.RLock()
+	defer muSignatureAlgorithms.RUnlock()
 	if _, ok := allSignatureAlgorithms[tmp]; !ok {
 		return fmt.Errorf(`invalid jwa.SignatureAlgorithm value`)
 	}
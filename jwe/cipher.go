@@ -0,0 +1,97 @@
+package jwe
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe/internal/content_crypt"
+)
+
+// ContentCipherFactory creates ContentCipher objects.
+type ContentCipherFactory interface {
+	Create() (ContentCipher, error)
+}
+type ContentCipherFactoryFn func() (ContentCipher, error)
+
+func (fn ContentCipherFactoryFn) Create() (ContentCipher, error) {
+	return fn()
+}
+
+var muContentCiphers sync.RWMutex
+var contentCipherDB = make(map[jwa.ContentEncryptionAlgorithm]ContentCipherFactory)
+
+// RegisterContentCipher is used to register a factory object that creates
+// ContentCipher objects for the given content encryption algorithm. Once
+// registered, `jwe.Encrypt()` and `jwe.Decrypt()` use the factory to
+// encrypt and decrypt contents of messages using `alg` as their "enc"
+// header parameter, even if `alg` is one of the algorithms that are
+// supported by this library.
+//
+// `alg` is also registered via `jwa.RegisterContentEncryptionAlgorithm()`
+// so that it is accepted in the "enc" header parameter.
+func RegisterContentCipher(alg jwa.ContentEncryptionAlgorithm, f ContentCipherFactory) {
+	jwa.RegisterContentEncryptionAlgorithm(alg)
+	muContentCiphers.Lock()
+	contentCipherDB[alg] = f
+	muContentCiphers.Unlock()
+}
+
+// UnregisterContentCipher removes the ContentCipher factory that was
+// registered for the given algorithm. If the algorithm is supported
+// by this library, the built-in implementation is used again.
+//
+// Note that `alg` is NOT unregistered from the jwa package. Use
+// `jwa.UnregisterContentEncryptionAlgorithm()` if you need to do so.
+func UnregisterContentCipher(alg jwa.ContentEncryptionAlgorithm) {
+	muContentCiphers.Lock()
+	delete(contentCipherDB, alg)
+	muContentCiphers.Unlock()
+}
+
+func lookupContentCipher(alg jwa.ContentEncryptionAlgorithm) (ContentCipherFactory, bool) {
+	muContentCiphers.RLock()
+	defer muContentCiphers.RUnlock()
+	f, ok := contentCipherDB[alg]
+	return f, ok
+}
+
+// registeredContentCipher adapts a ContentCipher to the interface that
+// is used internally
+type registeredContentCipher struct {
+	ContentCipher
+}
+
+func (c registeredContentCipher) Encrypt(cek, plaintext, aad []byte) ([]byte, []byte, []byte, error) {
+	iv, err := c.GenerateIV()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf(`failed to generate initialization vector: %w`, err)
+	}
+
+	ciphertext, tag, err := c.ContentCipher.Encrypt(cek, iv, plaintext, aad)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return iv, ciphertext, tag, nil
+}
+
+func newRegisteredContentCipher(alg jwa.ContentEncryptionAlgorithm, f ContentCipherFactory) (*registeredContentCipher, error) {
+	c, err := f.Create()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to create content cipher for %s: %w`, alg, err)
+	}
+	return &registeredContentCipher{ContentCipher: c}, nil
+}
+
+// newContentCrypt creates the content encrypter for the given algorithm.
+// Content ciphers registered by the user take precedence.
+func newContentCrypt(alg jwa.ContentEncryptionAlgorithm) (*content_crypt.Generic, error) {
+	if f, ok := lookupContentCipher(alg); ok {
+		c, err := newRegisteredContentCipher(alg, f)
+		if err != nil {
+			return nil, err
+		}
+		return content_crypt.NewGenericFromCipher(alg, c, c.TagSize()), nil
+	}
+	return content_crypt.NewGeneric(alg)
+}
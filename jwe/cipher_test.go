@@ -0,0 +1,117 @@
+package jwe_test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/stretchr/testify/require"
+)
+
+// gcmContentCipher implements AES-256-GCM with a 96 bit tag, which
+// is not one of the algorithms defined in RFC7518
+type gcmContentCipher struct{}
+
+const a256GCM96 jwa.ContentEncryptionAlgorithm = "X-A256GCM-96"
+
+func (gcmContentCipher) KeySize() int {
+	return 32
+}
+
+func (gcmContentCipher) TagSize() int {
+	return 12
+}
+
+func (gcmContentCipher) GenerateIV() ([]byte, error) {
+	iv := make([]byte, 12)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
+
+func (c gcmContentCipher) aead(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != c.KeySize() {
+		return nil, fmt.Errorf(`invalid key size %d`, len(cek))
+	}
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCMWithTagSize(block, c.TagSize())
+}
+
+func (c gcmContentCipher) Encrypt(cek, iv, plaintext, aad []byte) ([]byte, []byte, error) {
+	aead, err := c.aead(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	combined := aead.Seal(nil, iv, plaintext, aad)
+	offset := len(combined) - c.TagSize()
+	return combined[:offset], combined[offset:], nil
+}
+
+func (c gcmContentCipher) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := c.aead(cek)
+	if err != nil {
+		return nil, err
+	}
+	combined := make([]byte, 0, len(ciphertext)+len(tag))
+	combined = append(append(combined, ciphertext...), tag...)
+	return aead.Open(nil, iv, combined, aad)
+}
+
+func TestRegisterContentCipher(t *testing.T) {
+	jwe.RegisterContentCipher(a256GCM96, jwe.ContentCipherFactoryFn(func() (jwe.ContentCipher, error) {
+		return gcmContentCipher{}, nil
+	}))
+	defer func() {
+		jwe.UnregisterContentCipher(a256GCM96)
+		jwa.UnregisterContentEncryptionAlgorithm(a256GCM96)
+	}()
+
+	var enc jwa.ContentEncryptionAlgorithm
+	require.NoError(t, enc.Accept(a256GCM96.String()), `registered algorithm should be accepted`)
+
+	rsakey, err := jwxtest.GenerateRsaKey()
+	require.NoError(t, err, `jwxtest.GenerateRsaKey should succeed`)
+	eckey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+	sharedkey := make([]byte, 32)
+	_, err = rand.Read(sharedkey)
+	require.NoError(t, err, `rand.Read should succeed`)
+
+	testcases := []struct {
+		Alg     jwa.KeyEncryptionAlgorithm
+		Encrypt interface{}
+		Decrypt interface{}
+	}{
+		{Alg: jwa.RSA_OAEP, Encrypt: &rsakey.PublicKey, Decrypt: rsakey},
+		{Alg: jwa.A256KW, Encrypt: sharedkey, Decrypt: sharedkey},
+		{Alg: jwa.DIRECT, Encrypt: sharedkey, Decrypt: sharedkey},
+		{Alg: jwa.ECDH_ES, Encrypt: &eckey.PublicKey, Decrypt: eckey},
+	}
+
+	payload := []byte(`Lorem ipsum`)
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Alg.String(), func(t *testing.T) {
+			encrypted, err := jwe.Encrypt(payload, jwe.WithKey(tc.Alg, tc.Encrypt), jwe.WithContentEncryption(a256GCM96))
+			require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+			msg, err := jwe.Parse(encrypted)
+			require.NoError(t, err, `jwe.Parse should succeed`)
+			require.Equal(t, a256GCM96, msg.ProtectedHeaders().ContentEncryption(), `enc should match`)
+			require.Len(t, msg.Tag(), 12, `tag size should match`)
+
+			decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(tc.Alg, tc.Decrypt))
+			require.NoError(t, err, `jwe.Decrypt should succeed`)
+			require.Equal(t, payload, decrypted, `payload should match`)
+		})
+	}
+}
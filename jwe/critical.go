@@ -0,0 +1,87 @@
+package jwe
+
+import (
+	"fmt"
+)
+
+// reservedHeaderNames lists the header parameter names that are defined
+// by RFC7516 and RFC7518. These names may not appear in the "crit"
+// header parameter (RFC7516 Section 4.1.13)
+var reservedHeaderNames = map[string]struct{}{
+	AgreementPartyUInfoKey:    {},
+	AgreementPartyVInfoKey:    {},
+	AlgorithmKey:              {},
+	CompressionKey:            {},
+	ContentEncryptionKey:      {},
+	ContentTypeKey:            {},
+	CriticalKey:               {},
+	EphemeralPublicKeyKey:     {},
+	JWKKey:                    {},
+	JWKSetURLKey:              {},
+	KeyIDKey:                  {},
+	TypeKey:                   {},
+	X509CertChainKey:          {},
+	X509CertThumbprintKey:     {},
+	X509CertThumbprintS256Key: {},
+	X509URLKey:                {},
+	CountKey:                  {},
+	InitializationVectorKey:   {},
+	SaltKey:                   {},
+	TagKey:                    {},
+}
+
+// checkCritical verifies that the "crit" header parameter in the given
+// message satisfies the requirements described in RFC7516 Section 4.1.13.
+//
+// `understood` should contain the names of all extensions that the
+// caller understands and processes.
+func checkCritical(msg *Message, understood map[string]struct{}) error {
+	protected := msg.protectedHeaders
+	var protectedCrit bool
+	if protected != nil {
+		_, protectedCrit = protected.Get(CriticalKey)
+	}
+
+	if unprotected := msg.unprotectedHeaders; unprotected != nil {
+		if _, ok := unprotected.Get(CriticalKey); ok {
+			return fmt.Errorf(`"crit" must be in the protected header`)
+		}
+	}
+
+	// Recipients that were created from compact serialization carry a
+	// copy of the protected header, so we can only tell that "crit"
+	// was given in the per-recipient header if the protected header
+	// does not contain one.
+	if !protectedCrit {
+		for i, recipient := range msg.recipients {
+			hdrs := recipient.Headers()
+			if hdrs == nil {
+				continue
+			}
+			if _, ok := hdrs.Get(CriticalKey); ok {
+				return fmt.Errorf(`"crit" must be in the protected header (found in recipient #%d)`, i+1)
+			}
+		}
+		return nil
+	}
+
+	list := protected.Critical()
+	if len(list) == 0 {
+		return fmt.Errorf(`"crit" must not be an empty list`)
+	}
+
+	for _, name := range list {
+		if _, ok := reservedHeaderNames[name]; ok {
+			return fmt.Errorf(`"crit" must not contain header parameter %q defined by the specifications`, name)
+		}
+
+		if _, ok := understood[name]; !ok {
+			return fmt.Errorf(`"crit" contains extension %q that is not understood`, name)
+		}
+
+		if _, ok := protected.Get(name); !ok {
+			return fmt.Errorf(`header parameter %q listed in "crit" is not present in the protected header`, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package jwe_test
+
+import (
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCritical(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	payload := []byte("Lorem ipsum")
+
+	testcases := []struct {
+		Name    string
+		Crit    []string
+		Extra   map[string]interface{}
+		Options []jwe.DecryptOption
+		Error   bool
+	}{
+		{
+			Name: "no crit",
+		},
+		{
+			Name:  "understood extension",
+			Crit:  []string{"myext"},
+			Extra: map[string]interface{}{"myext": "foo"},
+			Options: []jwe.DecryptOption{
+				jwe.WithCriticalExtensions("myext"),
+			},
+		},
+		{
+			Name:  "extension not understood",
+			Crit:  []string{"myext"},
+			Extra: map[string]interface{}{"myext": "foo"},
+			Error: true,
+		},
+		{
+			Name: "extension not present",
+			Crit: []string{"myext"},
+			Options: []jwe.DecryptOption{
+				jwe.WithCriticalExtensions("myext"),
+			},
+			Error: true,
+		},
+		{
+			Name: "reserved name",
+			Crit: []string{jwe.ContentEncryptionKey},
+			Options: []jwe.DecryptOption{
+				jwe.WithCriticalExtensions(jwe.ContentEncryptionKey),
+			},
+			Error: true,
+		},
+		{
+			Name:  "empty list",
+			Crit:  []string{},
+			Error: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			hdrs := jwe.NewHeaders()
+			if tc.Crit != nil {
+				require.NoError(t, hdrs.Set(jwe.CriticalKey, tc.Crit), `hdrs.Set should succeed`)
+			}
+			for k, v := range tc.Extra {
+				require.NoError(t, hdrs.Set(k, v), `hdrs.Set should succeed`)
+			}
+
+			encrypted, err := jwe.Encrypt(payload, jwe.WithKey(jwa.A128KW, key), jwe.WithProtectedHeaders(hdrs))
+			require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+			options := append([]jwe.DecryptOption{jwe.WithKey(jwa.A128KW, key)}, tc.Options...)
+			decrypted, err := jwe.Decrypt(encrypted, options...)
+			if tc.Error {
+				require.Error(t, err, `jwe.Decrypt should fail`)
+				return
+			}
+			require.NoError(t, err, `jwe.Decrypt should succeed`)
+			require.Equal(t, payload, decrypted, `payload should match`)
+		})
+	}
+
+	t.Run("crit in per-recipient header", func(t *testing.T) {
+		hdrs := jwe.NewHeaders()
+		require.NoError(t, hdrs.Set(jwe.CriticalKey, []string{"myext"}), `hdrs.Set should succeed`)
+		require.NoError(t, hdrs.Set("myext", "foo"), `hdrs.Set should succeed`)
+
+		encrypted, err := jwe.Encrypt(payload,
+			jwe.WithJSON(),
+			jwe.WithKey(jwa.A128KW, key, jwe.WithPerRecipientHeaders(hdrs)),
+			jwe.WithKey(jwa.A128KW, key),
+		)
+		require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+		_, err = jwe.Decrypt(encrypted, jwe.WithKey(jwa.A128KW, key), jwe.WithCriticalExtensions("myext"))
+		require.Error(t, err, `jwe.Decrypt should fail`)
+	})
+}
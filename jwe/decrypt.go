@@ -18,6 +18,7 @@ import (
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/content_crypt"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keyenc"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 // decrypter is responsible for taking various components to decrypt a message.
@@ -36,6 +37,7 @@ type decrypter struct {
 	tag         []byte
 	privkey     interface{}
 	pubkey      interface{}
+	senderkey   interface{}
 	ctalg       jwa.ContentEncryptionAlgorithm
 	keyalg      jwa.KeyEncryptionAlgorithm
 	cipher      content_crypt.Cipher
@@ -115,6 +117,13 @@ func (d *decrypter) PublicKey(pubkey interface{}) *decrypter {
 	return d
 }
 
+// SenderPublicKey sets the public key of the sender to be used in decoding
+// ECDH-1PU based encryptions. The key must be in its "raw" format
+func (d *decrypter) SenderPublicKey(senderkey interface{}) *decrypter {
+	d.senderkey = senderkey
+	return d
+}
+
 func (d *decrypter) Tag(tag []byte) *decrypter {
 	d.tag = tag
 	return d
@@ -122,9 +131,18 @@ func (d *decrypter) Tag(tag []byte) *decrypter {
 
 func (d *decrypter) ContentCipher() (content_crypt.Cipher, error) {
 	if d.cipher == nil {
+		if f, ok := lookupContentCipher(d.ctalg); ok {
+			cipher, err := newRegisteredContentCipher(d.ctalg, f)
+			if err != nil {
+				return nil, err
+			}
+			d.cipher = cipher
+			return d.cipher, nil
+		}
+
 		switch d.ctalg {
-		case jwa.A128GCM, jwa.A192GCM, jwa.A256GCM, jwa.A128CBC_HS256, jwa.A192CBC_HS384, jwa.A256CBC_HS512:
-			cipher, err := cipher.NewAES(d.ctalg)
+		case jwa.A128GCM, jwa.A192GCM, jwa.A256GCM, jwa.A128CBC_HS256, jwa.A192CBC_HS384, jwa.A256CBC_HS512, jwa.C20P, jwa.XC20P:
+			cipher, err := cipher.New(d.ctalg)
 			if err != nil {
 				return nil, fmt.Errorf(`failed to build content cipher for %s: %w`, d.ctalg, err)
 			}
@@ -144,6 +162,12 @@ func (d *decrypter) Decrypt(recipientKey, ciphertext []byte) (plaintext []byte,
 		return
 	}
 
+	return d.DecryptContent(cek, ciphertext)
+}
+
+// DecryptContent decrypts the ciphertext using the content encryption
+// key that has already been decrypted.
+func (d *decrypter) DecryptContent(cek, ciphertext []byte) (plaintext []byte, err error) {
 	cipher, ciphererr := d.ContentCipher()
 	if ciphererr != nil {
 		err = fmt.Errorf(`failed to fetch content crypt cipher: %w`, ciphererr)
@@ -280,8 +304,8 @@ func (d *decrypter) BuildKeyDecrypter() (keyenc.Decrypter, error) {
 		return keyenc.NewAES(alg, sharedkey)
 	case jwa.ECDH_ES, jwa.ECDH_ES_A128KW, jwa.ECDH_ES_A192KW, jwa.ECDH_ES_A256KW:
 		switch d.pubkey.(type) {
-		case x25519.PublicKey:
-			return keyenc.NewECDHESDecrypt(alg, d.ctalg, d.pubkey, d.apu, d.apv, d.privkey), nil
+		case x25519.PublicKey, x448.PublicKey:
+			return keyenc.NewECDHESDecrypt(alg, d.ctalg, cipher.KeySize(), d.pubkey, d.apu, d.apv, d.privkey), nil
 		default:
 			var pubkey ecdsa.PublicKey
 			if err := keyconv.ECDSAPublicKey(&pubkey, d.pubkey); err != nil {
@@ -293,7 +317,29 @@ func (d *decrypter) BuildKeyDecrypter() (keyenc.Decrypter, error) {
 				return nil, fmt.Errorf(`*ecdsa.PrivateKey is required as the key to build %s key decrypter: %w`, alg, err)
 			}
 
-			return keyenc.NewECDHESDecrypt(alg, d.ctalg, &pubkey, d.apu, d.apv, &privkey), nil
+			return keyenc.NewECDHESDecrypt(alg, d.ctalg, cipher.KeySize(), &pubkey, d.apu, d.apv, &privkey), nil
+		}
+	case jwa.ECDH_1PU, jwa.ECDH_1PU_A128KW, jwa.ECDH_1PU_A192KW, jwa.ECDH_1PU_A256KW:
+		switch d.pubkey.(type) {
+		case x25519.PublicKey, x448.PublicKey:
+			return keyenc.NewECDH1PUDecrypt(alg, d.ctalg, cipher.KeySize(), d.pubkey, d.senderkey, d.apu, d.apv, d.tag, d.privkey)
+		default:
+			var pubkey ecdsa.PublicKey
+			if err := keyconv.ECDSAPublicKey(&pubkey, d.pubkey); err != nil {
+				return nil, fmt.Errorf(`*ecdsa.PublicKey is required as the key to build %s key decrypter: %w`, alg, err)
+			}
+
+			var senderkey ecdsa.PublicKey
+			if err := keyconv.ECDSAPublicKey(&senderkey, d.senderkey); err != nil {
+				return nil, fmt.Errorf(`*ecdsa.PublicKey is required as the sender key to build %s key decrypter: %w`, alg, err)
+			}
+
+			var privkey ecdsa.PrivateKey
+			if err := keyconv.ECDSAPrivateKey(&privkey, d.privkey); err != nil {
+				return nil, fmt.Errorf(`*ecdsa.PrivateKey is required as the key to build %s key decrypter: %w`, alg, err)
+			}
+
+			return keyenc.NewECDH1PUDecrypt(alg, d.ctalg, cipher.KeySize(), &pubkey, &senderkey, d.apu, d.apv, d.tag, &privkey)
 		}
 	default:
 		return nil, fmt.Errorf(`unsupported algorithm for key decryption (%s)`, alg)
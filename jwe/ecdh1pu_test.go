@@ -0,0 +1,186 @@
+package jwe_test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
+	"github.com/stretchr/testify/require"
+)
+
+func TestECDH1PU(t *testing.T) {
+	type keypair struct {
+		Private interface{}
+		Public  interface{}
+	}
+
+	generate := func(t *testing.T, crv jwa.EllipticCurveAlgorithm) keypair {
+		t.Helper()
+		switch crv {
+		case jwa.X25519:
+			pub, priv, err := x25519.GenerateKey(rand.Reader)
+			require.NoError(t, err, `x25519.GenerateKey should succeed`)
+			return keypair{Private: priv, Public: pub}
+		case jwa.X448:
+			pub, priv, err := x448.GenerateKey(rand.Reader)
+			require.NoError(t, err, `x448.GenerateKey should succeed`)
+			return keypair{Private: priv, Public: pub}
+		}
+		priv, err := jwxtest.GenerateEcdsaKey(crv)
+		require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+		return keypair{Private: priv, Public: &priv.PublicKey}
+	}
+
+	algorithms := []jwa.KeyEncryptionAlgorithm{
+		jwa.ECDH_1PU,
+		jwa.ECDH_1PU_A128KW,
+		jwa.ECDH_1PU_A192KW,
+		jwa.ECDH_1PU_A256KW,
+	}
+	plaintext := []byte(examplePayload)
+
+	for _, crv := range []jwa.EllipticCurveAlgorithm{jwa.P256, jwa.P384, jwa.P521, jwa.X25519, jwa.X448} {
+		crv := crv
+		t.Run(crv.String(), func(t *testing.T) {
+			recipient := generate(t, crv)
+			sender := generate(t, crv)
+
+			senderPrivate, err := jwk.FromRaw(sender.Private)
+			require.NoError(t, err, `jwk.FromRaw should succeed`)
+			require.NoError(t, senderPrivate.Set(jwk.KeyIDKey, "alice"), `senderPrivate.Set should succeed`)
+			senderPublic, err := jwk.PublicKeyOf(senderPrivate)
+			require.NoError(t, err, `jwk.PublicKeyOf should succeed`)
+			senderSet := jwk.NewSet()
+			require.NoError(t, senderSet.AddKey(senderPublic), `senderSet.AddKey should succeed`)
+
+			for _, alg := range algorithms {
+				alg := alg
+				t.Run(alg.String(), func(t *testing.T) {
+					encrypted, err := jwe.Encrypt(plaintext,
+						jwe.WithKey(alg, recipient.Public),
+						jwe.WithSenderKey(senderPrivate),
+						jwe.WithContentEncryption(jwa.A256CBC_HS512),
+					)
+					require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+					msg, err := jwe.Parse(encrypted)
+					require.NoError(t, err, `jwe.Parse should succeed`)
+					require.Equal(t, "alice", msg.ProtectedHeaders().SenderKeyID(), `"skid" should be populated`)
+					require.NotNil(t, msg.ProtectedHeaders().EphemeralPublicKey(), `"epk" should be populated`)
+
+					decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(alg, recipient.Private), jwe.WithSenderKeySet(senderSet))
+					require.NoError(t, err, `jwe.Decrypt should succeed`)
+					require.Equal(t, plaintext, decrypted, `decrypted payload should match`)
+
+					decrypted, err = jwe.Decrypt(encrypted, jwe.WithKey(alg, recipient.Private), jwe.WithSenderKey(sender.Public))
+					require.NoError(t, err, `jwe.Decrypt should succeed`)
+					require.Equal(t, plaintext, decrypted, `decrypted payload should match`)
+
+					_, err = jwe.Decrypt(encrypted, jwe.WithKey(alg, recipient.Private))
+					require.Error(t, err, `jwe.Decrypt should fail without the sender key`)
+
+					other := generate(t, crv)
+					_, err = jwe.Decrypt(encrypted, jwe.WithKey(alg, recipient.Private), jwe.WithSenderKey(other.Public))
+					require.Error(t, err, `jwe.Decrypt should fail with the wrong sender key`)
+				})
+			}
+		})
+	}
+
+	t.Run("multiple recipients", func(t *testing.T) {
+		sender := generate(t, jwa.X25519)
+		recipients := []keypair{generate(t, jwa.X25519), generate(t, jwa.X25519)}
+
+		encrypted, err := jwe.Encrypt(plaintext,
+			jwe.WithJSON(),
+			jwe.WithKey(jwa.ECDH_1PU_A256KW, recipients[0].Public),
+			jwe.WithKey(jwa.ECDH_1PU_A256KW, recipients[1].Public),
+			jwe.WithSenderKey(sender.Private),
+			jwe.WithContentEncryption(jwa.A128CBC_HS256),
+		)
+		require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+		for i, recipient := range recipients {
+			decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(jwa.ECDH_1PU_A256KW, recipient.Private), jwe.WithSenderKey(sender.Public))
+			require.NoError(t, err, `jwe.Decrypt should succeed for recipient #%d`, i)
+			require.Equal(t, plaintext, decrypted, `decrypted payload should match`)
+		}
+	})
+	t.Run("sender key provider", func(t *testing.T) {
+		sender := generate(t, jwa.P256)
+		recipient := generate(t, jwa.P256)
+
+		hdrs := jwe.NewHeaders()
+		require.NoError(t, hdrs.Set(jwe.SenderKeyIDKey, "did:example:alice#key-1"), `hdrs.Set should succeed`)
+		encrypted, err := jwe.Encrypt(plaintext,
+			jwe.WithKey(jwa.ECDH_1PU, recipient.Public, jwe.WithPerRecipientHeaders(hdrs)),
+			jwe.WithSenderKey(sender.Private),
+		)
+		require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+		var skid string
+		decrypted, err := jwe.Decrypt(encrypted,
+			jwe.WithKey(jwa.ECDH_1PU, recipient.Private),
+			jwe.WithSenderKeyProvider(jwe.SenderKeyProviderFunc(func(_ context.Context, hdrs jwe.Headers) (interface{}, error) {
+				skid = hdrs.SenderKeyID()
+				return sender.Public, nil
+			})),
+		)
+		require.NoError(t, err, `jwe.Decrypt should succeed`)
+		require.Equal(t, plaintext, decrypted, `decrypted payload should match`)
+		require.Equal(t, "did:example:alice#key-1", skid, `"skid" should be passed to the provider`)
+	})
+	t.Run("draft-madden-jose-ecdh-1pu-04 Appendix B", func(t *testing.T) {
+		const src = `{
+  "protected":"eyJhbGciOiJFQ0RILTFQVStBMTI4S1ciLCJlbmMiOiJBMjU2Q0JDLUhTNTEyIiwiYXB1IjoiUVd4cFkyVSIsImFwdiI6IlFtOWlJR0Z1WkNCRGFHRnliR2xsIiwiZXBrIjp7Imt0eSI6Ik9LUCIsImNydiI6IlgyNTUxOSIsIngiOiJrOW9mX2NwQWFqeTBwb1c1Z2FpeFhHczluSGt3ZzFBRnFVQUZhMzlkeUJjIn19",
+  "unprotected":{"jku":"https://alice.example.com/keys.jwks"},
+  "recipients":[
+    {
+      "header":{"kid":"bob-key-2"},
+      "encrypted_key":"pOMVA9_PtoRe7xXW1139NzzN1UhiFoio8lGto9cf0t8PyU-sjNXH8-LIRLycq8CHJQbDwvQeU1cSl55cQ0hGezJu2N9IY0QN"
+    },
+    {
+      "header":{"kid":"2021-05-06"},
+      "encrypted_key":"56GVudgRLIMEElQ7DpXsijJVRSWUSDNdbWkdV3g0GUNq6hcT_GkxwnxlPIWrTXCqRpVKQC8fe4z3PQ2YH2afvjQ28aiCTWFE"
+    }
+  ],
+  "iv":"AAECAwQFBgcICQoLDA0ODw",
+  "ciphertext":"Az2IWsISEMDJvyc5XRL-3-d-RgNBOGolCsxFFoUXFYw",
+  "tag":"HLb4fTlm8spGmij3RyOs2gJ4DpHM4hhVRwdF_hGb3WQ"
+}`
+		alice, err := jwk.ParseKey([]byte(`{"kty":"OKP","crv":"X25519","kid":"Alice","x":"Knbm_BcdQr7WIoz-uqit9M0wbcfEr6y-9UfIZ8QnBD4"}`))
+		require.NoError(t, err, `jwk.ParseKey should succeed`)
+
+		recipients := []string{
+			`{"kty":"OKP","crv":"X25519","kid":"Bob","x":"BT7aR0ItXfeDAldeeOlXL_wXqp-j5FltT0vRSG16kRw","d":"1gDirl_r_Y3-qUa3WXHgEXrrEHngWThU3c9zj9A2uBg"}`,
+			`{"kty":"OKP","crv":"X25519","kid":"Charlie","x":"q-LsvU772uV_2sPJhfAIq-3vnKNVefNoIlvyvg1hrnE","d":"Jcv8gklhMjC0b-lsk5onBbppWAx5ncNtbM63Jr9xBQE"}`,
+		}
+		for _, recipientSrc := range recipients {
+			recipient, err := jwk.ParseKey([]byte(recipientSrc))
+			require.NoError(t, err, `jwk.ParseKey should succeed`)
+
+			decrypted, err := jwe.Decrypt([]byte(src), jwe.WithKey(jwa.ECDH_1PU_A128KW, recipient), jwe.WithSenderKey(alice))
+			require.NoError(t, err, `jwe.Decrypt should succeed for %s`, recipient.KeyID())
+			require.Equal(t, []byte("Three is a magic number."), decrypted, `decrypted payload should match`)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		sender := generate(t, jwa.P256)
+		recipient := generate(t, jwa.P256)
+
+		_, err := jwe.Encrypt(plaintext, jwe.WithKey(jwa.ECDH_1PU, recipient.Public))
+		require.Error(t, err, `jwe.Encrypt should fail without the sender key`)
+
+		_, err = jwe.Encrypt(plaintext, jwe.WithKey(jwa.ECDH_1PU, recipient.Public), jwe.WithSenderKey(generate(t, jwa.P384).Private))
+		require.Error(t, err, `jwe.Encrypt should fail with keys on different curves`)
+
+		_, err = jwe.Encrypt(plaintext, jwe.WithKey(jwa.ECDH_1PU_A256KW, recipient.Public), jwe.WithSenderKey(sender.Private), jwe.WithContentEncryption(jwa.A256GCM))
+		require.Error(t, err, `jwe.Encrypt should fail for key wrapping modes with non AES_CBC_HMAC_SHA2 content encryption`)
+	})
+}
@@ -29,6 +29,7 @@ const (
 	JWKKey                    = "jwk"
 	JWKSetURLKey              = "jku"
 	KeyIDKey                  = "kid"
+	SenderKeyIDKey            = "skid"
 	TypeKey                   = "typ"
 	X509CertChainKey          = "x5c"
 	X509CertThumbprintKey     = "x5t"
@@ -51,6 +52,7 @@ type Headers interface {
 	JWK() jwk.Key
 	JWKSetURL() string
 	KeyID() string
+	SenderKeyID() string
 	Type() string
 	X509CertChain() *cert.Chain
 	X509CertThumbprint() string
@@ -86,6 +88,7 @@ type stdHeaders struct {
 	jwk                    jwk.Key
 	jwkSetURL              *string
 	keyID                  *string
+	senderKeyID            *string
 	typ                    *string
 	x509CertChain          *cert.Chain
 	x509CertThumbprint     *string
@@ -186,6 +189,15 @@ func (h *stdHeaders) KeyID() string {
 	return *(h.keyID)
 }
 
+func (h *stdHeaders) SenderKeyID() string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.senderKeyID == nil {
+		return ""
+	}
+	return *(h.senderKeyID)
+}
+
 func (h *stdHeaders) Type() string {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -265,6 +277,9 @@ func (h *stdHeaders) makePairs() []*HeaderPair {
 	if h.keyID != nil {
 		pairs = append(pairs, &HeaderPair{Key: KeyIDKey, Value: *(h.keyID)})
 	}
+	if h.senderKeyID != nil {
+		pairs = append(pairs, &HeaderPair{Key: SenderKeyIDKey, Value: *(h.senderKeyID)})
+	}
 	if h.typ != nil {
 		pairs = append(pairs, &HeaderPair{Key: TypeKey, Value: *(h.typ)})
 	}
@@ -351,6 +366,11 @@ func (h *stdHeaders) Get(name string) (interface{}, bool) {
 			return nil, false
 		}
 		return *(h.keyID), true
+	case SenderKeyIDKey:
+		if h.senderKeyID == nil {
+			return nil, false
+		}
+		return *(h.senderKeyID), true
 	case TypeKey:
 		if h.typ == nil {
 			return nil, false
@@ -459,6 +479,12 @@ func (h *stdHeaders) setNoLock(name string, value interface{}) error {
 			return nil
 		}
 		return fmt.Errorf(`invalid value for %s key: %T`, KeyIDKey, value)
+	case SenderKeyIDKey:
+		if v, ok := value.(string); ok {
+			h.senderKeyID = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, SenderKeyIDKey, value)
 	case TypeKey:
 		if v, ok := value.(string); ok {
 			h.typ = &v
@@ -524,6 +550,8 @@ func (h *stdHeaders) Remove(key string) error {
 		h.jwkSetURL = nil
 	case KeyIDKey:
 		h.keyID = nil
+	case SenderKeyIDKey:
+		h.senderKeyID = nil
 	case TypeKey:
 		h.typ = nil
 	case X509CertChainKey:
@@ -552,6 +580,7 @@ func (h *stdHeaders) UnmarshalJSON(buf []byte) error {
 	h.jwk = nil
 	h.jwkSetURL = nil
 	h.keyID = nil
+	h.senderKeyID = nil
 	h.typ = nil
 	h.x509CertChain = nil
 	h.x509CertThumbprint = nil
@@ -639,6 +668,10 @@ LOOP:
 				if err := json.AssignNextStringToken(&h.keyID, dec); err != nil {
 					return fmt.Errorf(`failed to decode value for key %s: %w`, KeyIDKey, err)
 				}
+			case SenderKeyIDKey:
+				if err := json.AssignNextStringToken(&h.senderKeyID, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, SenderKeyIDKey, err)
+				}
 			case TypeKey:
 				if err := json.AssignNextStringToken(&h.typ, dec); err != nil {
 					return fmt.Errorf(`failed to decode value for key %s: %w`, TypeKey, err)
@@ -677,7 +710,7 @@ LOOP:
 
 func (h stdHeaders) MarshalJSON() ([]byte, error) {
 	data := make(map[string]interface{})
-	fields := make([]string, 0, 16)
+	fields := make([]string, 0, 17)
 	for _, pair := range h.makePairs() {
 		fields = append(fields, pair.Key.(string))
 		data[pair.Key.(string)] = pair.Value
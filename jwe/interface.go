@@ -3,6 +3,7 @@ package jwe
 import (
 	"github.com/lestrrat-go/iter/mapiter"
 	"github.com/lestrrat-go/jwx/v2/internal/iter"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keygen"
 )
 
@@ -158,3 +159,53 @@ type Visitor = iter.MapVisitor
 type VisitorFunc = iter.MapVisitorFunc
 type HeaderPair = mapiter.Pair
 type Iterator = mapiter.Iterator
+
+// KeyEncrypter is an interface for things that can encrypt content
+// encryption keys. It can be used along with `jwe.RegisterKeyEncrypter()`
+// to provide implementations for key management algorithms that are not
+// supported by this library, or to replace the built-in ones.
+type KeyEncrypter interface {
+	Algorithm() jwa.KeyEncryptionAlgorithm
+
+	// EncryptKey encrypts the content encryption key `cek`. Any header
+	// parameters that are required to decrypt the key later should be
+	// set in `hdrs`, which are the per-recipient headers.
+	EncryptKey(cek []byte, hdrs Headers) ([]byte, error)
+}
+
+// KeyDecrypter is an interface for things that can decrypt content
+// encryption keys. It can be used along with `jwe.RegisterKeyDecrypter()`
+// to provide implementations for key management algorithms that are not
+// supported by this library, or to replace the built-in ones.
+type KeyDecrypter interface {
+	Algorithm() jwa.KeyEncryptionAlgorithm
+
+	// DecryptKey decrypts the encrypted key `enckey`. `hdrs` contains
+	// the protected and the per-recipient headers merged together.
+	DecryptKey(enckey []byte, hdrs Headers) ([]byte, error)
+}
+
+// ContentCipher is an interface for things that can encrypt and decrypt
+// the content of a JWE message using an authenticated encryption scheme.
+// It can be used along with `jwe.RegisterContentCipher()` to provide
+// implementations for content encryption algorithms that are not
+// supported by this library.
+type ContentCipher interface {
+	// KeySize returns the size of the content encryption key in bytes
+	KeySize() int
+
+	// TagSize returns the size of the authentication tag in bytes
+	TagSize() int
+
+	// GenerateIV generates a new initialization vector (nonce) to
+	// be used for encrypting the content.
+	GenerateIV() ([]byte, error)
+
+	// Encrypt encrypts the plaintext using the content encryption key
+	// and the initialization vector, and computes the authentication
+	// tag over the ciphertext and the additional authenticated data.
+	Encrypt(cek, iv, plaintext, aad []byte) (ciphertext []byte, tag []byte, err error)
+
+	// Decrypt verifies the authentication tag and decrypts the ciphertext.
+	Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
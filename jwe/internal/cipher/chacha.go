@@ -0,0 +1,48 @@
+package cipher
+
+import (
+	"crypto/cipher"
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+var chacha = &chachaFetcher{}
+var xchacha = &xchachaFetcher{}
+
+func (f chachaFetcher) Fetch(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf(`cipher: failed to create ChaCha20-Poly1305 cipher: %w`, err)
+	}
+	return aead, nil
+}
+
+func (f xchachaFetcher) Fetch(key []byte) (cipher.AEAD, error) {
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, fmt.Errorf(`cipher: failed to create XChaCha20-Poly1305 cipher: %w`, err)
+	}
+	return aead, nil
+}
+
+// NewChaCha20Poly1305 creates a content cipher for C20P (ChaCha20-Poly1305,
+// 96 bit nonce) or XC20P (XChaCha20-Poly1305, 192 bit nonce).
+func NewChaCha20Poly1305(alg jwa.ContentEncryptionAlgorithm) (*AEADContentCipher, error) {
+	var fetcher Fetcher
+	switch alg {
+	case jwa.C20P:
+		fetcher = chacha
+	case jwa.XC20P:
+		fetcher = xchacha
+	default:
+		return nil, fmt.Errorf("failed to create ChaCha20-Poly1305 content cipher: invalid algorithm (%s)", alg)
+	}
+
+	return &AEADContentCipher{
+		keysize: chacha20poly1305.KeySize,
+		tagsize: chacha20poly1305.Overhead,
+		fetch:   fetcher,
+	}, nil
+}
@@ -34,15 +34,25 @@ func (f cbcFetcher) Fetch(key []byte) (cipher.AEAD, error) {
 	return aead, nil
 }
 
-func (c AesContentCipher) KeySize() int {
+func (c AEADContentCipher) KeySize() int {
 	return c.keysize
 }
 
-func (c AesContentCipher) TagSize() int {
+func (c AEADContentCipher) TagSize() int {
 	return c.tagsize
 }
 
-func NewAES(alg jwa.ContentEncryptionAlgorithm) (*AesContentCipher, error) {
+// New creates a new content cipher for the given content encryption algorithm
+func New(alg jwa.ContentEncryptionAlgorithm) (*AEADContentCipher, error) {
+	switch alg {
+	case jwa.C20P, jwa.XC20P:
+		return NewChaCha20Poly1305(alg)
+	default:
+		return NewAES(alg)
+	}
+}
+
+func NewAES(alg jwa.ContentEncryptionAlgorithm) (*AEADContentCipher, error) {
 	var keysize int
 	var tagsize int
 	var fetcher Fetcher
@@ -75,14 +85,14 @@ func NewAES(alg jwa.ContentEncryptionAlgorithm) (*AesContentCipher, error) {
 		return nil, fmt.Errorf("failed to create AES content cipher: invalid algorithm (%s)", alg)
 	}
 
-	return &AesContentCipher{
+	return &AEADContentCipher{
 		keysize: keysize,
 		tagsize: tagsize,
 		fetch:   fetcher,
 	}, nil
 }
 
-func (c AesContentCipher) Encrypt(cek, plaintext, aad []byte) (iv, ciphertxt, tag []byte, err error) {
+func (c AEADContentCipher) Encrypt(cek, plaintext, aad []byte) (iv, ciphertxt, tag []byte, err error) {
 	var aead cipher.AEAD
 	aead, err = c.fetch.Fetch(cek)
 	if err != nil {
@@ -127,7 +137,7 @@ func (c AesContentCipher) Encrypt(cek, plaintext, aad []byte) (iv, ciphertxt, ta
 	return
 }
 
-func (c AesContentCipher) Decrypt(cek, iv, ciphertxt, tag, aad []byte) (plaintext []byte, err error) {
+func (c AEADContentCipher) Decrypt(cek, iv, ciphertxt, tag, aad []byte) (plaintext []byte, err error) {
 	aead, err := c.fetch.Fetch(cek)
 	if err != nil {
 		return nil, fmt.Errorf(`failed to fetch AEAD data: %w`, err)
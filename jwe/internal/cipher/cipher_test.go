@@ -1,11 +1,14 @@
 package cipher_test
 
 import (
+	"encoding/hex"
 	"testing"
 
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/cipher"
+	"github.com/lestrrat-go/jwx/v2/jwe/internal/keygen"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAES(t *testing.T) {
@@ -25,3 +28,73 @@ func TestAES(t *testing.T) {
 		t.Logf("keysize = %d", c.KeySize())
 	}
 }
+
+func TestChaCha20Poly1305(t *testing.T) {
+	const plaintext = "Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, sunscreen would be it."
+	testcases := []struct {
+		Name       string
+		Algorithm  jwa.ContentEncryptionAlgorithm
+		Key        string
+		Nonce      string
+		AAD        string
+		Ciphertext string
+		Tag        string
+	}{
+		{
+			// RFC 8439, Section 2.8.2
+			Name:       "RFC 8439 2.8.2",
+			Algorithm:  jwa.C20P,
+			Key:        "808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f",
+			Nonce:      "070000004041424344454647",
+			AAD:        "50515253c0c1c2c3c4c5c6c7",
+			Ciphertext: "d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d63dbea45e8ca9671282fafb69da92728b1a71de0a9e060b2905d6a5b67ecd3b3692ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc3ff4def08e4b7a9de576d26586cec64b6116",
+			Tag:        "1ae10b594f09e26a7e902ecbd0600691",
+		},
+		{
+			// draft-irtf-cfrg-xchacha-03, Appendix A.3.1
+			Name:       "draft-irtf-cfrg-xchacha A.3.1",
+			Algorithm:  jwa.XC20P,
+			Key:        "808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f",
+			Nonce:      "404142434445464748494a4b4c4d4e4f5051525354555657",
+			AAD:        "50515253c0c1c2c3c4c5c6c7",
+			Ciphertext: "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff921f9664c97637da9768812f615c68b13b52e",
+			Tag:        "c0875924c1c7987947deafd8780acf49",
+		},
+	}
+
+	decode := func(t *testing.T, s string) []byte {
+		t.Helper()
+		b, err := hex.DecodeString(s)
+		require.NoError(t, err, `hex.DecodeString should succeed`)
+		return b
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			c, err := cipher.New(tc.Algorithm)
+			require.NoError(t, err, `cipher.New should succeed`)
+			require.Equal(t, 32, c.KeySize(), `key size should be 32`)
+			require.Equal(t, 16, c.TagSize(), `tag size should be 16`)
+
+			key := decode(t, tc.Key)
+			nonce := decode(t, tc.Nonce)
+			aad := decode(t, tc.AAD)
+			c.NonceGenerator = keygen.Static(nonce)
+
+			iv, ciphertext, tag, err := c.Encrypt(key, []byte(plaintext), aad)
+			require.NoError(t, err, `c.Encrypt should succeed`)
+			require.Equal(t, nonce, iv, `iv should match`)
+			require.Equal(t, tc.Ciphertext, hex.EncodeToString(ciphertext), `ciphertext should match`)
+			require.Equal(t, tc.Tag, hex.EncodeToString(tag), `tag should match`)
+
+			decrypted, err := c.Decrypt(key, iv, ciphertext, tag, aad)
+			require.NoError(t, err, `c.Decrypt should succeed`)
+			require.Equal(t, plaintext, string(decrypted), `plaintext should match`)
+
+			tag[0] ^= 0x1
+			_, err = c.Decrypt(key, iv, ciphertext, tag, aad)
+			require.Error(t, err, `c.Decrypt should fail with a modified tag`)
+		})
+	}
+}
@@ -24,9 +24,12 @@ type Fetcher interface {
 
 type gcmFetcher struct{}
 type cbcFetcher struct{}
+type chachaFetcher struct{}
+type xchachaFetcher struct{}
 
-// AesContentCipher represents a cipher based on AES
-type AesContentCipher struct {
+// AEADContentCipher represents a cipher based on an AEAD construction,
+// such as AES-GCM, AES-CBC + HMAC-SHA2, and (X)ChaCha20-Poly1305
+type AEADContentCipher struct {
 	NonceGenerator keygen.Generator
 	fetch          Fetcher
 	keysize        int
@@ -25,9 +25,9 @@ func (c Generic) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
 }
 
 func NewGeneric(alg jwa.ContentEncryptionAlgorithm) (*Generic, error) {
-	c, err := cipher.NewAES(alg)
+	c, err := cipher.New(alg)
 	if err != nil {
-		return nil, fmt.Errorf(`aes crypt: failed to create content cipher: %w`, err)
+		return nil, fmt.Errorf(`failed to create content cipher: %w`, err)
 	}
 
 	return &Generic{
@@ -38,6 +38,17 @@ func NewGeneric(alg jwa.ContentEncryptionAlgorithm) (*Generic, error) {
 	}, nil
 }
 
+// NewGenericFromCipher creates a Generic content encrypter using the
+// given content cipher.
+func NewGenericFromCipher(alg jwa.ContentEncryptionAlgorithm, c cipher.ContentCipher, tagsize int) *Generic {
+	return &Generic{
+		alg:     alg,
+		cipher:  c,
+		keysize: c.KeySize(),
+		tagsize: tagsize,
+	}
+}
+
 func (c Generic) KeySize() int {
 	return c.keysize
 }
@@ -58,12 +58,39 @@ type ECDHESEncrypt struct {
 type ECDHESDecrypt struct {
 	keyalg     jwa.KeyEncryptionAlgorithm
 	contentalg jwa.ContentEncryptionAlgorithm
+	keysize    int
 	apu        []byte
 	apv        []byte
 	privkey    interface{}
 	pubkey     interface{}
 }
 
+// ECDH1PUEncrypt encrypts content encryption keys using ECDH-1PU.
+type ECDH1PUEncrypt struct {
+	algorithm jwa.KeyEncryptionAlgorithm
+	enc       jwa.ContentEncryptionAlgorithm
+	keyID     string
+	keysize   int
+	apu       []byte
+	apv       []byte
+	pubkey    interface{}
+	senderkey interface{}
+	ephemeral interface{}
+}
+
+// ECDH1PUDecrypt decrypts keys using ECDH-1PU.
+type ECDH1PUDecrypt struct {
+	keyalg     jwa.KeyEncryptionAlgorithm
+	contentalg jwa.ContentEncryptionAlgorithm
+	keysize    int
+	apu        []byte
+	apv        []byte
+	tag        []byte
+	privkey    interface{}
+	pubkey     interface{}
+	senderkey  interface{}
+}
+
 // RSAOAEPEncrypt encrypts keys using RSA OAEP algorithm
 type RSAOAEPEncrypt struct {
 	alg    jwa.KeyEncryptionAlgorithm
@@ -21,10 +21,10 @@ import (
 
 	"github.com/lestrrat-go/jwx/v2/internal/ecutil"
 	"github.com/lestrrat-go/jwx/v2/jwa"
-	contentcipher "github.com/lestrrat-go/jwx/v2/jwe/internal/cipher"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/concatkdf"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keygen"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 func NewNoop(alg jwa.KeyEncryptionAlgorithm, sharedkey []byte) (*Noop, error) {
@@ -218,6 +218,8 @@ func NewECDHESEncrypt(alg jwa.KeyEncryptionAlgorithm, enc jwa.ContentEncryptionA
 		generator, err = keygen.NewEcdhes(alg, enc, keysize, key, apu, apv)
 	case x25519.PublicKey:
 		generator, err = keygen.NewX25519(alg, enc, keysize, key)
+	case x448.PublicKey:
+		generator, err = keygen.NewX448(alg, enc, keysize, key)
 	default:
 		return nil, fmt.Errorf("unexpected key type %T", keyif)
 	}
@@ -276,10 +278,11 @@ func (kw ECDHESEncrypt) Encrypt(cek []byte) (keygen.ByteSource, error) {
 }
 
 // NewECDHESDecrypt creates a new key decrypter using ECDH-ES
-func NewECDHESDecrypt(keyalg jwa.KeyEncryptionAlgorithm, contentalg jwa.ContentEncryptionAlgorithm, pubkey interface{}, apu, apv []byte, privkey interface{}) *ECDHESDecrypt {
+func NewECDHESDecrypt(keyalg jwa.KeyEncryptionAlgorithm, contentalg jwa.ContentEncryptionAlgorithm, keysize int, pubkey interface{}, apu, apv []byte, privkey interface{}) *ECDHESDecrypt {
 	return &ECDHESDecrypt{
 		keyalg:     keyalg,
 		contentalg: contentalg,
+		keysize:    keysize,
 		apu:        apu,
 		apv:        apv,
 		privkey:    privkey,
@@ -304,6 +307,16 @@ func DeriveZ(privkeyif interface{}, pubkeyif interface{}) ([]byte, error) {
 			return nil, fmt.Errorf(`public key must be x25519.PublicKey, was: %T`, pubkeyif)
 		}
 		return curve25519.X25519(privkey.Seed(), pubkey)
+	case x448.PrivateKey:
+		privkey, ok := privkeyif.(x448.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf(`private key must be x448.PrivateKey, was: %T`, privkeyif)
+		}
+		pubkey, ok := pubkeyif.(x448.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf(`public key must be x448.PublicKey, was: %T`, pubkeyif)
+		}
+		return x448.SharedSecret(privkey, pubkey)
 	default:
 		privkey, ok := privkeyif.(*ecdsa.PrivateKey)
 		if !ok {
@@ -352,12 +365,12 @@ func (kw ECDHESDecrypt) Decrypt(enckey []byte) ([]byte, error) {
 
 	switch kw.keyalg {
 	case jwa.ECDH_ES:
-		// Create a content cipher from the content encryption algorithm
-		c, err := contentcipher.NewAES(kw.contentalg)
-		if err != nil {
-			return nil, fmt.Errorf(`failed to create content cipher for %s: %w`, kw.contentalg, err)
+		// In direct key agreement mode, the derived key is the CEK,
+		// so its size is determined by the content encryption algorithm
+		if kw.keysize <= 0 {
+			return nil, fmt.Errorf(`invalid content key size for %s`, kw.contentalg)
 		}
-		keysize = uint32(c.KeySize())
+		keysize = uint32(kw.keysize)
 		algBytes = []byte(kw.contentalg.String())
 	case jwa.ECDH_ES_A128KW:
 		keysize = 16
@@ -387,6 +400,285 @@ func (kw ECDHESDecrypt) Decrypt(enckey []byte) ([]byte, error) {
 	return Unwrap(block, enckey)
 }
 
+// ecdh1puKeySize returns the size of the key derived by ECDH-1PU.
+// For Direct Key Agreement mode, this is the size of the content
+// encryption key.
+func ecdh1puKeySize(alg jwa.KeyEncryptionAlgorithm, enc jwa.ContentEncryptionAlgorithm, keysize int) (int, error) {
+	switch alg {
+	case jwa.ECDH_1PU:
+		if keysize <= 0 {
+			return 0, fmt.Errorf(`invalid content key size for %s`, enc)
+		}
+		return keysize, nil
+	}
+
+	// Key Agreement with Key Wrapping mode includes the authentication tag
+	// in the KDF, which is only meaningful for AES_CBC_HMAC_SHA2 algorithms
+	// (draft-madden-jose-ecdh-1pu-04, Section 2.1)
+	switch enc {
+	case jwa.A128CBC_HS256, jwa.A192CBC_HS384, jwa.A256CBC_HS512:
+	default:
+		return 0, fmt.Errorf(`%s can only be used with AES_CBC_HMAC_SHA2 content encryption algorithms (got %s)`, alg, enc)
+	}
+
+	switch alg {
+	case jwa.ECDH_1PU_A128KW:
+		return 16, nil
+	case jwa.ECDH_1PU_A192KW:
+		return 24, nil
+	case jwa.ECDH_1PU_A256KW:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("invalid ECDH-1PU key wrap algorithm (%s)", alg)
+	}
+}
+
+// DeriveECDH1PU derives a key using the shared secrets Ze (ephemeral-static)
+// and Zs (static-static) as described in draft-madden-jose-ecdh-1pu-04.
+// For Key Agreement with Key Wrapping mode, `tag` must contain
+// the JWE authentication tag.
+func DeriveECDH1PU(alg, apu, apv, ze, zs []byte, keysize uint32, tag []byte) ([]byte, error) {
+	z := make([]byte, len(ze)+len(zs))
+	copy(z, ze)
+	copy(z[len(ze):], zs)
+
+	pubinfo := make([]byte, 4)
+	binary.BigEndian.PutUint32(pubinfo, keysize*8)
+	if len(tag) > 0 {
+		cctag := make([]byte, 4+len(tag))
+		binary.BigEndian.PutUint32(cctag, uint32(len(tag)))
+		copy(cctag[4:], tag)
+		pubinfo = append(pubinfo, cctag...)
+	}
+
+	kdf := concatkdf.New(crypto.SHA256, alg, z, apu, apv, pubinfo, []byte{})
+	key := make([]byte, keysize)
+	if _, err := kdf.Read(key); err != nil {
+		return nil, fmt.Errorf(`failed to read kdf: %w`, err)
+	}
+	return key, nil
+}
+
+// NewECDH1PUEncrypt creates a new key encrypter based on ECDH-1PU.
+// `pubkey` is the public key of the recipient, and `senderkey` is the
+// private key of the sender. Both must be either *ecdsa.PublicKey and
+// *ecdsa.PrivateKey on the same curve, x25519.PublicKey and
+// x25519.PrivateKey, or x448.PublicKey and x448.PrivateKey.
+func NewECDH1PUEncrypt(alg jwa.KeyEncryptionAlgorithm, enc jwa.ContentEncryptionAlgorithm, keysize int, pubkey, senderkey interface{}, apu, apv []byte) (*ECDH1PUEncrypt, error) {
+	keysize, err := ecdh1puKeySize(alg, enc, keysize)
+	if err != nil {
+		return nil, err
+	}
+
+	var ephemeral interface{}
+	switch pubkey := pubkey.(type) {
+	case *ecdsa.PublicKey:
+		sender, ok := senderkey.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf(`sender key must be *ecdsa.PrivateKey, was: %T`, senderkey)
+		}
+		if sender.Curve != pubkey.Curve {
+			return nil, fmt.Errorf(`sender key and recipient key must be on the same curve`)
+		}
+		v, err := ecdsa.GenerateKey(pubkey.Curve, rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to generate ephemeral key for ECDH-1PU: %w`, err)
+		}
+		ephemeral = v
+	case x25519.PublicKey:
+		if _, ok := senderkey.(x25519.PrivateKey); !ok {
+			return nil, fmt.Errorf(`sender key must be x25519.PrivateKey, was: %T`, senderkey)
+		}
+		_, v, err := x25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to generate ephemeral key for ECDH-1PU: %w`, err)
+		}
+		ephemeral = v
+	case x448.PublicKey:
+		if _, ok := senderkey.(x448.PrivateKey); !ok {
+			return nil, fmt.Errorf(`sender key must be x448.PrivateKey, was: %T`, senderkey)
+		}
+		_, v, err := x448.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to generate ephemeral key for ECDH-1PU: %w`, err)
+		}
+		ephemeral = v
+	default:
+		return nil, fmt.Errorf("unexpected key type %T", pubkey)
+	}
+
+	return &ECDH1PUEncrypt{
+		algorithm: alg,
+		enc:       enc,
+		keysize:   keysize,
+		apu:       apu,
+		apv:       apv,
+		pubkey:    pubkey,
+		senderkey: senderkey,
+		ephemeral: ephemeral,
+	}, nil
+}
+
+// Algorithm returns the key encryption algorithm being used
+func (kw ECDH1PUEncrypt) Algorithm() jwa.KeyEncryptionAlgorithm {
+	return kw.algorithm
+}
+
+func (kw *ECDH1PUEncrypt) SetKeyID(v string) {
+	kw.keyID = v
+}
+
+// KeyID returns the key ID associated with this encrypter
+func (kw ECDH1PUEncrypt) KeyID() string {
+	return kw.keyID
+}
+
+// EphemeralPublicKey returns the public portion of the ephemeral key
+// generated for this encrypter, which must be sent as the "epk" header
+func (kw ECDH1PUEncrypt) EphemeralPublicKey() interface{} {
+	switch ephemeral := kw.ephemeral.(type) {
+	case *ecdsa.PrivateKey:
+		return &ephemeral.PublicKey
+	case x25519.PrivateKey:
+		return ephemeral.Public()
+	case x448.PrivateKey:
+		return ephemeral.Public()
+	default:
+		return nil
+	}
+}
+
+// Encrypt returns the content encryption key derived using ECDH-1PU
+// in Direct Key Agreement mode. For Key Agreement with Key Wrapping mode,
+// use EncryptWithTag instead.
+func (kw ECDH1PUEncrypt) Encrypt(cek []byte) (keygen.ByteSource, error) {
+	if kw.algorithm != jwa.ECDH_1PU {
+		return nil, fmt.Errorf(`%s requires the authentication tag to encrypt the key`, kw.algorithm)
+	}
+	return kw.EncryptWithTag(cek, nil)
+}
+
+// EncryptWithTag encrypts the content encryption key using ECDH-1PU.
+// `tag` is the authentication tag produced by the content encryption,
+// and is required for the Key Agreement with Key Wrapping mode.
+func (kw ECDH1PUEncrypt) EncryptWithTag(cek, tag []byte) (keygen.ByteSource, error) {
+	epk := kw.EphemeralPublicKey()
+
+	ze, err := DeriveZ(kw.ephemeral, kw.pubkey)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to determine Ze: %w`, err)
+	}
+	zs, err := DeriveZ(kw.senderkey, kw.pubkey)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to determine Zs: %w`, err)
+	}
+
+	var algBytes []byte
+	if kw.algorithm == jwa.ECDH_1PU {
+		algBytes = []byte(kw.enc.String())
+		tag = nil
+	} else {
+		if len(tag) == 0 {
+			return nil, fmt.Errorf(`%s requires the authentication tag to encrypt the key`, kw.algorithm)
+		}
+		algBytes = []byte(kw.algorithm.String())
+	}
+
+	key, err := DeriveECDH1PU(algBytes, kw.apu, kw.apv, ze, zs, uint32(kw.keysize), tag)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to derive ECDH-1PU encryption key: %w`, err)
+	}
+
+	if kw.algorithm == jwa.ECDH_1PU {
+		return keygen.ByteWithECPublicKey{
+			PublicKey: epk,
+			ByteKey:   keygen.ByteKey(key),
+		}, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate cipher from derived key: %w`, err)
+	}
+
+	jek, err := Wrap(block, cek)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to wrap data: %w`, err)
+	}
+
+	return keygen.ByteWithECPublicKey{
+		PublicKey: epk,
+		ByteKey:   keygen.ByteKey(jek),
+	}, nil
+}
+
+// NewECDH1PUDecrypt creates a new key decrypter using ECDH-1PU.
+// `pubkey` is the ephemeral public key ("epk"), `senderkey` is the
+// public key of the sender, and `privkey` is the private key of the
+// recipient. `tag` is the authentication tag of the JWE message, which
+// is used in Key Agreement with Key Wrapping mode.
+func NewECDH1PUDecrypt(keyalg jwa.KeyEncryptionAlgorithm, contentalg jwa.ContentEncryptionAlgorithm, keysize int, pubkey, senderkey interface{}, apu, apv, tag []byte, privkey interface{}) (*ECDH1PUDecrypt, error) {
+	keysize, err := ecdh1puKeySize(keyalg, contentalg, keysize)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ECDH1PUDecrypt{
+		keyalg:     keyalg,
+		contentalg: contentalg,
+		keysize:    keysize,
+		apu:        apu,
+		apv:        apv,
+		tag:        tag,
+		privkey:    privkey,
+		pubkey:     pubkey,
+		senderkey:  senderkey,
+	}, nil
+}
+
+// Algorithm returns the key encryption algorithm being used
+func (kw ECDH1PUDecrypt) Algorithm() jwa.KeyEncryptionAlgorithm {
+	return kw.keyalg
+}
+
+// Decrypt decrypts the encrypted key using ECDH-1PU
+func (kw ECDH1PUDecrypt) Decrypt(enckey []byte) ([]byte, error) {
+	ze, err := DeriveZ(kw.privkey, kw.pubkey)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to determine Ze: %w`, err)
+	}
+	zs, err := DeriveZ(kw.privkey, kw.senderkey)
+	if err != nil {
+		return nil, fmt.Errorf(`unable to determine Zs: %w`, err)
+	}
+
+	var algBytes []byte
+	var tag []byte
+	if kw.keyalg == jwa.ECDH_1PU {
+		algBytes = []byte(kw.contentalg.String())
+	} else {
+		algBytes = []byte(kw.keyalg.String())
+		tag = kw.tag
+	}
+
+	key, err := DeriveECDH1PU(algBytes, kw.apu, kw.apv, ze, zs, uint32(kw.keysize), tag)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to derive ECDH-1PU encryption key: %w`, err)
+	}
+
+	// ECDH-1PU does not wrap keys in Direct Key Agreement mode
+	if kw.keyalg == jwa.ECDH_1PU {
+		return key, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to create cipher for ECDH-1PU key wrap: %w`, err)
+	}
+
+	return Unwrap(block, enckey)
+}
+
 // NewRSAOAEPEncrypt creates a new key encrypter using RSA OAEP
 func NewRSAOAEPEncrypt(alg jwa.KeyEncryptionAlgorithm, pubkey *rsa.PublicKey) (*RSAOAEPEncrypt, error) {
 	switch alg {
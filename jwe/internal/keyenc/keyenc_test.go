@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/ecdsa"
+	"encoding/base64"
 	"encoding/hex"
 	"testing"
 
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keyenc"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func mustHexDecode(s string) []byte {
@@ -128,6 +130,131 @@ func TestDeriveECDHES(t *testing.T) {
 	}
 }
 
+func TestDeriveECDH1PU(t *testing.T) {
+	// Test vectors from draft-madden-jose-ecdh-1pu-04
+	rawKey := func(t *testing.T, src string) interface{} {
+		t.Helper()
+		key, err := jwk.ParseKey([]byte(src))
+		require.NoError(t, err, `jwk.ParseKey should succeed`)
+		var raw interface{}
+		require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+		return raw
+	}
+
+	t.Run("Appendix A", func(t *testing.T) {
+		// ECDH-1PU direct key agreement using P-256 and A256GCM
+		const aliceKeySrc = `{"kty":"EC",
+      "crv":"P-256",
+      "x":"WKn-ZIGevcwGIyyrzFoZNBdaq9_TsqzGl96oc0CWuis",
+      "y":"y77t-RvAHRKTsSGdIYUfweuOvwrvDD-Q3Hv5J0fSKbE",
+      "d":"Hndv7ZZjs_ke8o9zXYo3iq-Yr8SewI5vrqd0pAvEPqg"
+     }`
+		const bobKeySrc = `{"kty":"EC",
+      "crv":"P-256",
+      "x":"weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ",
+      "y":"e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck",
+      "d":"VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"
+     }`
+		const ephemeralKeySrc = `{"kty":"EC",
+      "crv":"P-256",
+      "x":"gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+      "y":"SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+      "d":"0_NxaRPUMQoAJt50Gz8YiTr8gRTwyEaCumd-MToTmIo"
+     }`
+
+		alice := rawKey(t, aliceKeySrc).(*ecdsa.PrivateKey)
+		bob := rawKey(t, bobKeySrc).(*ecdsa.PrivateKey)
+		ephemeral := rawKey(t, ephemeralKeySrc).(*ecdsa.PrivateKey)
+
+		expectedZe := mustHexDecode(`9e56d91d817135d372834283bf84269cfb316ea3da806a48f6daa7798cfe90c4`)
+		expectedZs := mustHexDecode(`e3ca3474384c9f62b30bfd4c688b3e7d4110a1b4badc3cc54ef7b81241efd50d`)
+		expectedKey := mustHexDecode(`6caf13723d14850ad4b42cd6dde935bffd2fff00a9ba70de05c203a5e1722ca7`)
+
+		// Alice (sender) side
+		ze, err := keyenc.DeriveZ(ephemeral, &bob.PublicKey)
+		require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+		require.Equal(t, expectedZe, ze, `Ze should match`)
+		zs, err := keyenc.DeriveZ(alice, &bob.PublicKey)
+		require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+		require.Equal(t, expectedZs, zs, `Zs should match`)
+
+		// Bob (recipient) side
+		ze, err = keyenc.DeriveZ(bob, &ephemeral.PublicKey)
+		require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+		require.Equal(t, expectedZe, ze, `Ze should match`)
+		zs, err = keyenc.DeriveZ(bob, &alice.PublicKey)
+		require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+		require.Equal(t, expectedZs, zs, `Zs should match`)
+
+		key, err := keyenc.DeriveECDH1PU([]byte("A256GCM"), []byte("Alice"), []byte("Bob"), ze, zs, 32, nil)
+		require.NoError(t, err, `keyenc.DeriveECDH1PU should succeed`)
+		require.Equal(t, expectedKey, key, `derived key should match`)
+	})
+	t.Run("Appendix B", func(t *testing.T) {
+		// ECDH-1PU+A128KW using X25519 and A256CBC-HS512, with two recipients
+		const aliceKeySrc = `{"kty":"OKP","crv":"X25519","x":"Knbm_BcdQr7WIoz-uqit9M0wbcfEr6y-9UfIZ8QnBD4"}`
+		const ephemeralKeySrc = `{"kty":"OKP","crv":"X25519","x":"k9of_cpAajy0poW5gaixXGs9nHkwg1AFqUAFa39dyBc"}`
+
+		alice := rawKey(t, aliceKeySrc)
+		ephemeral := rawKey(t, ephemeralKeySrc)
+		tag, err := base64.RawURLEncoding.DecodeString(`HLb4fTlm8spGmij3RyOs2gJ4DpHM4hhVRwdF_hGb3WQ`)
+		require.NoError(t, err, `base64 decode should succeed`)
+		cek := mustHexDecode(`fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0efeeedecebeae9e8e7e6e5e4e3e2e1e0dfdedddcdbdad9d8d7d6d5d4d3d2d1d0cfcecdcccbcac9c8c7c6c5c4c3c2c1c0`)
+
+		testcases := []struct {
+			Name         string
+			KeySrc       string
+			Ze           string
+			Zs           string
+			Kek          string
+			EncryptedKey string
+		}{
+			{
+				Name:         "Bob",
+				KeySrc:       `{"kty":"OKP","crv":"X25519","x":"BT7aR0ItXfeDAldeeOlXL_wXqp-j5FltT0vRSG16kRw","d":"1gDirl_r_Y3-qUa3WXHgEXrrEHngWThU3c9zj9A2uBg"}`,
+				Ze:           `32810896e0fe4d570ed1acfcedf67117dc194ed5daac21d8ff7af3244694897f`,
+				Zs:           `2157612c9048edfae77cb2e4237140605967c05c7f77a48eeaf2cf29a5737c4a`,
+				Kek:          `df4c37a0668306a11e3d6b0074b5d8df`,
+				EncryptedKey: `pOMVA9_PtoRe7xXW1139NzzN1UhiFoio8lGto9cf0t8PyU-sjNXH8-LIRLycq8CHJQbDwvQeU1cSl55cQ0hGezJu2N9IY0QN`,
+			},
+			{
+				Name:         "Charlie",
+				KeySrc:       `{"kty":"OKP","crv":"X25519","x":"q-LsvU772uV_2sPJhfAIq-3vnKNVefNoIlvyvg1hrnE","d":"Jcv8gklhMjC0b-lsk5onBbppWAx5ncNtbM63Jr9xBQE"}`,
+				Ze:           `89dcfe4c37c1dc0271f346b5b3b19c3b705ca2a72f9a237785c34406fcb75f10`,
+				Zs:           `78fe63fc661cf8d18f92a8422a6418e4ed5e20a9168185fdeedca1c3d8e6a61c`,
+				Kek:          `57d8126f1b7ec4ccb0584dac03cb27cc`,
+				EncryptedKey: `56GVudgRLIMEElQ7DpXsijJVRSWUSDNdbWkdV3g0GUNq6hcT_GkxwnxlPIWrTXCqRpVKQC8fe4z3PQ2YH2afvjQ28aiCTWFE`,
+			},
+		}
+
+		for _, tc := range testcases {
+			tc := tc
+			t.Run(tc.Name, func(t *testing.T) {
+				recipient := rawKey(t, tc.KeySrc)
+
+				ze, err := keyenc.DeriveZ(recipient, ephemeral)
+				require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+				require.Equal(t, mustHexDecode(tc.Ze), ze, `Ze should match`)
+				zs, err := keyenc.DeriveZ(recipient, alice)
+				require.NoError(t, err, `keyenc.DeriveZ should succeed`)
+				require.Equal(t, mustHexDecode(tc.Zs), zs, `Zs should match`)
+
+				kek, err := keyenc.DeriveECDH1PU([]byte("ECDH-1PU+A128KW"), []byte("Alice"), []byte("Bob and Charlie"), ze, zs, 16, tag)
+				require.NoError(t, err, `keyenc.DeriveECDH1PU should succeed`)
+				require.Equal(t, mustHexDecode(tc.Kek), kek, `derived key should match`)
+
+				encryptedKey, err := base64.RawURLEncoding.DecodeString(tc.EncryptedKey)
+				require.NoError(t, err, `base64 decode should succeed`)
+				block, err := aes.NewCipher(kek)
+				require.NoError(t, err, `aes.NewCipher should succeed`)
+				unwrapped, err := keyenc.Unwrap(block, encryptedKey)
+				require.NoError(t, err, `keyenc.Unwrap should succeed`)
+				require.Equal(t, cek, unwrapped, `unwrapped key should match`)
+			})
+		}
+	})
+}
+
 func TestKeyWrap(t *testing.T) {
 	// stolen from go-jose
 	// Test vectors from: http://csrc.nist.gov/groups/ST/toolkit/documents/kms/key-wrap.pdf
@@ -5,6 +5,7 @@ import (
 
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 type Generator interface {
@@ -38,6 +39,14 @@ type X25519 struct {
 	pubkey    x25519.PublicKey
 }
 
+// X448 generates keys using ECDH-ES algorithm / X448 curve
+type X448 struct {
+	algorithm jwa.KeyEncryptionAlgorithm
+	enc       jwa.ContentEncryptionAlgorithm
+	keysize   int
+	pubkey    x448.PublicKey
+}
+
 // ByteKey is a generated key that only has the key's byte buffer
 // as its instance data. If a key needs to do more, such as providing
 // values to be set in a JWE header, that key type wraps a ByteKey
@@ -22,6 +22,7 @@ import (
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keyenc"
 	"github.com/lestrrat-go/jwx/v2/jwe/internal/keygen"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 const (
@@ -38,12 +39,23 @@ var _ = fmtMax
 var registry = json.NewRegistry()
 
 type recipientBuilder struct {
-	alg     jwa.KeyEncryptionAlgorithm
-	key     interface{}
-	headers Headers
+	alg       jwa.KeyEncryptionAlgorithm
+	key       interface{}
+	senderKey interface{}
+	headers   Headers
+
+	// wrapWithTag is set when the encrypted key can only be computed
+	// after the content has been encrypted (i.e. ECDH-1PU key wrapping
+	// modes, which require the authentication tag)
+	wrapWithTag func(tag []byte) error
 }
 
 func (b *recipientBuilder) Build(cek []byte, calg jwa.ContentEncryptionAlgorithm, cc *content_crypt.Generic) (Recipient, []byte, error) {
+	// Key encrypters registered by the user take precedence
+	if f, ok := lookupKeyEncrypter(b.alg); ok {
+		return b.buildRegistered(f, cek)
+	}
+
 	// we need the raw key
 	rawKey := b.key
 
@@ -63,6 +75,8 @@ func (b *recipientBuilder) Build(cek []byte, calg jwa.ContentEncryptionAlgorithm
 	// First, create a key encryptor
 	var enc keyenc.Encrypter
 	switch b.alg {
+	case jwa.ECDH_1PU, jwa.ECDH_1PU_A128KW, jwa.ECDH_1PU_A192KW, jwa.ECDH_1PU_A256KW:
+		return b.buildECDH1PU(cek, calg, cc, rawKey, keyID)
 	case jwa.RSA1_5:
 		var pubkey rsa.PublicKey
 		if err := keyconv.RSAPublicKey(&pubkey, rawKey); err != nil {
@@ -126,7 +140,7 @@ func (b *recipientBuilder) Build(cek []byte, calg jwa.ContentEncryptionAlgorithm
 		}
 
 		switch key := rawKey.(type) {
-		case x25519.PublicKey:
+		case x25519.PublicKey, x448.PublicKey:
 			var apu, apv []byte
 			if hdrs := b.headers; hdrs != nil {
 				apu = hdrs.AgreementPartyUInfo()
@@ -206,6 +220,136 @@ func (b *recipientBuilder) Build(cek []byte, calg jwa.ContentEncryptionAlgorithm
 	return r, rawCEK, nil
 }
 
+// buildECDH1PU builds a recipient for the ECDH-1PU key agreement algorithms.
+// In Key Agreement with Key Wrapping mode, the content encryption key is
+// wrapped after the content has been encrypted, via b.wrapWithTag
+func (b *recipientBuilder) buildECDH1PU(cek []byte, calg jwa.ContentEncryptionAlgorithm, cc *content_crypt.Generic, rawKey interface{}, keyID string) (Recipient, []byte, error) {
+	if b.senderKey == nil {
+		return nil, nil, fmt.Errorf(`%s requires the sender's private key (use jwe.WithSenderKey())`, b.alg)
+	}
+
+	senderKey := b.senderKey
+	var senderKeyID string
+	if jwkKey, ok := senderKey.(jwk.Key); ok {
+		senderKeyID = jwkKey.KeyID()
+
+		var raw interface{}
+		if err := jwkKey.Raw(&raw); err != nil {
+			return nil, nil, fmt.Errorf(`failed to retrieve raw key out of %T: %w`, senderKey, err)
+		}
+		senderKey = raw
+	}
+
+	var apu, apv []byte
+	if hdrs := b.headers; hdrs != nil {
+		apu = hdrs.AgreementPartyUInfo()
+		apv = hdrs.AgreementPartyVInfo()
+	}
+
+	var enc *keyenc.ECDH1PUEncrypt
+	switch key := rawKey.(type) {
+	case x25519.PublicKey, x448.PublicKey:
+		v, err := keyenc.NewECDH1PUEncrypt(b.alg, calg, cc.KeySize(), key, senderKey, apu, apv)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`failed to create ECDH-1PU key wrap encrypter: %w`, err)
+		}
+		enc = v
+	default:
+		var pubkey ecdsa.PublicKey
+		if err := keyconv.ECDSAPublicKey(&pubkey, rawKey); err != nil {
+			return nil, nil, fmt.Errorf(`failed to generate public key from key (%T): %w`, key, err)
+		}
+
+		var privkey ecdsa.PrivateKey
+		if err := keyconv.ECDSAPrivateKey(&privkey, senderKey); err != nil {
+			return nil, nil, fmt.Errorf(`failed to generate sender private key from key (%T): %w`, senderKey, err)
+		}
+
+		v, err := keyenc.NewECDH1PUEncrypt(b.alg, calg, cc.KeySize(), &pubkey, &privkey, apu, apv)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`failed to create ECDH-1PU key wrap encrypter: %w`, err)
+		}
+		enc = v
+	}
+
+	r := NewRecipient()
+	if hdrs := b.headers; hdrs != nil {
+		_ = r.SetHeaders(hdrs)
+	}
+
+	if err := r.Headers().Set(AlgorithmKey, b.alg); err != nil {
+		return nil, nil, fmt.Errorf(`failed to set header: %w`, err)
+	}
+	if keyID != "" {
+		if err := r.Headers().Set(KeyIDKey, keyID); err != nil {
+			return nil, nil, fmt.Errorf(`failed to set header: %w`, err)
+		}
+	}
+	if senderKeyID != "" && r.Headers().SenderKeyID() == "" {
+		if err := r.Headers().Set(SenderKeyIDKey, senderKeyID); err != nil {
+			return nil, nil, fmt.Errorf(`failed to set header: %w`, err)
+		}
+	}
+
+	// The ephemeral public key must be known before the content is
+	// encrypted, as it may be part of the protected headers
+	epk := keygen.ByteWithECPublicKey{PublicKey: enc.EphemeralPublicKey()}
+	if err := epk.Populate(r.Headers()); err != nil {
+		return nil, nil, fmt.Errorf(`failed to populate: %w`, err)
+	}
+
+	if b.alg == jwa.ECDH_1PU {
+		enckey, err := enc.Encrypt(cek)
+		if err != nil {
+			return nil, nil, fmt.Errorf(`failed to encrypt key: %w`, err)
+		}
+		return r, enckey.Bytes(), nil
+	}
+
+	b.wrapWithTag = func(tag []byte) error {
+		enckey, err := enc.EncryptWithTag(cek, tag)
+		if err != nil {
+			return fmt.Errorf(`failed to encrypt key: %w`, err)
+		}
+		return r.SetEncryptedKey(enckey.Bytes())
+	}
+	return r, nil, nil
+}
+
+// buildRegistered builds a recipient using a KeyEncrypter that was
+// registered via RegisterKeyEncrypter
+func (b *recipientBuilder) buildRegistered(f KeyEncrypterFactory, cek []byte) (Recipient, []byte, error) {
+	enc, err := f.Create(b.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to create key encrypter for %s: %w`, b.alg, err)
+	}
+
+	r := NewRecipient()
+	if hdrs := b.headers; hdrs != nil {
+		_ = r.SetHeaders(hdrs)
+	}
+
+	if err := r.Headers().Set(AlgorithmKey, b.alg); err != nil {
+		return nil, nil, fmt.Errorf(`failed to set header: %w`, err)
+	}
+	if jwkKey, ok := b.key.(jwk.Key); ok {
+		if kid := jwkKey.KeyID(); kid != "" {
+			if err := r.Headers().Set(KeyIDKey, kid); err != nil {
+				return nil, nil, fmt.Errorf(`failed to set header: %w`, err)
+			}
+		}
+	}
+
+	enckey, err := enc.EncryptKey(cek, r.Headers())
+	if err != nil {
+		return nil, nil, fmt.Errorf(`failed to encrypt key: %w`, err)
+	}
+	if err := r.SetEncryptedKey(enckey); err != nil {
+		return nil, nil, fmt.Errorf(`failed to set encrypted key: %w`, err)
+	}
+	return r, nil, nil
+}
+
 // Encrypt generates a JWE message for the given payload and returns
 // it in serialized form, which can be in either compact or
 // JSON format. Default is compact.
@@ -241,6 +385,7 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 	var protected Headers
 	var mergeProtected bool
 	var useRawCEK bool
+	var senderKey interface{}
 	for _, option := range options {
 		//nolint:forcetypeassert
 		switch option.Ident() {
@@ -252,8 +397,10 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 			}
 
 			switch v {
-			case jwa.DIRECT, jwa.ECDH_ES:
-				useRawCEK = true
+			case jwa.DIRECT, jwa.ECDH_ES, jwa.ECDH_1PU:
+				if _, ok := lookupKeyEncrypter(v); !ok {
+					useRawCEK = true
+				}
 			}
 
 			builders = append(builders, &recipientBuilder{
@@ -263,6 +410,8 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 			})
 		case identContentEncryptionAlgorithm{}:
 			calg = option.Value().(jwa.ContentEncryptionAlgorithm)
+		case identSenderKey{}:
+			senderKey = option.Value()
 		case identCompress{}:
 			compression = option.Value().(jwa.CompressionAlgorithm)
 		case identMergeProtectedHeaders{}:
@@ -296,12 +445,16 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 
 	if useRawCEK {
 		if len(builders) != 1 {
-			return nil, fmt.Errorf(`jwe.Encrypt: multiple recipients for ECDH-ES/DIRECT mode supported`)
+			return nil, fmt.Errorf(`jwe.Encrypt: multiple recipients for ECDH-ES/ECDH-1PU/DIRECT mode supported`)
 		}
 	}
 
+	for _, builder := range builders {
+		builder.senderKey = senderKey
+	}
+
 	// There is exactly one content encrypter.
-	contentcrypt, err := content_crypt.NewGeneric(calg)
+	contentcrypt, err := newContentCrypt(calg)
 	if err != nil {
 		return nil, fmt.Errorf(`jwe.Encrypt: failed to create AES encrypter: %w`, err)
 	}
@@ -367,6 +520,15 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 		return nil, fmt.Errorf(`failed to encrypt payload: %w`, err)
 	}
 
+	for i, builder := range builders {
+		if builder.wrapWithTag == nil {
+			continue
+		}
+		if err := builder.wrapWithTag(tag); err != nil {
+			return nil, fmt.Errorf(`jwe.Encrypt: failed to create recipient #%d: %w`, i, err)
+		}
+	}
+
 	msg := NewMessage()
 
 	if err := msg.Set(CipherTextKey, ciphertext); err != nil {
@@ -398,11 +560,12 @@ func Encrypt(payload []byte, options ...EncryptOption) ([]byte, error) {
 }
 
 type decryptCtx struct {
-	msg              *Message
-	aad              []byte
-	computedAad      []byte
-	keyProviders     []KeyProvider
-	protectedHeaders Headers
+	msg                *Message
+	aad                []byte
+	computedAad        []byte
+	keyProviders       []KeyProvider
+	senderKeyProviders []SenderKeyProvider
+	protectedHeaders   Headers
 }
 
 // Decrypt takes the key encryption algorithm and the corresponding
@@ -414,9 +577,15 @@ type decryptCtx struct {
 // `jwa.KeyEncryptionAlgorithm` or otherwise it will cause an error.
 //
 // `key` must be a private key. It can be either in its raw format (e.g. *rsa.PrivateKey) or a jwk.Key
+//
+// If the message contains the "crit" header parameter, all of the extensions
+// listed in it must be declared as understood by using `jwe.WithCriticalExtensions()`,
+// or otherwise decryption fails. "crit" may only appear in the protected header.
 func Decrypt(buf []byte, options ...DecryptOption) ([]byte, error) {
 	var keyProviders []KeyProvider
+	var senderKeyProviders []SenderKeyProvider
 	var keyUsed interface{}
+	understood := make(map[string]struct{})
 
 	var dst *Message
 	//nolint:forcetypeassert
@@ -426,8 +595,16 @@ func Decrypt(buf []byte, options ...DecryptOption) ([]byte, error) {
 			dst = option.Value().(*Message)
 		case identKeyProvider{}:
 			keyProviders = append(keyProviders, option.Value().(KeyProvider))
+		case identSenderKey{}:
+			senderKeyProviders = append(senderKeyProviders, &staticSenderKeyProvider{key: option.Value()})
+		case identSenderKeyProvider{}:
+			senderKeyProviders = append(senderKeyProviders, option.Value().(SenderKeyProvider))
 		case identKeyUsed{}:
 			keyUsed = option.Value()
+		case identCriticalExtensions{}:
+			for _, name := range option.Value().([]string) {
+				understood[name] = struct{}{}
+			}
 		case identKey{}:
 			pair := option.Value().(*withKey)
 			alg, ok := pair.alg.(jwa.KeyEncryptionAlgorithm)
@@ -450,6 +627,10 @@ func Decrypt(buf []byte, options ...DecryptOption) ([]byte, error) {
 		return nil, fmt.Errorf(`failed to parse buffer for Decrypt: %w`, err)
 	}
 
+	if err := checkCritical(msg, understood); err != nil {
+		return nil, fmt.Errorf(`jwe.Decrypt: invalid "crit" header parameter: %w`, err)
+	}
+
 	// Process things that are common to the message
 	ctx := context.TODO()
 	h, err := msg.protectedHeaders.Clone(ctx)
@@ -495,6 +676,7 @@ func Decrypt(buf []byte, options ...DecryptOption) ([]byte, error) {
 	dctx.computedAad = computedAad
 	dctx.msg = msg
 	dctx.keyProviders = keyProviders
+	dctx.senderKeyProviders = senderKeyProviders
 	dctx.protectedHeaders = h
 
 	var lastError error
@@ -550,7 +732,9 @@ func (dctx *decryptCtx) try(ctx context.Context, recipient Recipient, keyUsed in
 }
 
 func (dctx *decryptCtx) decryptKey(ctx context.Context, alg jwa.KeyEncryptionAlgorithm, key interface{}, recipient Recipient) ([]byte, error) {
-	if jwkKey, ok := key.(jwk.Key); ok {
+	// Key decrypters registered by the user receive the key as is
+	registered, hasRegistered := lookupKeyDecrypter(alg)
+	if jwkKey, ok := key.(jwk.Key); ok && !hasRegistered {
 		var raw interface{}
 		if err := jwkKey.Raw(&raw); err != nil {
 			return nil, fmt.Errorf(`failed to retrieve raw key from %T: %w`, key, err)
@@ -564,11 +748,6 @@ func (dctx *decryptCtx) decryptKey(ctx context.Context, alg jwa.KeyEncryptionAlg
 		InitializationVector(dctx.msg.initializationVector).
 		Tag(dctx.msg.tag)
 
-	if recipient.Headers().Algorithm() != alg {
-		// algorithms don't match
-		return nil, fmt.Errorf(`jwe.Decrypt: key and recipient algorithms do not match`)
-	}
-
 	h2, err := dctx.protectedHeaders.Clone(ctx)
 	if err != nil {
 		return nil, fmt.Errorf(`jwe.Decrypt: failed to copy headers (1): %w`, err)
@@ -579,27 +758,80 @@ func (dctx *decryptCtx) decryptKey(ctx context.Context, alg jwa.KeyEncryptionAlg
 		return nil, fmt.Errorf(`failed to copy headers (2): %w`, err)
 	}
 
+	// "alg" may be specified in the shared headers when the message is
+	// in JSON serialization, so compare against the merged headers
+	if h2.Algorithm() != alg {
+		// algorithms don't match
+		return nil, fmt.Errorf(`jwe.Decrypt: key and recipient algorithms do not match`)
+	}
+
+	var plaintext []byte
+	if hasRegistered {
+		kd, err := registered.Create(key)
+		if err != nil {
+			return nil, fmt.Errorf(`jwe.Decrypt: failed to create key decrypter for %s: %w`, alg, err)
+		}
+
+		cek, err := kd.DecryptKey(recipient.EncryptedKey(), h2)
+		if err != nil {
+			return nil, fmt.Errorf(`jwe.Decrypt: failed to decrypt key: %w`, err)
+		}
+
+		plaintext, err = dec.DecryptContent(cek, dctx.msg.cipherText)
+		if err != nil {
+			return nil, fmt.Errorf(`jwe.Decrypt: decryption failed: %w`, err)
+		}
+	} else {
+		if err := dctx.configureDecrypter(ctx, dec, alg, h2); err != nil {
+			return nil, err
+		}
+
+		plaintext, err = dec.Decrypt(recipient.EncryptedKey(), dctx.msg.cipherText)
+		if err != nil {
+			return nil, fmt.Errorf(`jwe.Decrypt: decryption failed: %w`, err)
+		}
+	}
+
+	if h2.Compression() == jwa.Deflate {
+		buf, err := uncompress(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf(`jwe.Derypt: failed to uncompress payload: %w`, err)
+		}
+		plaintext = buf
+	}
+
+	if plaintext == nil {
+		return nil, fmt.Errorf(`failed to find matching recipient`)
+	}
+
+	return plaintext, nil
+}
+
+// configureDecrypter sets the algorithm specific parameters found in
+// the headers to the decrypter
+func (dctx *decryptCtx) configureDecrypter(ctx context.Context, dec *decrypter, alg jwa.KeyEncryptionAlgorithm, h2 Headers) error {
 	switch alg {
-	case jwa.ECDH_ES, jwa.ECDH_ES_A128KW, jwa.ECDH_ES_A192KW, jwa.ECDH_ES_A256KW:
+	case jwa.ECDH_ES, jwa.ECDH_ES_A128KW, jwa.ECDH_ES_A192KW, jwa.ECDH_ES_A256KW,
+		jwa.ECDH_1PU, jwa.ECDH_1PU_A128KW, jwa.ECDH_1PU_A192KW, jwa.ECDH_1PU_A256KW:
 		epkif, ok := h2.Get(EphemeralPublicKeyKey)
 		if !ok {
-			return nil, fmt.Errorf(`failed to get 'epk' field`)
+			return fmt.Errorf(`failed to get 'epk' field`)
 		}
 		switch epk := epkif.(type) {
 		case jwk.ECDSAPublicKey:
 			var pubkey ecdsa.PublicKey
 			if err := epk.Raw(&pubkey); err != nil {
-				return nil, fmt.Errorf(`failed to get public key: %w`, err)
+				return fmt.Errorf(`failed to get public key: %w`, err)
 			}
 			dec.PublicKey(&pubkey)
 		case jwk.OKPPublicKey:
 			var pubkey interface{}
 			if err := epk.Raw(&pubkey); err != nil {
-				return nil, fmt.Errorf(`failed to get public key: %w`, err)
+				return fmt.Errorf(`failed to get public key: %w`, err)
 			}
 			dec.PublicKey(pubkey)
 		default:
-			return nil, fmt.Errorf("unexpected 'epk' type %T for alg %s", epkif, alg)
+			return fmt.Errorf("unexpected 'epk' type %T for alg %s", epkif, alg)
 		}
 
 		if apu := h2.AgreementPartyUInfo(); len(apu) > 0 {
@@ -608,77 +840,101 @@ func (dctx *decryptCtx) decryptKey(ctx context.Context, alg jwa.KeyEncryptionAlg
 		if apv := h2.AgreementPartyVInfo(); len(apv) > 0 {
 			dec.AgreementPartyVInfo(apv)
 		}
+
+		switch alg {
+		case jwa.ECDH_1PU, jwa.ECDH_1PU_A128KW, jwa.ECDH_1PU_A192KW, jwa.ECDH_1PU_A256KW:
+			senderKey, err := dctx.fetchSenderKey(ctx, h2)
+			if err != nil {
+				return fmt.Errorf(`failed to fetch sender key for %s: %w`, alg, err)
+			}
+			dec.SenderPublicKey(senderKey)
+		}
 	case jwa.A128GCMKW, jwa.A192GCMKW, jwa.A256GCMKW:
 		ivB64, ok := h2.Get(InitializationVectorKey)
 		if !ok {
-			return nil, fmt.Errorf(`failed to get 'iv' field`)
+			return fmt.Errorf(`failed to get 'iv' field`)
 		}
 		ivB64Str, ok := ivB64.(string)
 		if !ok {
-			return nil, fmt.Errorf("unexpected type for 'iv': %T", ivB64)
+			return fmt.Errorf("unexpected type for 'iv': %T", ivB64)
 		}
 		tagB64, ok := h2.Get(TagKey)
 		if !ok {
-			return nil, fmt.Errorf(`failed to get 'tag' field`)
+			return fmt.Errorf(`failed to get 'tag' field`)
 		}
 		tagB64Str, ok := tagB64.(string)
 		if !ok {
-			return nil, fmt.Errorf("unexpected type for 'tag': %T", tagB64)
+			return fmt.Errorf("unexpected type for 'tag': %T", tagB64)
 		}
 		iv, err := base64.DecodeString(ivB64Str)
 		if err != nil {
-			return nil, fmt.Errorf(`failed to b64-decode 'iv': %w`, err)
+			return fmt.Errorf(`failed to b64-decode 'iv': %w`, err)
 		}
 		tag, err := base64.DecodeString(tagB64Str)
 		if err != nil {
-			return nil, fmt.Errorf(`failed to b64-decode 'tag': %w`, err)
+			return fmt.Errorf(`failed to b64-decode 'tag': %w`, err)
 		}
 		dec.KeyInitializationVector(iv)
 		dec.KeyTag(tag)
 	case jwa.PBES2_HS256_A128KW, jwa.PBES2_HS384_A192KW, jwa.PBES2_HS512_A256KW:
 		saltB64, ok := h2.Get(SaltKey)
 		if !ok {
-			return nil, fmt.Errorf(`failed to get 'p2s' field`)
+			return fmt.Errorf(`failed to get 'p2s' field`)
 		}
 		saltB64Str, ok := saltB64.(string)
 		if !ok {
-			return nil, fmt.Errorf("unexpected type for 'p2s': %T", saltB64)
+			return fmt.Errorf("unexpected type for 'p2s': %T", saltB64)
 		}
 
 		count, ok := h2.Get(CountKey)
 		if !ok {
-			return nil, fmt.Errorf(`failed to get 'p2c' field`)
+			return fmt.Errorf(`failed to get 'p2c' field`)
 		}
 		countFlt, ok := count.(float64)
 		if !ok {
-			return nil, fmt.Errorf("unexpected type for 'p2c': %T", count)
+			return fmt.Errorf("unexpected type for 'p2c': %T", count)
 		}
 		salt, err := base64.DecodeString(saltB64Str)
 		if err != nil {
-			return nil, fmt.Errorf(`failed to b64-decode 'salt': %w`, err)
+			return fmt.Errorf(`failed to b64-decode 'salt': %w`, err)
 		}
 		dec.KeySalt(salt)
 		dec.KeyCount(int(countFlt))
 	}
+	return nil
+}
 
-	plaintext, err := dec.Decrypt(recipient.EncryptedKey(), dctx.msg.cipherText)
-	if err != nil {
-		return nil, fmt.Errorf(`jwe.Decrypt: decryption failed: %w`, err)
+// fetchSenderKey resolves the sender's public key for ECDH-1PU using
+// the sender key providers. The key is returned in its raw format
+func (dctx *decryptCtx) fetchSenderKey(ctx context.Context, h2 Headers) (interface{}, error) {
+	if len(dctx.senderKeyProviders) == 0 {
+		return nil, fmt.Errorf(`no sender key providers have been provided (see jwe.WithSenderKey(), jwe.WithSenderKeySet(), and jwe.WithSenderKeyProvider())`)
 	}
 
-	if h2.Compression() == jwa.Deflate {
-		buf, err := uncompress(plaintext)
+	var lastError error
+	for _, kp := range dctx.senderKeyProviders {
+		key, err := kp.FetchSenderKey(ctx, h2)
 		if err != nil {
-			return nil, fmt.Errorf(`jwe.Derypt: failed to uncompress payload: %w`, err)
+			lastError = err
+			continue
+		}
+		if key == nil {
+			continue
 		}
-		plaintext = buf
-	}
 
-	if plaintext == nil {
-		return nil, fmt.Errorf(`failed to find matching recipient`)
+		if jwkKey, ok := key.(jwk.Key); ok {
+			var raw interface{}
+			if err := jwkKey.Raw(&raw); err != nil {
+				return nil, fmt.Errorf(`failed to retrieve raw key from %T: %w`, key, err)
+			}
+			key = raw
+		}
+		return key, nil
 	}
-
-	return plaintext, nil
+	if lastError != nil {
+		return nil, lastError
+	}
+	return nil, fmt.Errorf(`no sender key found`)
 }
 
 // Parse parses the JWE message into a Message object. The JWE message
@@ -21,6 +21,7 @@ import (
 	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -461,6 +462,20 @@ func TestEncode_X25519(t *testing.T) {
 	testEncodeECDHWithKey(t, privkey, pubkey)
 }
 
+func TestEncode_X448(t *testing.T) {
+	pubkey, privkey, err := x448.GenerateKey(rand.Reader)
+	require.NoError(t, err, `x448.GenerateKey should succeed`)
+
+	testEncodeECDHWithKey(t, privkey, pubkey)
+
+	// also through jwk.Key, so that "epk" is round-tripped as an OKP key
+	jwkPriv, err := jwk.FromRaw(privkey)
+	require.NoError(t, err, `jwk.FromRaw should succeed`)
+	jwkPub, err := jwk.PublicKeyOf(jwkPriv)
+	require.NoError(t, err, `jwk.PublicKeyOf should succeed`)
+	testEncodeECDHWithKey(t, jwkPriv, jwkPub)
+}
+
 func Test_GHIssue207(t *testing.T) {
 	const plaintext = "hi\n"
 	var testcases = []struct {
@@ -533,6 +548,8 @@ func TestEncode_Direct(t *testing.T) {
 		{jwa.A192GCM, 24},
 		{jwa.A256CBC_HS512, 64},
 		{jwa.A256GCM, 32},
+		{jwa.C20P, 32},
+		{jwa.XC20P, 32},
 	}
 	plaintext := []byte("Lorem ipsum")
 
@@ -564,6 +581,52 @@ func TestEncode_Direct(t *testing.T) {
 	}
 }
 
+func TestEncode_ChaCha20Poly1305(t *testing.T) {
+	eckey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+	x25519pub, x25519priv, err := x25519.GenerateKey(rand.Reader)
+	require.NoError(t, err, `x25519.GenerateKey should succeed`)
+	sharedkey := make([]byte, 32)
+	_, err = rand.Read(sharedkey)
+	require.NoError(t, err, `rand.Read should succeed`)
+
+	keys := []struct {
+		Algorithm jwa.KeyEncryptionAlgorithm
+		Public    interface{}
+		Private   interface{}
+	}{
+		{jwa.RSA_OAEP_256, &rsaPrivKey.PublicKey, &rsaPrivKey},
+		{jwa.A256KW, sharedkey, sharedkey},
+		{jwa.A256GCMKW, sharedkey, sharedkey},
+		{jwa.DIRECT, sharedkey, sharedkey},
+		{jwa.ECDH_ES, &eckey.PublicKey, eckey},
+		{jwa.ECDH_ES_A256KW, &eckey.PublicKey, eckey},
+		{jwa.ECDH_ES, x25519pub, x25519priv},
+	}
+
+	plaintext := []byte(examplePayload)
+	for _, enc := range []jwa.ContentEncryptionAlgorithm{jwa.C20P, jwa.XC20P} {
+		enc := enc
+		for _, key := range keys {
+			key := key
+			t.Run(fmt.Sprintf("%s/%s/%T", enc, key.Algorithm, key.Public), func(t *testing.T) {
+				for _, serialization := range []jwe.EncryptOption{jwe.WithCompact(), jwe.WithJSON()} {
+					encrypted, err := jwe.Encrypt(plaintext, jwe.WithKey(key.Algorithm, key.Public), jwe.WithContentEncryption(enc), serialization)
+					require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+					msg, err := jwe.Parse(encrypted)
+					require.NoError(t, err, `jwe.Parse should succeed`)
+					require.Equal(t, enc, msg.ProtectedHeaders().ContentEncryption(), `"enc" should match`)
+
+					decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(key.Algorithm, key.Private))
+					require.NoError(t, err, `jwe.Decrypt should succeed`)
+					require.Equal(t, plaintext, decrypted, `jwe.Decrypt should match input plaintext`)
+				}
+			})
+		}
+	}
+}
+
 // Decrypts messages generated by `jose` tool. It helps check compatibility with other jwx implementations.
 func TestDecodePredefined_Direct(t *testing.T) {
 	var testcases = []struct {
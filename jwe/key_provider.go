@@ -106,8 +106,14 @@ type keySetProvider struct {
 	requireKid bool
 }
 
+// encryptionKeyFilter matches keys that may be used for encryption
+var encryptionKeyFilter = jwk.MatchAny(
+	jwk.MatchKeyUsage(""),
+	jwk.MatchKeyUsage(jwk.ForEncryption),
+)
+
 func (kp *keySetProvider) selectKey(sink KeySink, key jwk.Key, _ Recipient, _ *Message) error {
-	if usage := key.KeyUsage(); usage != "" && usage != jwk.ForEncryption.String() {
+	if !encryptionKeyFilter.Match(key) {
 		return nil
 	}
 
@@ -142,8 +148,12 @@ func (kp *keySetProvider) FetchKeys(_ context.Context, sink KeySink, r Recipient
 		return kp.selectKey(sink, key, r, msg)
 	}
 
-	for i := 0; i < kp.set.Len(); i++ {
-		key, _ := kp.set.Key(i)
+	candidates, err := jwk.Select(kp.set, encryptionKeyFilter)
+	if err != nil {
+		return fmt.Errorf(`failed to select keys: %w`, err)
+	}
+	for i := 0; i < candidates.Len(); i++ {
+		key, _ := candidates.Key(i)
 		if err := kp.selectKey(sink, key, r, msg); err != nil {
 			continue
 		}
@@ -159,3 +169,47 @@ type KeyProviderFunc func(context.Context, KeySink, Recipient, *Message) error
 func (kp KeyProviderFunc) FetchKeys(ctx context.Context, sink KeySink, r Recipient, msg *Message) error {
 	return kp(ctx, sink, r, msg)
 }
+
+// SenderKeyProvider is responsible for providing the public key of the
+// sender of a JWE message that was encrypted using one of the ECDH-1PU
+// key agreement algorithms.
+//
+// `hdrs` contains the protected headers of the message merged with the
+// per-recipient headers, so the implementation can use values such as
+// the "skid" header parameter to look up the appropriate key.
+type SenderKeyProvider interface {
+	FetchSenderKey(ctx context.Context, hdrs Headers) (interface{}, error)
+}
+
+// SenderKeyProviderFunc is a type of SenderKeyProvider that is implemented by
+// a single function.
+type SenderKeyProviderFunc func(context.Context, Headers) (interface{}, error)
+
+func (kp SenderKeyProviderFunc) FetchSenderKey(ctx context.Context, hdrs Headers) (interface{}, error) {
+	return kp(ctx, hdrs)
+}
+
+type staticSenderKeyProvider struct {
+	key interface{}
+}
+
+func (kp *staticSenderKeyProvider) FetchSenderKey(_ context.Context, _ Headers) (interface{}, error) {
+	return kp.key, nil
+}
+
+type keySetSenderKeyProvider struct {
+	set jwk.Set
+}
+
+func (kp *keySetSenderKeyProvider) FetchSenderKey(_ context.Context, hdrs Headers) (interface{}, error) {
+	skid := hdrs.SenderKeyID()
+	if skid == "" {
+		return nil, fmt.Errorf(`failed to find matching sender key: no sender key ID ("skid") specified in message`)
+	}
+
+	key, ok := kp.set.LookupKeyID(skid)
+	if !ok {
+		return nil, fmt.Errorf(`failed to find key with key ID %q in key set`, skid)
+	}
+	return key, nil
+}
@@ -0,0 +1,110 @@
+package jwe
+
+import (
+	"sync"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+// KeyEncrypterFactory creates a KeyEncrypter for the given key. `key`
+// is the value that was passed to `jwe.WithKey()` (or the key found by
+// a key provider), as is.
+type KeyEncrypterFactory interface {
+	Create(key interface{}) (KeyEncrypter, error)
+}
+type KeyEncrypterFactoryFn func(interface{}) (KeyEncrypter, error)
+
+func (fn KeyEncrypterFactoryFn) Create(key interface{}) (KeyEncrypter, error) {
+	return fn(key)
+}
+
+// KeyDecrypterFactory creates a KeyDecrypter for the given key. `key`
+// is the value that was passed to `jwe.WithKey()` (or the key found by
+// a key provider), as is.
+type KeyDecrypterFactory interface {
+	Create(key interface{}) (KeyDecrypter, error)
+}
+type KeyDecrypterFactoryFn func(interface{}) (KeyDecrypter, error)
+
+func (fn KeyDecrypterFactoryFn) Create(key interface{}) (KeyDecrypter, error) {
+	return fn(key)
+}
+
+var muKeyEncrypters sync.RWMutex
+var keyEncrypterDB = make(map[jwa.KeyEncryptionAlgorithm]KeyEncrypterFactory)
+var keyDecrypterDB = make(map[jwa.KeyEncryptionAlgorithm]KeyDecrypterFactory)
+
+// RegisterKeyEncrypter is used to register a factory object that creates
+// KeyEncrypter objects for the given key encryption algorithm. Once
+// registered, `jwe.Encrypt()` uses the factory to encrypt content
+// encryption keys for recipients using `alg`, even if `alg` is one of
+// the algorithms that are supported by this library.
+//
+// `alg` is also registered via `jwa.RegisterKeyEncryptionAlgorithm()`
+// so that it is accepted in the "alg" header parameter.
+//
+// Registered algorithms must wrap the content encryption key (i.e.
+// the result must be stored as the JWE Encrypted Key), so key
+// management modes such as direct encryption or direct key agreement
+// are not supported.
+func RegisterKeyEncrypter(alg jwa.KeyEncryptionAlgorithm, f KeyEncrypterFactory) {
+	jwa.RegisterKeyEncryptionAlgorithm(alg)
+	muKeyEncrypters.Lock()
+	keyEncrypterDB[alg] = f
+	muKeyEncrypters.Unlock()
+}
+
+// RegisterKeyDecrypter is used to register a factory object that creates
+// KeyDecrypter objects for the given key encryption algorithm. Once
+// registered, `jwe.Decrypt()` uses the factory to decrypt content
+// encryption keys for recipients using `alg`, even if `alg` is one of
+// the algorithms that are supported by this library.
+//
+// `alg` is also registered via `jwa.RegisterKeyEncryptionAlgorithm()`
+// so that it is accepted in the "alg" header parameter.
+func RegisterKeyDecrypter(alg jwa.KeyEncryptionAlgorithm, f KeyDecrypterFactory) {
+	jwa.RegisterKeyEncryptionAlgorithm(alg)
+	muKeyEncrypters.Lock()
+	keyDecrypterDB[alg] = f
+	muKeyEncrypters.Unlock()
+}
+
+// UnregisterKeyEncrypter removes the KeyEncrypter factory that was
+// registered for the given algorithm. If the algorithm is supported
+// by this library, the built-in implementation is used again.
+//
+// Note that `alg` is NOT unregistered from the jwa package, as it may
+// still be used by a registered KeyDecrypter. Use
+// `jwa.UnregisterKeyEncryptionAlgorithm()` if you need to do so.
+func UnregisterKeyEncrypter(alg jwa.KeyEncryptionAlgorithm) {
+	muKeyEncrypters.Lock()
+	delete(keyEncrypterDB, alg)
+	muKeyEncrypters.Unlock()
+}
+
+// UnregisterKeyDecrypter removes the KeyDecrypter factory that was
+// registered for the given algorithm. If the algorithm is supported
+// by this library, the built-in implementation is used again.
+//
+// Note that `alg` is NOT unregistered from the jwa package, as it may
+// still be used by a registered KeyEncrypter. Use
+// `jwa.UnregisterKeyEncryptionAlgorithm()` if you need to do so.
+func UnregisterKeyDecrypter(alg jwa.KeyEncryptionAlgorithm) {
+	muKeyEncrypters.Lock()
+	delete(keyDecrypterDB, alg)
+	muKeyEncrypters.Unlock()
+}
+
+func lookupKeyEncrypter(alg jwa.KeyEncryptionAlgorithm) (KeyEncrypterFactory, bool) {
+	muKeyEncrypters.RLock()
+	defer muKeyEncrypters.RUnlock()
+	f, ok := keyEncrypterDB[alg]
+	return f, ok
+}
+
+func lookupKeyDecrypter(alg jwa.KeyEncryptionAlgorithm) (KeyDecrypterFactory, bool) {
+	muKeyEncrypters.RLock()
+	defer muKeyEncrypters.RUnlock()
+	f, ok := keyDecrypterDB[alg]
+	return f, ok
+}
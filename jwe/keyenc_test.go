@@ -0,0 +1,171 @@
+package jwe_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"fmt"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+// xorKeyWrap is a (totally insecure) key wrapping scheme used to
+// test custom key encryption algorithms
+type xorKeyWrap struct {
+	key []byte
+}
+
+const xorKW jwa.KeyEncryptionAlgorithm = "X-XOR-KW"
+
+func (kw *xorKeyWrap) Algorithm() jwa.KeyEncryptionAlgorithm {
+	return xorKW
+}
+
+func (kw *xorKeyWrap) xor(src []byte) []byte {
+	dst := make([]byte, len(src))
+	for i := range src {
+		dst[i] = src[i] ^ kw.key[i%len(kw.key)]
+	}
+	return dst
+}
+
+func (kw *xorKeyWrap) EncryptKey(cek []byte, hdrs jwe.Headers) ([]byte, error) {
+	if err := hdrs.Set("x-wrapped-len", len(cek)); err != nil {
+		return nil, err
+	}
+	return kw.xor(cek), nil
+}
+
+func (kw *xorKeyWrap) DecryptKey(enckey []byte, hdrs jwe.Headers) ([]byte, error) {
+	v, ok := hdrs.Get("x-wrapped-len")
+	if !ok {
+		return nil, fmt.Errorf(`missing "x-wrapped-len"`)
+	}
+	if l, ok := v.(float64); !ok || int(l) != len(enckey) {
+		return nil, fmt.Errorf(`invalid "x-wrapped-len"`)
+	}
+	return kw.xor(enckey), nil
+}
+
+func newXORKeyWrap(key interface{}) (*xorKeyWrap, error) {
+	if jwkKey, ok := key.(jwk.Key); ok {
+		var raw []byte
+		if err := jwkKey.Raw(&raw); err != nil {
+			return nil, err
+		}
+		key = raw
+	}
+	octets, ok := key.([]byte)
+	if !ok || len(octets) == 0 {
+		return nil, fmt.Errorf(`invalid key %T`, key)
+	}
+	return &xorKeyWrap{key: octets}, nil
+}
+
+// remoteRSA emulates an RSA-OAEP key encryption performed by an
+// external service, where the private key is referred to by name
+type remoteRSA struct {
+	keys  map[string]*rsa.PrivateKey
+	calls int
+}
+
+type remoteRSAKey struct {
+	rsa  *remoteRSA
+	name string
+}
+
+func (k *remoteRSAKey) Algorithm() jwa.KeyEncryptionAlgorithm {
+	return jwa.RSA_OAEP
+}
+
+func (k *remoteRSAKey) EncryptKey(cek []byte, _ jwe.Headers) ([]byte, error) {
+	k.rsa.calls++
+	return rsa.EncryptOAEP(sha1.New(), rand.Reader, &k.rsa.keys[k.name].PublicKey, cek, []byte{})
+}
+
+func (k *remoteRSAKey) DecryptKey(enckey []byte, _ jwe.Headers) ([]byte, error) {
+	k.rsa.calls++
+	return rsa.DecryptOAEP(sha1.New(), rand.Reader, k.rsa.keys[k.name], enckey, []byte{})
+}
+
+func TestRegisterKeyEncrypter(t *testing.T) {
+	t.Run("custom algorithm", func(t *testing.T) {
+		jwe.RegisterKeyEncrypter(xorKW, jwe.KeyEncrypterFactoryFn(func(key interface{}) (jwe.KeyEncrypter, error) {
+			return newXORKeyWrap(key)
+		}))
+		jwe.RegisterKeyDecrypter(xorKW, jwe.KeyDecrypterFactoryFn(func(key interface{}) (jwe.KeyDecrypter, error) {
+			return newXORKeyWrap(key)
+		}))
+		defer func() {
+			jwe.UnregisterKeyEncrypter(xorKW)
+			jwe.UnregisterKeyDecrypter(xorKW)
+			jwa.UnregisterKeyEncryptionAlgorithm(xorKW)
+		}()
+
+		key, err := jwxtest.GenerateSymmetricJwk()
+		require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+		require.NoError(t, key.Set(jwk.KeyIDKey, `xor-key`), `key.Set should succeed`)
+
+		payload := []byte(`Lorem ipsum`)
+		for _, options := range [][]jwe.EncryptOption{
+			{jwe.WithKey(xorKW, key)},
+			{jwe.WithJSON(), jwe.WithKey(xorKW, key), jwe.WithKey(jwa.A128KW, []byte(`0123456789abcdef`))},
+		} {
+			encrypted, err := jwe.Encrypt(payload, options...)
+			require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+			msg, err := jwe.Parse(encrypted)
+			require.NoError(t, err, `jwe.Parse should succeed`)
+			r := msg.Recipients()[0]
+			require.Equal(t, xorKW, r.Headers().Algorithm(), `alg should match`)
+			require.Equal(t, `xor-key`, r.Headers().KeyID(), `kid should match`)
+
+			decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(xorKW, key))
+			require.NoError(t, err, `jwe.Decrypt should succeed`)
+			require.Equal(t, payload, decrypted, `payload should match`)
+		}
+	})
+	t.Run("override built-in algorithm", func(t *testing.T) {
+		privkey, err := jwxtest.GenerateRsaKey()
+		require.NoError(t, err, `jwxtest.GenerateRsaKey should succeed`)
+
+		remote := &remoteRSA{keys: map[string]*rsa.PrivateKey{`my-key`: privkey}}
+		jwe.RegisterKeyEncrypter(jwa.RSA_OAEP, jwe.KeyEncrypterFactoryFn(func(key interface{}) (jwe.KeyEncrypter, error) {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf(`expected key name`)
+			}
+			return &remoteRSAKey{rsa: remote, name: name}, nil
+		}))
+		jwe.RegisterKeyDecrypter(jwa.RSA_OAEP, jwe.KeyDecrypterFactoryFn(func(key interface{}) (jwe.KeyDecrypter, error) {
+			name, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf(`expected key name`)
+			}
+			return &remoteRSAKey{rsa: remote, name: name}, nil
+		}))
+
+		payload := []byte(`Lorem ipsum`)
+		encrypted, err := jwe.Encrypt(payload, jwe.WithKey(jwa.RSA_OAEP, `my-key`))
+		require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+		decrypted, err := jwe.Decrypt(encrypted, jwe.WithKey(jwa.RSA_OAEP, `my-key`))
+		require.NoError(t, err, `jwe.Decrypt should succeed`)
+		require.Equal(t, payload, decrypted, `payload should match`)
+		require.Equal(t, 2, remote.calls, `registered key encrypter/decrypter should be used`)
+
+		jwe.UnregisterKeyEncrypter(jwa.RSA_OAEP)
+		jwe.UnregisterKeyDecrypter(jwa.RSA_OAEP)
+
+		// The message is compatible with the built-in implementation
+		decrypted, err = jwe.Decrypt(encrypted, jwe.WithKey(jwa.RSA_OAEP, privkey))
+		require.NoError(t, err, `jwe.Decrypt should succeed`)
+		require.Equal(t, payload, decrypted, `payload should match`)
+		require.Equal(t, 2, remote.calls, `built-in key decrypter should be used`)
+	})
+}
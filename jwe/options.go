@@ -8,6 +8,8 @@ import (
 	"github.com/lestrrat-go/option"
 )
 
+type identCriticalExtensions struct{}
+
 // Specify contents of the protected header. Some fields such as
 // "enc" and "zip" will be overwritten when encryption is performed.
 //
@@ -84,6 +86,13 @@ func WithKeySet(set jwk.Set, options ...WithKeySetSuboption) DecryptOption {
 	})
 }
 
+// WithSenderKeySet specifies that the sender's public key for the ECDH-1PU
+// key agreement algorithms should be looked up from `set`, using the value
+// of the "skid" header parameter as the key ID.
+func WithSenderKeySet(set jwk.Set) DecryptOption {
+	return WithSenderKeyProvider(&keySetSenderKeyProvider{set: set})
+}
+
 // WithJSON specifies that the result of `jwe.Encrypt()` is serialized in
 // JSON format.
 //
@@ -105,3 +114,15 @@ func WithJSON(options ...WithJSONSuboption) EncryptOption {
 	}
 	return &encryptOption{option.New(identSerialization{}, format)}
 }
+
+// WithCriticalExtensions specifies the names of the extension header
+// parameters that the caller understands and processes. When a JWE
+// message lists extensions in its "crit" header parameter, `jwe.Decrypt()`
+// fails unless all of them have been declared using this option
+// (RFC7516 Section 4.1.13).
+//
+// This option may be specified multiple times, in which case the
+// names are accumulated.
+func WithCriticalExtensions(names ...string) DecryptOption {
+	return &decryptOption{option.New(identCriticalExtensions{}, append([]string(nil), names...))}
+}
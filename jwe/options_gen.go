@@ -122,6 +122,8 @@ type identPerRecipientHeaders struct{}
 type identPretty struct{}
 type identProtectedHeaders struct{}
 type identRequireKid struct{}
+type identSenderKey struct{}
+type identSenderKeyProvider struct{}
 type identSerialization struct{}
 
 func (identCompress) String() string {
@@ -172,6 +174,14 @@ func (identRequireKid) String() string {
 	return "WithRequireKid"
 }
 
+func (identSenderKey) String() string {
+	return "WithSenderKey"
+}
+
+func (identSenderKeyProvider) String() string {
+	return "WithSenderKeyProvider"
+}
+
 func (identSerialization) String() string {
 	return "WithSerialization"
 }
@@ -245,6 +255,30 @@ func WithRequireKid(v bool) WithKeySetSuboption {
 	return &withKeySetSuboption{option.New(identRequireKid{}, v)}
 }
 
+// WithSenderKey specifies the static key of the sender, which is
+// required when using the ECDH-1PU key agreement algorithms
+// (`jwa.ECDH_1PU`, `jwa.ECDH_1PU_A128KW`, `jwa.ECDH_1PU_A192KW`, and
+// `jwa.ECDH_1PU_A256KW`).
+//
+// When passed to `jwe.Encrypt()`, the key must be the sender's private
+// key. When passed to `jwe.Decrypt()`, the key must be the sender's
+// public key. Either a raw key (*ecdsa.PrivateKey/*ecdsa.PublicKey,
+// x25519.PrivateKey/x25519.PublicKey, x448.PrivateKey/x448.PublicKey) or
+// a `jwk.Key` may be passed.
+//
+// When encrypting, if the key is a `jwk.Key` with a key ID, its value
+// is used to populate the "skid" header parameter.
+func WithSenderKey(v interface{}) EncryptDecryptOption {
+	return &encryptDecryptOption{option.New(identSenderKey{}, v)}
+}
+
+// WithSenderKeyProvider specifies the `jwe.SenderKeyProvider` to use
+// to resolve the sender's public key when decrypting messages that
+// were encrypted using one of the ECDH-1PU key agreement algorithms.
+func WithSenderKeyProvider(v SenderKeyProvider) DecryptOption {
+	return &decryptOption{option.New(identSenderKeyProvider{}, v)}
+}
+
 // WithCompact specifies that the result of `jwe.Encrypt()` is serialized in
 // compact format.
 //
@@ -21,5 +21,7 @@ func TestOptionIdent(t *testing.T) {
 	require.Equal(t, "WithPretty", identPretty{}.String())
 	require.Equal(t, "WithProtectedHeaders", identProtectedHeaders{}.String())
 	require.Equal(t, "WithRequireKid", identRequireKid{}.String())
+	require.Equal(t, "WithSenderKey", identSenderKey{}.String())
+	require.Equal(t, "WithSenderKeyProvider", identSenderKeyProvider{}.String())
 	require.Equal(t, "WithSerialization", identSerialization{}.String())
 }
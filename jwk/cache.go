@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/lestrrat-go/httprc"
 	"github.com/lestrrat-go/iter/arrayiter"
 	"github.com/lestrrat-go/iter/mapiter"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
 )
 
 type Transformer = httprc.Transformer
@@ -80,8 +82,31 @@ type Whitelist = httprc.Whitelist
 // as it is expected that they will be available and will be valid. The
 // caching mechanism can hide intermittent connectivity problems as well
 // as keep the objects mostly fresh.
+//
+// If a `jwk.CacheStorage` is given via `jwk.WithCacheStorage()`, the
+// fetched Set objects are persisted every time they are refreshed, and
+// loaded back when their URLs are registered. Loaded Set objects are
+// served as-is until the first successful refresh, which happens
+// according to the refresh schedule that was persisted along with them.
 type Cache struct {
-	cache *httprc.Cache
+	cache   *httprc.Cache
+	ctx     context.Context
+	storage CacheStorage
+	errSink ErrSink
+
+	mu       sync.RWMutex
+	stored   map[string]*CacheEntry // entries loaded from storage, until the first refresh
+	sets     map[string]Set         // parsed Set objects for entries in `stored`
+	policies map[string]*cachePolicy
+	lazy     map[string]*lazyRefresh
+}
+
+// lazyRefresh keeps track of the refreshes requested via
+// `(jwk.Cache).RefreshLazy()` for a single URL
+type lazyRefresh struct {
+	mu          sync.Mutex
+	minInterval time.Duration
+	last        time.Time
 }
 
 // PostFetcher is an interface for objects that want to perform
@@ -106,6 +131,15 @@ func (f PostFetchFunc) PostFetch(u string, set Set) (Set, error) {
 type jwksTransform struct {
 	postFetch    PostFetcher
 	parseOptions []ParseOption
+
+	// x5u is true when the resource is a PEM encoded certificate
+	// chain instead of a JWKS. See `jwk.WithX5U()`
+	x5u           bool
+	verifyOptions []VerifyX509Option
+
+	// onFetch is called after a successful fetch, when the cache
+	// has been configured with a storage or the URL has a policy
+	onFetch func(string, Set)
 }
 
 // Default transform has no postFetch. This can be shared
@@ -118,7 +152,12 @@ func (t *jwksTransform) Transform(u string, res *http.Response) (interface{}, er
 		return nil, fmt.Errorf(`failed to read response body status: %w`, err)
 	}
 
-	set, err := Parse(buf, t.parseOptions...)
+	var set Set
+	if t.x5u {
+		set, err = parseX5U(u, buf, t.verifyOptions)
+	} else {
+		set, err = Parse(buf, t.parseOptions...)
+	}
 	if err != nil {
 		return nil, fmt.Errorf(`failed to parse JWK set at %q: %w`, u, err)
 	}
@@ -131,6 +170,39 @@ func (t *jwksTransform) Transform(u string, res *http.Response) (interface{}, er
 		set = v
 	}
 
+	if t.onFetch != nil {
+		t.onFetch(u, set)
+	}
+
+	return set, nil
+}
+
+// parseStored parses the JSON representation of a Set that was
+// persisted in a CacheStorage. PostFetch is not executed, as the
+// stored data has already been processed by it.
+func (t *jwksTransform) parseStored(buf []byte) (Set, error) {
+	options := make([]ParseOption, 0, len(t.parseOptions))
+	for _, option := range t.parseOptions {
+		// stored data is always JSON
+		if option.Ident() == (identPEM{}) {
+			continue
+		}
+		options = append(options, option)
+	}
+	set, err := Parse(buf, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.x5u {
+		// certificates may have expired since they were stored
+		for i := 0; i < set.Len(); i++ {
+			key, _ := set.Key(i)
+			if _, err := VerifyX509Chain(key, t.verifyOptions...); err != nil {
+				return nil, fmt.Errorf(`failed to verify stored certificate chain: %w`, err)
+			}
+		}
+	}
 	return set, nil
 }
 
@@ -140,18 +212,40 @@ func (t *jwksTransform) Transform(u string, res *http.Response) (interface{}, er
 // details.
 func NewCache(ctx context.Context, options ...CacheOption) *Cache {
 	var hrcopts []httprc.CacheOption
+	var storage CacheStorage
+	var errSink ErrSink
 	for _, option := range options {
 		//nolint:forcetypeassert
 		switch option.Ident() {
 		case identRefreshWindow{}:
 			hrcopts = append(hrcopts, httprc.WithRefreshWindow(option.Value().(time.Duration)))
 		case identErrSink{}:
-			hrcopts = append(hrcopts, httprc.WithErrSink(option.Value().(ErrSink)))
+			errSink = option.Value().(ErrSink)
+		case identCacheStorage{}:
+			storage = option.Value().(CacheStorage)
 		}
 	}
 
-	return &Cache{
-		cache: httprc.NewCache(ctx, hrcopts...),
+	c := &Cache{
+		ctx:      ctx,
+		storage:  storage,
+		errSink:  errSink,
+		stored:   make(map[string]*CacheEntry),
+		sets:     make(map[string]Set),
+		policies: make(map[string]*cachePolicy),
+		lazy:     make(map[string]*lazyRefresh),
+	}
+
+	// Errors are always intercepted, so that failed refreshes can be
+	// tracked for URLs registered with a policy
+	hrcopts = append(hrcopts, httprc.WithErrSink(httprc.ErrSinkFunc(c.handleRefreshError)))
+	c.cache = httprc.NewCache(ctx, hrcopts...)
+	return c
+}
+
+func (c *Cache) reportError(u string, err error) {
+	if c.errSink != nil {
+		c.errSink.Error(&httprc.RefreshError{URL: u, Err: err})
 	}
 }
 
@@ -164,6 +258,13 @@ func NewCache(ctx context.Context, options ...CacheOption) *Cache {
 // Use `jwk.WithParser` to configure how the JWKS should be parsed,
 // such as passing it extra options.
 //
+// Use `jwk.WithX5U(true)` to register a URL that points to a PEM encoded
+// certificate chain instead of a JWKS. See `jwk.FetchX5U()`
+//
+// Use `jwk.WithMaxStaleness`, `jwk.WithRetryBackoff`, and
+// `jwk.WithNegativeCacheTTL` to control how the cache behaves while
+// the JWKS cannot be fetched.
+//
 // Please refer to the documentation for `(httprc.Cache).Register` for more
 // details.
 //
@@ -180,6 +281,17 @@ func (c *Cache) Register(u string, options ...RegisterOption) error {
 	var hrropts []httprc.RegisterOption
 	var pf PostFetcher
 	var parseOptions []ParseOption
+	var verifyOptions []VerifyX509Option
+	var x5u bool
+	var refreshInterval time.Duration
+	minRefreshInterval := 15 * time.Minute // same as httprc
+	var policy *cachePolicy
+	getPolicy := func() *cachePolicy {
+		if policy == nil {
+			policy = &cachePolicy{}
+		}
+		return policy
+	}
 
 	// Note: we do NOT accept Transform option
 	for _, option := range options {
@@ -187,48 +299,249 @@ func (c *Cache) Register(u string, options ...RegisterOption) error {
 			parseOptions = append(parseOptions, parseOpt)
 			continue
 		}
+		if verifyOpt, ok := option.(VerifyX509Option); ok {
+			verifyOptions = append(verifyOptions, verifyOpt)
+			continue
+		}
 
 		//nolint:forcetypeassert
 		switch option.Ident() {
 		case identHTTPClient{}:
 			hrropts = append(hrropts, httprc.WithHTTPClient(option.Value().(HTTPClient)))
+		case identX5U{}:
+			x5u = option.Value().(bool)
 		case identRefreshInterval{}:
-			hrropts = append(hrropts, httprc.WithRefreshInterval(option.Value().(time.Duration)))
+			refreshInterval = option.Value().(time.Duration)
+			hrropts = append(hrropts, httprc.WithRefreshInterval(refreshInterval))
 		case identMinRefreshInterval{}:
-			hrropts = append(hrropts, httprc.WithMinRefreshInterval(option.Value().(time.Duration)))
+			minRefreshInterval = option.Value().(time.Duration)
+			hrropts = append(hrropts, httprc.WithMinRefreshInterval(minRefreshInterval))
 		case identFetchWhitelist{}:
 			hrropts = append(hrropts, httprc.WithWhitelist(option.Value().(httprc.Whitelist)))
 		case identPostFetcher{}:
 			pf = option.Value().(PostFetcher)
+		case identMaxStaleness{}:
+			getPolicy().maxStaleness = option.Value().(time.Duration)
+		case identRetryBackoff{}:
+			getPolicy().retryBackoff = option.Value().(time.Duration)
+		case identNegativeCacheTTL{}:
+			getPolicy().negativeTTL = option.Value().(time.Duration)
 		}
 	}
 
+	// When refreshInterval is not specified, the actual schedule
+	// depends on the HTTP response headers, but it will never be
+	// earlier than minRefreshInterval
+	interval := refreshInterval
+	if interval <= 0 {
+		interval = minRefreshInterval
+	}
+
+	if x5u && !hasX509Roots(verifyOptions) {
+		return fmt.Errorf(`(jwk.Cache).Register: jwk.WithX509Roots() must be specified along with jwk.WithX5U(true)`)
+	}
+
 	var t *jwksTransform
-	if pf == nil && len(parseOptions) == 0 {
+	if pf == nil && len(parseOptions) == 0 && !x5u && c.storage == nil && policy == nil {
 		t = defaultTransform
 	} else {
 		// User-supplied PostFetcher is attached to the transformer
 		t = &jwksTransform{
-			postFetch:    pf,
-			parseOptions: parseOptions,
+			postFetch:     pf,
+			parseOptions:  parseOptions,
+			x5u:           x5u,
+			verifyOptions: verifyOptions,
 		}
 	}
 
+	if c.storage != nil || policy != nil {
+		t.onFetch = func(u string, set Set) {
+			c.fetched(u, set, interval)
+		}
+	}
+
+	if policy != nil {
+		// Retries never wait longer than the regular refresh interval
+		policy.maxBackoff = interval
+	}
+
 	// Set the transfomer at the end so that nobody can override it
 	hrropts = append(hrropts, httprc.WithTransformer(t))
-	return c.cache.Register(u, hrropts...)
+	if err := c.cache.Register(u, hrropts...); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if old, ok := c.policies[u]; ok {
+		old.stop()
+	}
+	if policy != nil {
+		c.policies[u] = policy
+	} else {
+		delete(c.policies, u)
+	}
+	c.lazy[u] = &lazyRefresh{minInterval: minRefreshInterval}
+	c.mu.Unlock()
+
+	if c.storage != nil {
+		c.load(u, t)
+	}
+	return nil
+}
+
+// load loads the entry for `u` from the storage, and schedules its
+// first refresh. Errors are reported to the ErrSink, as the Set can
+// still be fetched from the URL.
+func (c *Cache) load(u string, t *jwksTransform) {
+	entry, err := c.storage.Load(c.ctx, u)
+	if err != nil {
+		c.reportError(u, fmt.Errorf(`failed to load stored JWKS: %w`, err))
+		return
+	}
+	if entry == nil {
+		return
+	}
+
+	set, err := t.parseStored(entry.Data)
+	if err != nil {
+		c.reportError(u, fmt.Errorf(`failed to parse stored JWKS: %w`, err))
+		return
+	}
+
+	c.mu.Lock()
+	c.stored[u] = entry
+	c.sets[u] = set
+	policy := c.policies[u]
+	c.mu.Unlock()
+
+	if policy != nil {
+		policy.mu.Lock()
+		policy.lastFetched = entry.LastFetched
+		policy.mu.Unlock()
+	}
+
+	go c.refreshStored(u, time.Until(entry.NextRefresh))
+}
+
+// refreshStored refreshes the Set that was loaded from the storage
+// after `delay`. Subsequent refreshes are scheduled by httprc, even
+// if this refresh fails.
+func (c *Cache) refreshStored(u string, delay time.Duration) {
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-timer.C:
+		}
+	}
+
+	// The URL may have been unregistered, or refreshed explicitly
+	c.mu.RLock()
+	_, ok := c.stored[u]
+	c.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	if _, err := c.Refresh(c.ctx, u); err != nil {
+		c.reportError(u, err)
+	}
+}
+
+// fetched is called every time the Set for `u` has been successfully
+// fetched and parsed
+func (c *Cache) fetched(u string, set Set, interval time.Duration) {
+	now := time.Now()
+	c.fetchSucceeded(u, now)
+
+	// The Set has been fetched, so we no longer need to serve
+	// the stored one
+	c.mu.Lock()
+	delete(c.stored, u)
+	delete(c.sets, u)
+	c.mu.Unlock()
+
+	if c.storage != nil {
+		c.store(u, set, now, interval)
+	}
+}
+
+func (c *Cache) store(u string, set Set, now time.Time, interval time.Duration) {
+	buf, err := json.Marshal(set)
+	if err != nil {
+		c.reportError(u, fmt.Errorf(`failed to marshal JWKS for storage: %w`, err))
+		return
+	}
+
+	entry := &CacheEntry{
+		URL:         u,
+		Data:        buf,
+		LastFetched: now,
+		NextRefresh: now.Add(interval),
+	}
+	if err := c.storage.Store(c.ctx, entry); err != nil {
+		c.reportError(u, fmt.Errorf(`failed to store JWKS: %w`, err))
+	}
+}
+
+// storedSet returns the Set loaded from the storage, if it has not
+// been refreshed yet
+func (c *Cache) storedSet(u string) (Set, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	set, ok := c.sets[u]
+	return set, ok
 }
 
 // Get returns the stored JWK set (`Set`) from the cache.
 //
+// If the URL was registered with `jwk.WithMaxStaleness`, Get returns
+// an error instead of the cached Set once it has not been successfully
+// refreshed for longer than the specified duration. If the URL was
+// registered with `jwk.WithNegativeCacheTTL`, fetch errors are returned
+// as-is for the specified duration, without fetching the URL again.
+// Fetches that fail because `ctx` has been canceled or has timed out
+// are not considered to be failures of the URL.
+//
 // Please refer to the documentation for `(httprc.Cache).Get` for more
 // details.
 func (c *Cache) Get(ctx context.Context, u string) (Set, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p := c.policyFor(u)
+	if p != nil {
+		switch p.status(time.Now()) {
+		case cachePolicyStale:
+			return nil, p.staleError(u)
+		case cachePolicyNegative:
+			return nil, p.negativeError(u)
+		case cachePolicyRefetch:
+			defer p.endRefetch()
+			return c.Refresh(ctx, u)
+		}
+	}
+
+	if set, ok := c.storedSet(u); ok {
+		return set, nil
+	}
+
 	v, err := c.cache.Get(ctx, u)
 	if err != nil {
+		c.fetchFailedFor(ctx, u, err)
 		return nil, err
 	}
 
+	// The previous fetch did not produce a Set. If no failure has been
+	// recorded, it was abandoned by its caller (see fetchFailedFor), and
+	// the resource must be fetched again
+	if v == nil && p != nil && !p.failed() {
+		return c.Refresh(ctx, u)
+	}
+
 	set, ok := v.(Set)
 	if !ok {
 		return nil, fmt.Errorf(`cached object is not a Set (was %T)`, v)
@@ -242,8 +555,13 @@ func (c *Cache) Get(ctx context.Context, u string) (Set, error) {
 // Please refer to the documentation for `(httprc.Cache).Refresh` for
 // more details
 func (c *Cache) Refresh(ctx context.Context, u string) (Set, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	v, err := c.cache.Refresh(ctx, u)
 	if err != nil {
+		c.fetchFailedFor(ctx, u, err)
 		return nil, err
 	}
 
@@ -254,6 +572,44 @@ func (c *Cache) Refresh(ctx context.Context, u string) (Set, error) {
 	return set, nil
 }
 
+// RefreshLazy is identical to Refresh(), except that the resource
+// is fetched at most once per minimum refresh interval (as specified by
+// `jwk.WithMinRefreshInterval()` when `u` was registered) through this
+// method. Otherwise the currently cached Set is returned.
+//
+// The boolean return value reports whether the resource was fetched.
+//
+// This is useful when the Set needs to be refreshed in response to
+// external input, such as a JWS message signed by a key ID that is not
+// in the cached Set: untrusted input can not force the cache to fetch
+// the resource over and over again. Concurrent callers wait for the
+// refresh in progress to complete, and receive the refreshed Set.
+func (c *Cache) RefreshLazy(ctx context.Context, u string) (Set, bool, error) {
+	c.mu.RLock()
+	lr, ok := c.lazy[u]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, false, fmt.Errorf(`url %q is not registered`, u)
+	}
+
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	if !lr.last.IsZero() && time.Since(lr.last) < lr.minInterval {
+		set, err := c.Get(ctx, u)
+		return set, false, err
+	}
+
+	// Failed attempts count as well, or else an unreachable server
+	// would be queried for every request
+	lr.last = time.Now()
+	set, err := c.Refresh(ctx, u)
+	if err != nil {
+		return nil, false, err
+	}
+	return set, true, nil
+}
+
 // IsRegistered returns true if the given URL `u` has already been registered
 // in the cache.
 //
@@ -268,11 +624,62 @@ func (c *Cache) IsRegistered(u string) bool {
 // Please refer to the documentation for `(httprc.Cache).Unregister` for more
 // details.
 func (c *Cache) Unregister(u string) error {
+	c.mu.Lock()
+	delete(c.stored, u)
+	delete(c.sets, u)
+	if p, ok := c.policies[u]; ok {
+		p.stop()
+		delete(c.policies, u)
+	}
+	delete(c.lazy, u)
+	c.mu.Unlock()
 	return c.cache.Unregister(u)
 }
 
+// Snapshot returns the contents of the cache at the given moment.
+//
+// Set objects that were loaded from a `jwk.CacheStorage` and have
+// not been refreshed yet are reported along with the time they were
+// originally fetched.
 func (c *Cache) Snapshot() *httprc.Snapshot {
-	return c.cache.Snapshot()
+	snapshot := c.cache.Snapshot()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i, entry := range snapshot.Entries {
+		stored, ok := c.stored[entry.URL]
+		if !ok {
+			continue
+		}
+		snapshot.Entries[i].Data = c.sets[entry.URL]
+		snapshot.Entries[i].LastFetched = stored.LastFetched
+	}
+	return snapshot
+}
+
+// CachedFetcher is a `jwk.Fetcher` that retrieves Set objects from a
+// `jwk.Cache` instead of fetching them every time. It can be passed to
+// `jws.WithVerifyAuto()` and `jws.WithVerifyX5U()` so that the keys
+// referred to by "jku" and "x5u" fields are cached and refreshed.
+//
+// Only URLs that have been registered with the cache can be fetched,
+// and `jwk.FetchOption`s passed to `Fetch()` are ignored: the options
+// given to `(*jwk.Cache).Register()` are used instead. This also means
+// that registering a URL is equivalent to adding it to a whitelist.
+type CachedFetcher struct {
+	cache *Cache
+}
+
+// NewCachedFetcher creates a new `jwk.CachedFetcher` object.
+func NewCachedFetcher(cache *Cache) *CachedFetcher {
+	return &CachedFetcher{cache: cache}
+}
+
+func (f *CachedFetcher) Fetch(ctx context.Context, u string, _ ...FetchOption) (Set, error) {
+	if !f.cache.IsRegistered(u) {
+		return nil, fmt.Errorf(`url %q has not been registered with the cache`, u)
+	}
+	return f.cache.Get(ctx, u)
 }
 
 // CachedSet is a thin shim over jwk.Cache that allows the user to cloack
@@ -0,0 +1,187 @@
+package jwk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/httprc"
+)
+
+// cachePolicy controls how a registered URL behaves when fetching
+// it fails. It is only created when one of `jwk.WithMaxStaleness()`,
+// `jwk.WithRetryBackoff()`, or `jwk.WithNegativeCacheTTL()` is specified.
+type cachePolicy struct {
+	maxStaleness time.Duration
+	retryBackoff time.Duration
+	maxBackoff   time.Duration
+	negativeTTL  time.Duration
+
+	mu          sync.Mutex
+	lastFetched time.Time // time of the last successful fetch
+	lastErr     error     // non-nil if the last fetch failed
+	lastErrAt   time.Time
+	failures    int  // number of consecutive failures
+	refetching  bool // true while a caller is fetching after the negative cache TTL
+	retry       *time.Timer
+}
+
+type cachePolicyStatus int
+
+const (
+	cachePolicyOK       cachePolicyStatus = iota // use whatever is in the cache
+	cachePolicyStale                             // the cached Set is too stale to be used
+	cachePolicyNegative                          // return the cached error
+	cachePolicyRefetch                           // fetch the Set synchronously
+)
+
+func (p *cachePolicy) status(now time.Time) cachePolicyStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.lastErr == nil {
+		return cachePolicyOK
+	}
+
+	hasSet := !p.lastFetched.IsZero()
+	stale := hasSet && p.maxStaleness > 0 && now.Sub(p.lastFetched) > p.maxStaleness
+	if (stale || !hasSet) && p.negativeTTL > 0 {
+		if p.refetching || now.Sub(p.lastErrAt) < p.negativeTTL {
+			return cachePolicyNegative
+		}
+		// Claim this attempt, so that concurrent callers do not
+		// all fetch at the same time. The claim must be released
+		// using endRefetch()
+		p.refetching = true
+		return cachePolicyRefetch
+	}
+
+	if stale {
+		return cachePolicyStale
+	}
+	return cachePolicyOK
+}
+
+func (p *cachePolicy) failed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr != nil
+}
+
+func (p *cachePolicy) endRefetch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.refetching = false
+}
+
+func (p *cachePolicy) staleError(u string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Errorf(`JWKS for %q has not been refreshed since %s, exceeding the maximum staleness of %s: %w`, u, p.lastFetched.Format(time.RFC3339), p.maxStaleness, p.lastErr)
+}
+
+func (p *cachePolicy) negativeError(u string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return fmt.Errorf(`failed to fetch %q (cached until %s): %w`, u, p.lastErrAt.Add(p.negativeTTL).Format(time.RFC3339), p.lastErr)
+}
+
+// backoff returns the delay before the next retry. Must be called
+// while holding the lock
+func (p *cachePolicy) backoff() time.Duration {
+	d := p.retryBackoff
+	for i := 1; i < p.failures && d < p.maxBackoff; i++ {
+		d *= 2
+	}
+	if p.maxBackoff > 0 && d > p.maxBackoff {
+		d = p.maxBackoff
+	}
+	return d
+}
+
+func (p *cachePolicy) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.retry != nil {
+		p.retry.Stop()
+		p.retry = nil
+	}
+}
+
+func (c *Cache) policyFor(u string) *cachePolicy {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.policies[u]
+}
+
+func (c *Cache) fetchSucceeded(u string, at time.Time) {
+	p := c.policyFor(u)
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastFetched = at
+	p.lastErr = nil
+	p.failures = 0
+	if p.retry != nil {
+		p.retry.Stop()
+		p.retry = nil
+	}
+}
+
+// fetchFailedFor records a failure to fetch `u` on behalf of a caller
+// using `ctx`. If `ctx` has been canceled or has timed out, the failure
+// is specific to the caller and is not recorded, as it says nothing
+// about the availability of `u`.
+func (c *Cache) fetchFailedFor(ctx context.Context, u string, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	c.fetchFailed(u, err)
+}
+
+func (c *Cache) fetchFailed(u string, err error) {
+	p := c.policyFor(u)
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastErr = err
+	p.lastErrAt = time.Now()
+	p.failures++
+
+	if p.retryBackoff <= 0 {
+		return
+	}
+
+	if p.retry != nil {
+		p.retry.Stop()
+	}
+	p.retry = time.AfterFunc(p.backoff(), func() {
+		if c.ctx.Err() != nil {
+			return
+		}
+		if _, err := c.Refresh(c.ctx, u); err != nil {
+			c.reportError(u, err)
+		}
+	})
+}
+
+// handleRefreshError receives the errors from the refreshes performed
+// by httprc in the background, and forwards them to the user's ErrSink
+func (c *Cache) handleRefreshError(err error) {
+	var rerr *httprc.RefreshError
+	if errors.As(err, &rerr) {
+		c.fetchFailed(rerr.URL, rerr.Err)
+	}
+
+	if c.errSink != nil {
+		c.errSink.Error(err)
+	}
+}
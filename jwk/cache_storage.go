@@ -0,0 +1,120 @@
+package jwk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+)
+
+// CacheEntry represents a single JWKS persisted by a `jwk.CacheStorage`.
+type CacheEntry struct {
+	// URL is the URL that the JWKS was fetched from
+	URL string `json:"url"`
+	// Data is the JSON representation of the JWKS, after it has been
+	// processed by the `jwk.PostFetcher` (if any)
+	Data json.RawMessage `json:"data"`
+	// LastFetched is the time when the JWKS was fetched
+	LastFetched time.Time `json:"last_fetched"`
+	// NextRefresh is the earliest time when the JWKS is scheduled to be
+	// refreshed. When the JWKS is loaded back into a `jwk.Cache`,
+	// it will not be refreshed before this time.
+	NextRefresh time.Time `json:"next_refresh"`
+}
+
+// CacheStorage is used by `jwk.Cache` to persist the fetched JWKS
+// so that they survive process restarts. Use `jwk.WithCacheStorage()`
+// to give a storage to `jwk.NewCache()`.
+//
+// Store is called every time a JWKS is successfully fetched, and Load
+// is called when a URL is registered with the cache.
+type CacheStorage interface {
+	// Load returns the entry stored for the URL `u`. If no entry exists,
+	// Load should return nil without an error.
+	Load(ctx context.Context, u string) (*CacheEntry, error)
+	// Store saves the entry, replacing any existing entry for the same URL.
+	Store(ctx context.Context, entry *CacheEntry) error
+}
+
+type fileCacheStorage struct {
+	dir string
+}
+
+// NewFileCacheStorage creates a `jwk.CacheStorage` that stores each
+// JWKS as a JSON file under the directory `dir`. The directory is
+// created when the first entry is stored, if it does not exist.
+func NewFileCacheStorage(dir string) CacheStorage {
+	return &fileCacheStorage{dir: dir}
+}
+
+func (s *fileCacheStorage) path(u string) string {
+	sum := sha256.Sum256([]byte(u))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *fileCacheStorage) Load(_ context.Context, u string) (*CacheEntry, error) {
+	buf, err := os.ReadFile(s.path(u))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`failed to read cache entry for %q: %w`, u, err)
+	}
+
+	var entry CacheEntry
+	if err := json.Unmarshal(buf, &entry); err != nil {
+		return nil, fmt.Errorf(`failed to parse cache entry for %q: %w`, u, err)
+	}
+
+	if entry.URL != u {
+		return nil, fmt.Errorf(`cache entry URL mismatch: expected %q, got %q`, u, entry.URL)
+	}
+	return &entry, nil
+}
+
+func (s *fileCacheStorage) Store(_ context.Context, entry *CacheEntry) error {
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf(`failed to marshal cache entry for %q: %w`, entry.URL, err)
+	}
+
+	if err := writeFileAtomic(s.path(entry.URL), buf); err != nil {
+		return fmt.Errorf(`failed to save cache entry for %q: %w`, entry.URL, err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes `buf` to a temporary file first and then
+// renames it to `path`, so that readers never observe a partially
+// written file. The parent directory is created if necessary.
+func writeFileAtomic(path string, buf []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf(`failed to create directory: %w`, err)
+	}
+
+	f, err := os.CreateTemp(dir, `.jwk-*`)
+	if err != nil {
+		return fmt.Errorf(`failed to create temporary file: %w`, err)
+	}
+	tmpname := f.Name()
+	defer os.Remove(tmpname)
+
+	if _, err := f.Write(buf); err != nil {
+		f.Close()
+		return fmt.Errorf(`failed to write temporary file: %w`, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf(`failed to close temporary file: %w`, err)
+	}
+
+	if err := os.Rename(tmpname, path); err != nil {
+		return fmt.Errorf(`failed to rename temporary file: %w`, err)
+	}
+	return nil
+}
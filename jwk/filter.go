@@ -0,0 +1,186 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+// KeyFilter is a predicate used to select keys from a `jwk.Set`.
+// See `jwk.Select()`
+type KeyFilter interface {
+	Match(Key) bool
+}
+
+// KeyFilterFunc is a KeyFilter based on a function
+type KeyFilterFunc func(Key) bool
+
+func (f KeyFilterFunc) Match(key Key) bool {
+	return f(key)
+}
+
+// Select returns a new `jwk.Set` containing the keys in `set` that
+// match all of the given filters, in the same order as they appear
+// in `set`. The keys themselves are not cloned.
+//
+// For example, to select all signature keys to be used with ES256, you
+// would write
+//
+//	selected, err := jwk.Select(set,
+//	  jwk.MatchKeyUsage(jwk.ForSignature),
+//	  jwk.MatchAlgorithm(jwa.ES256),
+//	)
+func Select(set Set, filters ...KeyFilter) (Set, error) {
+	selected := NewSet()
+	filter := MatchAll(filters...)
+	for i := 0; i < set.Len(); i++ {
+		key, ok := set.Key(i)
+		if !ok {
+			return nil, fmt.Errorf(`failed to get key #%d`, i)
+		}
+		if !filter.Match(key) {
+			continue
+		}
+		if err := selected.AddKey(key); err != nil {
+			return nil, fmt.Errorf(`failed to add key #%d: %w`, i, err)
+		}
+	}
+	return selected, nil
+}
+
+// MatchAll creates a KeyFilter that matches keys that match all of
+// the given filters. If no filters are given, all keys are matched.
+func MatchAll(filters ...KeyFilter) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		for _, filter := range filters {
+			if !filter.Match(key) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// MatchAny creates a KeyFilter that matches keys that match any of
+// the given filters. If no filters are given, no keys are matched.
+func MatchAny(filters ...KeyFilter) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		for _, filter := range filters {
+			if filter.Match(key) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// MatchNot creates a KeyFilter that matches keys that do not match
+// the given filter.
+func MatchNot(filter KeyFilter) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return !filter.Match(key)
+	})
+}
+
+// MatchKeyType creates a KeyFilter that matches keys whose "kty"
+// field is `kty`
+func MatchKeyType(kty jwa.KeyType) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return key.KeyType() == kty
+	})
+}
+
+// MatchKeyUsage creates a KeyFilter that matches keys whose "use"
+// field is `use`. Passing an empty value matches keys without
+// the "use" field.
+func MatchKeyUsage(use KeyUsageType) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return key.KeyUsage() == string(use)
+	})
+}
+
+// MatchKeyOps creates a KeyFilter that matches keys whose "key_ops"
+// field contains all of the given operations.
+func MatchKeyOps(ops ...KeyOperation) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		keyOps := key.KeyOps()
+		for _, op := range ops {
+			var found bool
+			for _, keyOp := range keyOps {
+				if keyOp == op {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// MatchAlgorithm creates a KeyFilter that matches keys whose "alg"
+// field is `alg`
+func MatchAlgorithm(alg jwa.KeyAlgorithm) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return key.Algorithm().String() == alg.String()
+	})
+}
+
+// MatchCurve creates a KeyFilter that matches EC and OKP keys whose
+// "crv" field is `crv`
+func MatchCurve(crv jwa.EllipticCurveAlgorithm) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		// both EC and OKP keys implement Crv()
+		v, ok := key.(interface {
+			Crv() jwa.EllipticCurveAlgorithm
+		})
+		return ok && v.Crv() == crv
+	})
+}
+
+// MatchKeyID creates a KeyFilter that matches keys whose "kid"
+// field is `kid`
+func MatchKeyID(kid string) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return key.KeyID() == kid
+	})
+}
+
+// MatchThumbprint creates a KeyFilter that matches keys whose
+// thumbprint (RFC7638) computed using `hash` is `thumbprint`
+func MatchThumbprint(hash crypto.Hash, thumbprint []byte) KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		v, err := key.Thumbprint(hash)
+		if err != nil {
+			return false
+		}
+		return subtle.ConstantTimeCompare(v, thumbprint) == 1
+	})
+}
+
+// MatchPrivateKey creates a KeyFilter that matches keys that contain
+// private (or secret) key material. Symmetric keys are always matched.
+func MatchPrivateKey() KeyFilter {
+	return KeyFilterFunc(isPrivateKey)
+}
+
+// MatchPublicKey creates a KeyFilter that matches asymmetric keys that
+// do not contain private key material.
+func MatchPublicKey() KeyFilter {
+	return KeyFilterFunc(func(key Key) bool {
+		return !isPrivateKey(key)
+	})
+}
+
+func isPrivateKey(key Key) bool {
+	switch key.(type) {
+	case RSAPrivateKey, ECDSAPrivateKey, OKPPrivateKey, SymmetricKey:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,131 @@
+package jwk_test
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelect(t *testing.T) {
+	rsaSig, err := jwxtest.GenerateRsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+	require.NoError(t, rsaSig.Set(jwk.KeyIDKey, `rsa-sig`), `rsaSig.Set should succeed`)
+	require.NoError(t, rsaSig.Set(jwk.KeyUsageKey, jwk.ForSignature), `rsaSig.Set should succeed`)
+	require.NoError(t, rsaSig.Set(jwk.AlgorithmKey, jwa.RS256), `rsaSig.Set should succeed`)
+
+	rsaEnc, err := jwxtest.GenerateRsaPublicJwk()
+	require.NoError(t, err, `jwxtest.GenerateRsaPublicJwk should succeed`)
+	require.NoError(t, rsaEnc.Set(jwk.KeyIDKey, `rsa-enc`), `rsaEnc.Set should succeed`)
+	require.NoError(t, rsaEnc.Set(jwk.KeyUsageKey, jwk.ForEncryption), `rsaEnc.Set should succeed`)
+	require.NoError(t, rsaEnc.Set(jwk.AlgorithmKey, jwa.RSA_OAEP_256), `rsaEnc.Set should succeed`)
+
+	ecSig, err := jwxtest.GenerateEcdsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateEcdsaJwk should succeed`)
+	require.NoError(t, ecSig.Set(jwk.KeyIDKey, `ec-sig`), `ecSig.Set should succeed`)
+	require.NoError(t, ecSig.Set(jwk.AlgorithmKey, jwa.ES256), `ecSig.Set should succeed`)
+	require.NoError(t, ecSig.Set(jwk.KeyOpsKey, jwk.KeyOperationList{jwk.KeyOpSign, jwk.KeyOpVerify}), `ecSig.Set should succeed`)
+
+	edSig, err := jwxtest.GenerateEd25519Jwk()
+	require.NoError(t, err, `jwxtest.GenerateEd25519Jwk should succeed`)
+	require.NoError(t, edSig.Set(jwk.KeyIDKey, `ed-sig`), `edSig.Set should succeed`)
+
+	sym, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+	require.NoError(t, sym.Set(jwk.KeyIDKey, `sym`), `sym.Set should succeed`)
+
+	set := jwk.NewSet()
+	for _, key := range []jwk.Key{rsaSig, rsaEnc, ecSig, edSig, sym} {
+		require.NoError(t, set.AddKey(key), `set.AddKey should succeed`)
+	}
+
+	tp, err := ecSig.Thumbprint(crypto.SHA256)
+	require.NoError(t, err, `ecSig.Thumbprint should succeed`)
+
+	testcases := []struct {
+		Name     string
+		Filters  []jwk.KeyFilter
+		Expected []string
+	}{
+		{
+			Name:     "No filters",
+			Expected: []string{`rsa-sig`, `rsa-enc`, `ec-sig`, `ed-sig`, `sym`},
+		},
+		{
+			Name:     "kty",
+			Filters:  []jwk.KeyFilter{jwk.MatchKeyType(jwa.RSA)},
+			Expected: []string{`rsa-sig`, `rsa-enc`},
+		},
+		{
+			Name:     "use",
+			Filters:  []jwk.KeyFilter{jwk.MatchKeyUsage(jwk.ForSignature)},
+			Expected: []string{`rsa-sig`},
+		},
+		{
+			Name:     "use (unspecified)",
+			Filters:  []jwk.KeyFilter{jwk.MatchKeyUsage("")},
+			Expected: []string{`ec-sig`, `ed-sig`, `sym`},
+		},
+		{
+			Name:     "key_ops",
+			Filters:  []jwk.KeyFilter{jwk.MatchKeyOps(jwk.KeyOpSign)},
+			Expected: []string{`ec-sig`},
+		},
+		{
+			Name:     "alg",
+			Filters:  []jwk.KeyFilter{jwk.MatchAlgorithm(jwa.RSA_OAEP_256)},
+			Expected: []string{`rsa-enc`},
+		},
+		{
+			Name:     "crv",
+			Filters:  []jwk.KeyFilter{jwk.MatchCurve(jwa.Ed25519)},
+			Expected: []string{`ed-sig`},
+		},
+		{
+			Name:     "kid",
+			Filters:  []jwk.KeyFilter{jwk.MatchKeyID(`sym`)},
+			Expected: []string{`sym`},
+		},
+		{
+			Name:     "thumbprint",
+			Filters:  []jwk.KeyFilter{jwk.MatchThumbprint(crypto.SHA256, tp)},
+			Expected: []string{`ec-sig`},
+		},
+		{
+			Name:     "private",
+			Filters:  []jwk.KeyFilter{jwk.MatchPrivateKey()},
+			Expected: []string{`rsa-sig`, `ec-sig`, `ed-sig`, `sym`},
+		},
+		{
+			Name:     "public",
+			Filters:  []jwk.KeyFilter{jwk.MatchPublicKey()},
+			Expected: []string{`rsa-enc`},
+		},
+		{
+			Name: "Composed",
+			Filters: []jwk.KeyFilter{
+				jwk.MatchAny(jwk.MatchKeyUsage(jwk.ForSignature), jwk.MatchKeyUsage("")),
+				jwk.MatchNot(jwk.MatchKeyType(jwa.OctetSeq)),
+			},
+			Expected: []string{`rsa-sig`, `ec-sig`, `ed-sig`},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			selected, err := jwk.Select(set, tc.Filters...)
+			require.NoError(t, err, `jwk.Select should succeed`)
+
+			var kids []string
+			for i := 0; i < selected.Len(); i++ {
+				key, _ := selected.Key(i)
+				kids = append(kids, key.KeyID())
+			}
+			require.Equal(t, tc.Expected, kids)
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
+)
+
+const (
+	defaultRSAKeySize = 2048
+	defaultOctKeySize = 256
+)
+
+// Generate creates a new private key of the given key type, and returns
+// it as a jwk.Key. For symmetric (oct) keys, the key itself is returned.
+//
+// The characteristics of the key can be controlled by passing options:
+//
+//   - `jwk.WithKeySize()` specifies the number of bits for RSA and oct keys
+//   - `jwk.WithCurve()` specifies the curve for EC and OKP keys
+//   - `jwk.WithAlgorithm()`, `jwk.WithKeyUsage()`, and `jwk.WithKeyOps()`
+//     populate the `alg`, `use`, and `key_ops` fields, respectively
+//   - `jwk.WithAssignKeyID()` assigns a key ID (`kid`) via `jwk.AssignKeyID()`
+//   - `jwk.WithRandReader()` specifies the source of randomness
+//
+// For example, to generate an EC key using P-384 to sign using ES384,
+// you would write
+//
+//	key, err := jwk.Generate(jwa.EC,
+//	  jwk.WithCurve(jwa.P384),
+//	  jwk.WithAlgorithm(jwa.ES384),
+//	  jwk.WithAssignKeyID(true),
+//	)
+func Generate(kty jwa.KeyType, options ...GenerateOption) (Key, error) {
+	var keysize int
+	var crv jwa.EllipticCurveAlgorithm
+	var alg jwa.KeyAlgorithm
+	var usage KeyUsageType
+	var ops KeyOperationList
+	var assignKeyID bool
+	var kidOptions []AssignKeyIDOption
+	var rdr io.Reader = rand.Reader
+	for _, option := range options {
+		if kidOption, ok := option.(AssignKeyIDOption); ok {
+			kidOptions = append(kidOptions, kidOption)
+			continue
+		}
+
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identKeySize{}:
+			keysize = option.Value().(int)
+		case identCurve{}:
+			crv = option.Value().(jwa.EllipticCurveAlgorithm)
+		case identAlgorithm{}:
+			alg = option.Value().(jwa.KeyAlgorithm)
+		case identKeyUsage{}:
+			usage = option.Value().(KeyUsageType)
+		case identKeyOps{}:
+			ops = option.Value().(KeyOperationList)
+		case identAssignKeyID{}:
+			assignKeyID = option.Value().(bool)
+		case identRandReader{}:
+			rdr = option.Value().(io.Reader)
+		}
+	}
+
+	var raw interface{}
+	switch kty {
+	case jwa.RSA:
+		if keysize == 0 {
+			keysize = defaultRSAKeySize
+		}
+		v, err := rsa.GenerateKey(rdr, keysize)
+		if err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to generate RSA private key: %w`, err)
+		}
+		raw = v
+	case jwa.EC:
+		if crv == "" {
+			crv = jwa.P256
+		}
+		curve, ok := CurveForAlgorithm(crv)
+		if !ok {
+			return nil, fmt.Errorf(`jwk.Generate: invalid elliptic curve for EC key: %s`, crv)
+		}
+		v, err := ecdsa.GenerateKey(curve, rdr)
+		if err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to generate ECDSA private key: %w`, err)
+		}
+		raw = v
+	case jwa.OKP:
+		if crv == "" {
+			crv = jwa.Ed25519
+		}
+		switch crv {
+		case jwa.Ed25519:
+			_, v, err := ed25519.GenerateKey(rdr)
+			if err != nil {
+				return nil, fmt.Errorf(`jwk.Generate: failed to generate ed25519 private key: %w`, err)
+			}
+			raw = v
+		case jwa.X25519:
+			_, v, err := x25519.GenerateKey(rdr)
+			if err != nil {
+				return nil, fmt.Errorf(`jwk.Generate: failed to generate x25519 private key: %w`, err)
+			}
+			raw = v
+		case jwa.Ed448:
+			_, v, err := ed448.GenerateKey(rdr)
+			if err != nil {
+				return nil, fmt.Errorf(`jwk.Generate: failed to generate ed448 private key: %w`, err)
+			}
+			raw = v
+		case jwa.X448:
+			_, v, err := x448.GenerateKey(rdr)
+			if err != nil {
+				return nil, fmt.Errorf(`jwk.Generate: failed to generate x448 private key: %w`, err)
+			}
+			raw = v
+		default:
+			return nil, fmt.Errorf(`jwk.Generate: invalid elliptic curve for OKP key: %s (expected %s/%s/%s/%s)`, crv, jwa.Ed25519, jwa.X25519, jwa.Ed448, jwa.X448)
+		}
+	case jwa.OctetSeq:
+		if keysize == 0 {
+			keysize = octKeySizeForAlgorithm(alg)
+		}
+		if keysize <= 0 || keysize%8 != 0 {
+			return nil, fmt.Errorf(`jwk.Generate: invalid key size for oct key: %d (must be a positive multiple of 8)`, keysize)
+		}
+		octets := make([]byte, keysize/8)
+		if _, err := io.ReadFull(rdr, octets); err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to generate symmetric key: %w`, err)
+		}
+		raw = octets
+	default:
+		return nil, fmt.Errorf(`jwk.Generate: invalid key type %s`, kty)
+	}
+
+	key, err := FromRaw(raw)
+	if err != nil {
+		return nil, fmt.Errorf(`jwk.Generate: failed to create jwk.Key from %T: %w`, raw, err)
+	}
+
+	if alg != nil {
+		if err := key.Set(AlgorithmKey, alg); err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to set %q: %w`, AlgorithmKey, err)
+		}
+	}
+
+	if usage != "" {
+		if err := key.Set(KeyUsageKey, usage); err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to set %q: %w`, KeyUsageKey, err)
+		}
+	}
+
+	if len(ops) > 0 {
+		if err := key.Set(KeyOpsKey, ops); err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to set %q: %w`, KeyOpsKey, err)
+		}
+	}
+
+	if assignKeyID {
+		if err := AssignKeyID(key, kidOptions...); err != nil {
+			return nil, fmt.Errorf(`jwk.Generate: failed to assign key ID: %w`, err)
+		}
+	}
+
+	return key, nil
+}
+
+// octKeySizeForAlgorithm returns the appropriate size of a symmetric
+// key in bits for the given algorithm. If the algorithm does not
+// dictate a particular size, the default size is returned
+func octKeySizeForAlgorithm(alg jwa.KeyAlgorithm) int {
+	switch alg {
+	case jwa.A128KW, jwa.A128GCMKW, jwa.PBES2_HS256_A128KW:
+		return 128
+	case jwa.A192KW, jwa.A192GCMKW, jwa.PBES2_HS384_A192KW:
+		return 192
+	case jwa.HS256, jwa.A256KW, jwa.A256GCMKW, jwa.PBES2_HS512_A256KW:
+		return 256
+	case jwa.HS384:
+		return 384
+	case jwa.HS512:
+		return 512
+	default:
+		return defaultOctKeySize
+	}
+}
@@ -0,0 +1,142 @@
+package jwk_test
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	t.Run("RSA", func(t *testing.T) {
+		t.Parallel()
+		key, err := jwk.Generate(jwa.RSA, jwk.WithKeySize(3072))
+		require.NoError(t, err, `jwk.Generate should succeed`)
+		require.Implements(t, (*jwk.RSAPrivateKey)(nil), key)
+
+		var raw rsa.PrivateKey
+		require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+		require.Equal(t, 3072, raw.N.BitLen(), `key size should match`)
+	})
+	t.Run("EC", func(t *testing.T) {
+		t.Parallel()
+		testcases := []struct {
+			Options []jwk.GenerateOption
+			Curve   jwa.EllipticCurveAlgorithm
+			Error   bool
+		}{
+			{Curve: jwa.P256},
+			{Options: []jwk.GenerateOption{jwk.WithCurve(jwa.P384)}, Curve: jwa.P384},
+			{Options: []jwk.GenerateOption{jwk.WithCurve(jwa.P521)}, Curve: jwa.P521},
+			{Options: []jwk.GenerateOption{jwk.WithCurve(jwa.Ed25519)}, Error: true},
+		}
+		for _, tc := range testcases {
+			key, err := jwk.Generate(jwa.EC, tc.Options...)
+			if tc.Error {
+				require.Error(t, err, `jwk.Generate should fail`)
+				continue
+			}
+			require.NoError(t, err, `jwk.Generate should succeed`)
+			eckey, ok := key.(jwk.ECDSAPrivateKey)
+			require.True(t, ok, `key should be a jwk.ECDSAPrivateKey`)
+			require.Equal(t, tc.Curve, eckey.Crv(), `curve should match`)
+
+			var raw ecdsa.PrivateKey
+			require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+		}
+	})
+	t.Run("OKP", func(t *testing.T) {
+		t.Parallel()
+		for _, crv := range []jwa.EllipticCurveAlgorithm{jwa.Ed25519, jwa.X25519} {
+			key, err := jwk.Generate(jwa.OKP, jwk.WithCurve(crv))
+			require.NoError(t, err, `jwk.Generate should succeed`)
+			okpkey, ok := key.(jwk.OKPPrivateKey)
+			require.True(t, ok, `key should be a jwk.OKPPrivateKey`)
+			require.Equal(t, crv, okpkey.Crv(), `curve should match`)
+		}
+
+		_, err := jwk.Generate(jwa.OKP, jwk.WithCurve(jwa.P256))
+		require.Error(t, err, `jwk.Generate should fail for P-256`)
+	})
+	t.Run("oct", func(t *testing.T) {
+		t.Parallel()
+		testcases := []struct {
+			Options []jwk.GenerateOption
+			Size    int
+			Error   bool
+		}{
+			{Size: 32},
+			{Options: []jwk.GenerateOption{jwk.WithKeySize(128)}, Size: 16},
+			{Options: []jwk.GenerateOption{jwk.WithAlgorithm(jwa.HS512)}, Size: 64},
+			{Options: []jwk.GenerateOption{jwk.WithAlgorithm(jwa.A192KW)}, Size: 24},
+			{Options: []jwk.GenerateOption{jwk.WithAlgorithm(jwa.HS512), jwk.WithKeySize(1024)}, Size: 128},
+			{Options: []jwk.GenerateOption{jwk.WithKeySize(100)}, Error: true},
+		}
+		for _, tc := range testcases {
+			key, err := jwk.Generate(jwa.OctetSeq, tc.Options...)
+			if tc.Error {
+				require.Error(t, err, `jwk.Generate should fail`)
+				continue
+			}
+			require.NoError(t, err, `jwk.Generate should succeed`)
+
+			var raw []byte
+			require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+			require.Len(t, raw, tc.Size, `key size should match`)
+		}
+	})
+	t.Run("Invalid key type", func(t *testing.T) {
+		t.Parallel()
+		_, err := jwk.Generate(jwa.KeyType("foo"))
+		require.Error(t, err, `jwk.Generate should fail`)
+	})
+	t.Run("Attributes", func(t *testing.T) {
+		t.Parallel()
+		key, err := jwk.Generate(jwa.EC,
+			jwk.WithCurve(jwa.P384),
+			jwk.WithAlgorithm(jwa.ES384),
+			jwk.WithKeyUsage(jwk.ForSignature),
+			jwk.WithKeyOps(jwk.KeyOperationList{jwk.KeyOpSign, jwk.KeyOpVerify}),
+			jwk.WithAssignKeyID(true),
+			jwk.WithThumbprintHash(crypto.SHA1),
+		)
+		require.NoError(t, err, `jwk.Generate should succeed`)
+		require.Equal(t, jwa.ES384, key.Algorithm(), `alg should match`)
+		require.Equal(t, string(jwk.ForSignature), key.KeyUsage(), `use should match`)
+		require.Equal(t, jwk.KeyOperationList{jwk.KeyOpSign, jwk.KeyOpVerify}, key.KeyOps(), `key_ops should match`)
+
+		expected := key.KeyID()
+		require.NotEmpty(t, expected, `kid should be assigned`)
+		require.NoError(t, key.Remove(jwk.KeyIDKey), `key.Remove should succeed`)
+		require.NoError(t, jwk.AssignKeyID(key, jwk.WithThumbprintHash(crypto.SHA1)), `jwk.AssignKeyID should succeed`)
+		require.Equal(t, expected, key.KeyID(), `kid should be computed using the given hash`)
+	})
+	t.Run("Deterministic", func(t *testing.T) {
+		t.Parallel()
+		seed := bytes.Repeat([]byte{0x42}, 64)
+		for _, crv := range []jwa.EllipticCurveAlgorithm{jwa.Ed25519, jwa.X25519} {
+			key1, err := jwk.Generate(jwa.OKP, jwk.WithCurve(crv), jwk.WithRandReader(bytes.NewReader(seed)))
+			require.NoError(t, err, `jwk.Generate should succeed`)
+			key2, err := jwk.Generate(jwa.OKP, jwk.WithCurve(crv), jwk.WithRandReader(bytes.NewReader(seed)))
+			require.NoError(t, err, `jwk.Generate should succeed`)
+
+			tp1, err := key1.Thumbprint(crypto.SHA256)
+			require.NoError(t, err, `key1.Thumbprint should succeed`)
+			tp2, err := key2.Thumbprint(crypto.SHA256)
+			require.NoError(t, err, `key2.Thumbprint should succeed`)
+			require.Equal(t, tp1, tp2, `keys generated from the same source should be the same`)
+		}
+
+		key, err := jwk.Generate(jwa.OctetSeq, jwk.WithRandReader(bytes.NewReader(seed)))
+		require.NoError(t, err, `jwk.Generate should succeed`)
+		var raw []byte
+		require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+		require.Equal(t, seed[:32], raw, `oct key should be read from the given source`)
+	})
+}
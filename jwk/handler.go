@@ -0,0 +1,146 @@
+package jwk
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+// SetProviderFunc is a function that returns the `jwk.Set` to be
+// served by the handler created by `jwk.NewHandlerFunc()`. It is
+// called for every request.
+type SetProviderFunc func(context.Context) (Set, error)
+
+type handler struct {
+	provider SetProviderFunc
+	maxAge   time.Duration
+}
+
+// NewHandler creates a `http.Handler` that publishes the public keys
+// in `set` as a JWKS, for example at `/.well-known/jwks.json`.
+//
+// The keys are converted to public keys using `jwk.PublicSetOf()` for
+// each request, so `set` may contain private keys. Symmetric keys are
+// never published.
+//
+// The response carries the `application/jwk-set+json` content type,
+// a strong ETag computed from the response body, and a Cache-Control
+// header (see `jwk.WithMaxAge()`). Requests with a matching
+// If-None-Match header receive a 304 Not Modified response.
+//
+// If `set` is a `jwk.CachedSet`, the keys are retrieved from the
+// underlying `jwk.Cache` for every request.
+func NewHandler(set Set, options ...HandlerOption) http.Handler {
+	return NewHandlerFunc(func(context.Context) (Set, error) {
+		return set, nil
+	}, options...)
+}
+
+// NewHandlerFunc is identical to `jwk.NewHandler()`, except that
+// the `jwk.Set` to be published is obtained by calling `f` for
+// every request.
+func NewHandlerFunc(f SetProviderFunc, options ...HandlerOption) http.Handler {
+	maxAge := 15 * time.Minute
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identMaxAge{}:
+			maxAge = option.Value().(time.Duration)
+		}
+	}
+	return &handler{
+		provider: f,
+		maxAge:   maxAge,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set(`Allow`, `GET, HEAD`)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	buf, err := h.render(r.Context())
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf)
+	etag := `"` + base64.EncodeToString(sum[:]) + `"`
+
+	hdr := w.Header()
+	hdr.Set(`ETag`, etag)
+	if h.maxAge > 0 {
+		hdr.Set(`Cache-Control`, `public, max-age=`+strconv.FormatInt(int64(h.maxAge/time.Second), 10))
+	} else {
+		hdr.Set(`Cache-Control`, `no-cache`)
+	}
+
+	if etagMatches(r.Header.Get(`If-None-Match`), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	hdr.Set(`Content-Type`, `application/jwk-set+json`)
+	hdr.Set(`Content-Length`, strconv.Itoa(len(buf)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(buf)
+}
+
+// render returns the JSON representation of the public keys
+func (h *handler) render(ctx context.Context) ([]byte, error) {
+	set, err := h.provider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to retrieve jwk.Set: %w`, err)
+	}
+
+	// Symmetric keys do not have a public portion, and jwk.PublicKeyOf()
+	// returns them as-is. Make sure that they are never published
+	filtered, err := Select(set, MatchNot(MatchKeyType(jwa.OctetSeq)))
+	if err != nil {
+		return nil, fmt.Errorf(`failed to select keys: %w`, err)
+	}
+
+	pubset, err := PublicSetOf(filtered)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to create public key set: %w`, err)
+	}
+
+	buf, err := json.Marshal(pubset)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to marshal jwk.Set: %w`, err)
+	}
+	return buf, nil
+}
+
+// etagMatches reports whether the value of an If-None-Match header
+// matches `etag`, using the weak comparison function (RFC7232 Section 3.2)
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	if strings.TrimSpace(header) == `*` {
+		return true
+	}
+
+	for _, v := range strings.Split(header, `,`) {
+		v = strings.TrimPrefix(strings.TrimSpace(v), `W/`)
+		if v == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package jwk_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	rsaKey, err := jwxtest.GenerateRsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+	require.NoError(t, rsaKey.Set(jwk.KeyIDKey, `rsa`), `rsaKey.Set should succeed`)
+	ecKey, err := jwxtest.GenerateEcdsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateEcdsaJwk should succeed`)
+	require.NoError(t, ecKey.Set(jwk.KeyIDKey, `ec`), `ecKey.Set should succeed`)
+	symKey, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+	require.NoError(t, symKey.Set(jwk.KeyIDKey, `sym`), `symKey.Set should succeed`)
+
+	set := jwk.NewSet()
+	for _, key := range []jwk.Key{rsaKey, ecKey, symKey} {
+		require.NoError(t, set.AddKey(key), `set.AddKey should succeed`)
+	}
+
+	srv := httptest.NewServer(jwk.NewHandler(set, jwk.WithMaxAge(time.Hour)))
+	defer srv.Close()
+
+	t.Run("GET", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL)
+		require.NoError(t, err, `GET should succeed`)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, `application/jwk-set+json`, res.Header.Get(`Content-Type`))
+		require.Equal(t, `public, max-age=3600`, res.Header.Get(`Cache-Control`))
+		require.NotEmpty(t, res.Header.Get(`ETag`))
+
+		fetched, err := jwk.ParseReader(res.Body)
+		require.NoError(t, err, `jwk.ParseReader should succeed`)
+		require.Equal(t, 2, fetched.Len(), `symmetric keys should not be published`)
+		for _, kid := range []string{`rsa`, `ec`} {
+			key, ok := fetched.LookupKeyID(kid)
+			require.True(t, ok, `key %q should be published`, kid)
+			_, ok = key.Get(`d`)
+			require.False(t, ok, `private key material should not be published`)
+		}
+		_, ok := fetched.LookupKeyID(`sym`)
+		require.False(t, ok, `symmetric keys should not be published`)
+	})
+	t.Run("If-None-Match", func(t *testing.T) {
+		res, err := srv.Client().Get(srv.URL)
+		require.NoError(t, err, `GET should succeed`)
+		res.Body.Close()
+		etag := res.Header.Get(`ETag`)
+
+		for _, v := range []string{etag, `"foo", ` + etag, `W/` + etag, `*`} {
+			req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+			require.NoError(t, err, `http.NewRequest should succeed`)
+			req.Header.Set(`If-None-Match`, v)
+			res, err := srv.Client().Do(req)
+			require.NoError(t, err, `GET should succeed`)
+			res.Body.Close()
+			require.Equal(t, http.StatusNotModified, res.StatusCode, `If-None-Match: %s`, v)
+			require.Equal(t, etag, res.Header.Get(`ETag`))
+		}
+
+		req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+		require.NoError(t, err, `http.NewRequest should succeed`)
+		req.Header.Set(`If-None-Match`, `"foo"`)
+		res, err = srv.Client().Do(req)
+		require.NoError(t, err, `GET should succeed`)
+		res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+	})
+	t.Run("HEAD", func(t *testing.T) {
+		res, err := srv.Client().Head(srv.URL)
+		require.NoError(t, err, `HEAD should succeed`)
+		res.Body.Close()
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.NotEmpty(t, res.Header.Get(`ETag`))
+	})
+	t.Run("POST", func(t *testing.T) {
+		res, err := srv.Client().Post(srv.URL, `application/json`, nil)
+		require.NoError(t, err, `POST should succeed`)
+		res.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, res.StatusCode)
+	})
+	t.Run("NewHandlerFunc", func(t *testing.T) {
+		var fail bool
+		h := jwk.NewHandlerFunc(func(context.Context) (jwk.Set, error) {
+			if fail {
+				return nil, errors.New(`failed`)
+			}
+			return set, nil
+		}, jwk.WithMaxAge(0))
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, `/`, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Equal(t, `no-cache`, w.Header().Get(`Cache-Control`))
+
+		fail = true
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, `/`, nil))
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -16,11 +16,13 @@ import (
 	"io"
 	"math/big"
 
+	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/lestrrat-go/jwx/v2/internal/base64"
 	"github.com/lestrrat-go/jwx/v2/internal/ecutil"
 	"github.com/lestrrat-go/jwx/v2/internal/json"
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 var registry = json.NewRegistry()
@@ -40,6 +42,9 @@ func bigIntToBytes(n *big.Int) ([]byte, error) {
 //   - "crypto/rsa".PrivateKey and "crypto/rsa".PublicKey creates an RSA based key
 //   - "crypto/ecdsa".PrivateKey and "crypto/ecdsa".PublicKey creates an EC based key
 //   - "crypto/ed25519".PrivateKey and "crypto/ed25519".PublicKey creates an OKP based key
+//   - "github.com/cloudflare/circl/sign/ed448".PrivateKey and "github.com/cloudflare/circl/sign/ed448".PublicKey creates an OKP based key
+//   - "github.com/lestrrat-go/jwx/v2/x25519".PrivateKey and "github.com/lestrrat-go/jwx/v2/x25519".PublicKey creates an OKP based key
+//   - "github.com/lestrrat-go/jwx/v2/x448".PrivateKey and "github.com/lestrrat-go/jwx/v2/x448".PublicKey creates an OKP based key
 //   - []byte creates a symmetric key
 func FromRaw(key interface{}) (Key, error) {
 	if key == nil {
@@ -109,6 +114,30 @@ func FromRaw(key interface{}) (Key, error) {
 			return nil, fmt.Errorf(`failed to initialize %T from %T: %w`, k, rawKey, err)
 		}
 		return k, nil
+	case ed448.PrivateKey:
+		k := newOKPPrivateKey()
+		if err := k.FromRaw(rawKey); err != nil {
+			return nil, fmt.Errorf(`failed to initialize %T from %T: %w`, k, rawKey, err)
+		}
+		return k, nil
+	case ed448.PublicKey:
+		k := newOKPPublicKey()
+		if err := k.FromRaw(rawKey); err != nil {
+			return nil, fmt.Errorf(`failed to initialize %T from %T: %w`, k, rawKey, err)
+		}
+		return k, nil
+	case x448.PrivateKey:
+		k := newOKPPrivateKey()
+		if err := k.FromRaw(rawKey); err != nil {
+			return nil, fmt.Errorf(`failed to initialize %T from %T: %w`, k, rawKey, err)
+		}
+		return k, nil
+	case x448.PublicKey:
+		k := newOKPPublicKey()
+		if err := k.FromRaw(rawKey); err != nil {
+			return nil, fmt.Errorf(`failed to initialize %T from %T: %w`, k, rawKey, err)
+		}
+		return k, nil
 	case []byte:
 		k := newSymmetricKey()
 		if err := k.FromRaw(rawKey); err != nil {
@@ -226,6 +255,14 @@ func PublicRawKeyOf(v interface{}) (interface{}, error) {
 		return x.Public(), nil
 	case x25519.PublicKey:
 		return x, nil
+	case ed448.PrivateKey:
+		return x.Public(), nil
+	case ed448.PublicKey:
+		return x, nil
+	case x448.PrivateKey:
+		return x.Public(), nil
+	case x448.PublicKey:
+		return x, nil
 	case []byte:
 		return x, nil
 	default:
@@ -273,14 +310,14 @@ func EncodeX509(v interface{}) (string, []byte, error) {
 			return "", nil, err
 		}
 		return pmECPrivateKey, marshaled, nil
-	case ed25519.PrivateKey:
-		marshaled, err := x509.MarshalPKCS8PrivateKey(v)
+	case ed25519.PrivateKey, ed448.PrivateKey, x448.PrivateKey:
+		marshaled, err := marshalPKCS8PrivateKey(v)
 		if err != nil {
 			return "", nil, err
 		}
 		return pmPrivateKey, marshaled, nil
-	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
-		marshaled, err := x509.MarshalPKIXPublicKey(v)
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, ed448.PublicKey, x448.PublicKey:
+		marshaled, err := marshalPKIXPublicKey(v)
 		if err != nil {
 			return "", nil, err
 		}
@@ -339,13 +376,13 @@ func DecodePEM(src []byte) (interface{}, []byte, error) {
 		return key, rest, nil
 	case pmPublicKey:
 		// XXX *could* return dsa.PublicKey
-		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		key, err := parsePKIXPublicKey(block.Bytes)
 		if err != nil {
 			return nil, nil, fmt.Errorf(`failed to parse PKIX public key: %w`, err)
 		}
 		return key, rest, nil
 	case pmPrivateKey:
-		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		key, err := parsePKCS8PrivateKey(block.Bytes)
 		if err != nil {
 			return nil, nil, fmt.Errorf(`failed to parse PKCS8 private key: %w`, err)
 		}
@@ -397,8 +434,62 @@ func (ctx *setDecodeCtx) IgnoreParseError() bool {
 // Note that a successful parsing of any type of key does NOT necessarily
 // guarantee a valid key. For example, no checks against expiration dates
 // are performed for certificate expiration, no checks against missing
-// parameters are performed, etc.
+// parameters are performed, etc. Use `jwk.WithValidate(true)` to
+// perform the structural checks described in `jwk.Validate()`.
 func ParseKey(data []byte, options ...ParseOption) (Key, error) {
+	key, err := parseKey(data, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if validate, vopts := validateOptions(options); validate {
+		if err := Validate(key, vopts...); err != nil {
+			return nil, fmt.Errorf(`failed to validate key: %w`, err)
+		}
+	}
+	return key, nil
+}
+
+// validateOptions extracts the options related to key validation
+func validateOptions(options []ParseOption) (bool, []ValidateOption) {
+	var validate bool
+	var vopts []ValidateOption
+	for _, option := range options {
+		if vopt, ok := option.(ValidateOption); ok {
+			vopts = append(vopts, vopt)
+			continue
+		}
+		if option.Ident() == (identValidate{}) {
+			//nolint:forcetypeassert
+			validate = option.Value().(bool)
+		}
+	}
+	return validate, vopts
+}
+
+// validateSet validates the keys in the set. If `ignoreError` is true,
+// invalid keys are removed from the set instead
+func validateSet(s Set, ignoreError bool, options []ValidateOption) error {
+	var invalid []Key
+	for i := 0; i < s.Len(); i++ {
+		key, _ := s.Key(i)
+		if err := Validate(key, options...); err != nil {
+			if !ignoreError {
+				return fmt.Errorf(`failed to validate key #%d: %w`, i, err)
+			}
+			invalid = append(invalid, key)
+		}
+	}
+
+	for _, key := range invalid {
+		if err := s.RemoveKey(key); err != nil {
+			return fmt.Errorf(`failed to remove invalid key: %w`, err)
+		}
+	}
+	return nil
+}
+
+func parseKey(data []byte, options ...ParseOption) (Key, error) {
 	var parsePEM bool
 	var localReg *json.Registry
 	for _, option := range options {
@@ -534,13 +625,26 @@ func Parse(src []byte, options ...ParseOption) (Set, error) {
 			}
 			src = bytes.TrimSpace(rest)
 		}
-		return s, nil
+	} else {
+		if err := parseSet(src, s, localReg, ignoreParseError); err != nil {
+			return nil, err
+		}
+	}
+
+	if validate, vopts := validateOptions(options); validate {
+		if err := validateSet(s, ignoreParseError, vopts); err != nil {
+			return nil, err
+		}
 	}
+	return s, nil
+}
+
+func parseSet(src []byte, s Set, localReg *json.Registry, ignoreParseError bool) error {
 
 	if localReg != nil || ignoreParseError {
 		dcKs, ok := s.(KeyWithDecodeCtx)
 		if !ok {
-			return nil, fmt.Errorf(`typed field was requested, but the key set (%T) does not support DecodeCtx`, s)
+			return fmt.Errorf(`typed field was requested, but the key set (%T) does not support DecodeCtx`, s)
 		}
 		dc := &setDecodeCtx{
 			DecodeCtx:        json.NewDecodeCtx(localReg),
@@ -551,10 +655,9 @@ func Parse(src []byte, options ...ParseOption) (Set, error) {
 	}
 
 	if err := json.Unmarshal(src, s); err != nil {
-		return nil, fmt.Errorf(`failed to unmarshal JWK set: %w`, err)
+		return fmt.Errorf(`failed to unmarshal JWK set: %w`, err)
 	}
-
-	return s, nil
+	return nil
 }
 
 // ParseReader parses a JWK set from the incoming byte buffer.
@@ -640,7 +743,7 @@ func cloneKey(src Key) (Key, error) {
 //
 // # Argument must be of type jwk.Key or jwk.Set
 //
-// Currently only EC (including Ed25519) and RSA keys (and jwk.Set
+// Currently only EC (including Ed25519 and Ed448) and RSA keys (and jwk.Set
 // comprised of these key types) are supported.
 func Pem(v interface{}) ([]byte, error) {
 	var set Set
@@ -679,7 +782,7 @@ func asnEncode(key Key) (string, []byte, error) {
 		if err := key.Raw(&rawkey); err != nil {
 			return "", nil, fmt.Errorf(`failed to get raw key from jwk.Key: %w`, err)
 		}
-		buf, err := x509.MarshalPKCS8PrivateKey(rawkey)
+		buf, err := marshalPKCS8PrivateKey(rawkey)
 		if err != nil {
 			return "", nil, fmt.Errorf(`failed to marshal PKCS8: %w`, err)
 		}
@@ -689,7 +792,7 @@ func asnEncode(key Key) (string, []byte, error) {
 		if err := key.Raw(&rawkey); err != nil {
 			return "", nil, fmt.Errorf(`failed to get raw key from jwk.Key: %w`, err)
 		}
-		buf, err := x509.MarshalPKIXPublicKey(rawkey)
+		buf, err := marshalPKIXPublicKey(rawkey)
 		if err != nil {
 			return "", nil, fmt.Errorf(`failed to marshal PKIX: %w`, err)
 		}
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/asn1"
 	"fmt"
 
+	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/lestrrat-go/blackmagic"
 	"github.com/lestrrat-go/jwx/v2/internal/base64"
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 func (k *okpPublicKey) FromRaw(rawKeyIf interface{}) error {
@@ -26,6 +30,14 @@ func (k *okpPublicKey) FromRaw(rawKeyIf interface{}) error {
 		k.x = rawKey
 		crv = jwa.X25519
 		k.crv = &crv
+	case ed448.PublicKey:
+		k.x = rawKey
+		crv = jwa.Ed448
+		k.crv = &crv
+	case x448.PublicKey:
+		k.x = rawKey
+		crv = jwa.X448
+		k.crv = &crv
 	default:
 		return fmt.Errorf(`unknown key type %T`, rawKeyIf)
 	}
@@ -49,6 +61,16 @@ func (k *okpPrivateKey) FromRaw(rawKeyIf interface{}) error {
 		k.x = rawKey.Public().(x25519.PublicKey) //nolint:forcetypeassert
 		crv = jwa.X25519
 		k.crv = &crv
+	case ed448.PrivateKey:
+		k.d = rawKey.Seed()
+		k.x = rawKey.Public().(ed448.PublicKey) //nolint:forcetypeassert
+		crv = jwa.Ed448
+		k.crv = &crv
+	case x448.PrivateKey:
+		k.d = rawKey.Seed()
+		k.x = rawKey.Public().(x448.PublicKey) //nolint:forcetypeassert
+		crv = jwa.X448
+		k.crv = &crv
 	default:
 		return fmt.Errorf(`unknown key type %T`, rawKeyIf)
 	}
@@ -62,6 +84,10 @@ func buildOKPPublicKey(alg jwa.EllipticCurveAlgorithm, xbuf []byte) (interface{}
 		return ed25519.PublicKey(xbuf), nil
 	case jwa.X25519:
 		return x25519.PublicKey(xbuf), nil
+	case jwa.Ed448:
+		return ed448.PublicKey(xbuf), nil
+	case jwa.X448:
+		return x448.PublicKey(xbuf), nil
 	default:
 		return nil, fmt.Errorf(`invalid curve algorithm %s`, alg)
 	}
@@ -99,6 +125,26 @@ func buildOKPPrivateKey(alg jwa.EllipticCurveAlgorithm, xbuf []byte, dbuf []byte
 			return nil, fmt.Errorf(`invalid x value given d value`)
 		}
 		return ret, nil
+	case jwa.Ed448:
+		if len(dbuf) != ed448.SeedSize {
+			return nil, fmt.Errorf(`invalid ed448 seed size: %d`, len(dbuf))
+		}
+		ret := ed448.NewKeyFromSeed(dbuf)
+		//nolint:forcetypeassert
+		if !bytes.Equal(xbuf, ret.Public().(ed448.PublicKey)) {
+			return nil, fmt.Errorf(`invalid x value given d value`)
+		}
+		return ret, nil
+	case jwa.X448:
+		ret, err := x448.NewKeyFromSeed(dbuf)
+		if err != nil {
+			return nil, fmt.Errorf(`unable to construct x448 private key from seed: %w`, err)
+		}
+		//nolint:forcetypeassert
+		if !bytes.Equal(xbuf, ret.Public().(x448.PublicKey)) {
+			return nil, fmt.Errorf(`invalid x value given d value`)
+		}
+		return ret, nil
 	default:
 		return nil, fmt.Errorf(`invalid curve algorithm %s`, alg)
 	}
@@ -181,3 +227,137 @@ func (k okpPrivateKey) Thumbprint(hash crypto.Hash) ([]byte, error) {
 		base64.EncodeToString(k.x),
 	), nil
 }
+
+// crypto/x509 does not know about Ed448 and X448 keys, so we handle
+// the ASN.1 DER encoding ourselves (RFC 8410)
+var (
+	oidX448  = asn1.ObjectIdentifier{1, 3, 101, 111}
+	oidEd448 = asn1.ObjectIdentifier{1, 3, 101, 113}
+)
+
+type okpAlgorithmIdentifier struct {
+	Algorithm asn1.ObjectIdentifier
+}
+
+type okpPublicKeyInfo struct {
+	Algorithm okpAlgorithmIdentifier
+	PublicKey asn1.BitString
+}
+
+type okpPrivateKeyInfo struct {
+	Version    int
+	Algorithm  okpAlgorithmIdentifier
+	PrivateKey []byte
+	Attributes asn1.RawValue  `asn1:"optional,tag:0"`
+	PublicKey  asn1.BitString `asn1:"optional,tag:1"`
+}
+
+// marshalPKIXPublicKey is x509.MarshalPKIXPublicKey, with support for
+// Ed448 and X448 keys
+func marshalPKIXPublicKey(key interface{}) ([]byte, error) {
+	var oid asn1.ObjectIdentifier
+	var buf []byte
+	switch key := key.(type) {
+	case ed448.PublicKey:
+		oid = oidEd448
+		buf = key
+	case x448.PublicKey:
+		oid = oidX448
+		buf = key
+	default:
+		return x509.MarshalPKIXPublicKey(key)
+	}
+
+	return asn1.Marshal(okpPublicKeyInfo{
+		Algorithm: okpAlgorithmIdentifier{Algorithm: oid},
+		PublicKey: asn1.BitString{Bytes: buf, BitLength: 8 * len(buf)},
+	})
+}
+
+// marshalPKCS8PrivateKey is x509.MarshalPKCS8PrivateKey, with support for
+// Ed448 and X448 keys
+func marshalPKCS8PrivateKey(key interface{}) ([]byte, error) {
+	var oid asn1.ObjectIdentifier
+	var seed []byte
+	switch key := key.(type) {
+	case ed448.PrivateKey:
+		oid = oidEd448
+		seed = key.Seed()
+	case x448.PrivateKey:
+		oid = oidX448
+		seed = key.Seed()
+	default:
+		return x509.MarshalPKCS8PrivateKey(key)
+	}
+
+	// CurvePrivateKey ::= OCTET STRING
+	curvePrivateKey, err := asn1.Marshal(seed)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to marshal private key: %w`, err)
+	}
+	return asn1.Marshal(okpPrivateKeyInfo{
+		Algorithm:  okpAlgorithmIdentifier{Algorithm: oid},
+		PrivateKey: curvePrivateKey,
+	})
+}
+
+// parsePKIXPublicKey is x509.ParsePKIXPublicKey, with support for
+// Ed448 and X448 keys
+func parsePKIXPublicKey(der []byte) (interface{}, error) {
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err == nil {
+		return key, nil
+	}
+
+	var info okpPublicKeyInfo
+	if rest, perr := asn1.Unmarshal(der, &info); perr != nil || len(rest) > 0 {
+		return nil, err
+	}
+
+	buf := info.PublicKey.RightAlign()
+	switch {
+	case info.Algorithm.Algorithm.Equal(oidEd448):
+		if len(buf) != ed448.PublicKeySize {
+			return nil, fmt.Errorf(`invalid ed448 public key size: %d`, len(buf))
+		}
+		return ed448.PublicKey(buf), nil
+	case info.Algorithm.Algorithm.Equal(oidX448):
+		if len(buf) != x448.PublicKeySize {
+			return nil, fmt.Errorf(`invalid x448 public key size: %d`, len(buf))
+		}
+		return x448.PublicKey(buf), nil
+	default:
+		return nil, err
+	}
+}
+
+// parsePKCS8PrivateKey is x509.ParsePKCS8PrivateKey, with support for
+// Ed448 and X448 keys
+func parsePKCS8PrivateKey(der []byte) (interface{}, error) {
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err == nil {
+		return key, nil
+	}
+
+	var info okpPrivateKeyInfo
+	if rest, perr := asn1.Unmarshal(der, &info); perr != nil || len(rest) > 0 {
+		return nil, err
+	}
+
+	var seed []byte
+	if _, perr := asn1.Unmarshal(info.PrivateKey, &seed); perr != nil {
+		return nil, fmt.Errorf(`invalid OKP private key: %w`, perr)
+	}
+
+	switch {
+	case info.Algorithm.Algorithm.Equal(oidEd448):
+		if len(seed) != ed448.SeedSize {
+			return nil, fmt.Errorf(`invalid ed448 seed size: %d`, len(seed))
+		}
+		return ed448.NewKeyFromSeed(seed), nil
+	case info.Algorithm.Algorithm.Equal(oidX448):
+		return x448.NewKeyFromSeed(seed)
+	default:
+		return nil, err
+	}
+}
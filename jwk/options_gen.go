@@ -4,18 +4,25 @@ package jwk
 
 import (
 	"crypto"
+	"crypto/x509"
+	"io"
 	"io/fs"
 	"time"
 
 	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/option"
 )
 
 type Option = option.Interface
 
+// AssignKeyIDOption is a type of Option that can be passed to `jwk.AssignKeyID()`.
+// AssignKeyIDOption also implements the `GenerateOption`, and thus can
+// safely be passed to `jwk.Generate()`
 type AssignKeyIDOption interface {
 	Option
 	assignKeyIDOption()
+	generateOption()
 }
 
 type assignKeyIDOption struct {
@@ -24,6 +31,8 @@ type assignKeyIDOption struct {
 
 func (*assignKeyIDOption) assignKeyIDOption() {}
 
+func (*assignKeyIDOption) generateOption() {}
+
 // CacheOption is a type of Option that can be passed to the
 // `jwk.Cache` object.
 type CacheOption interface {
@@ -57,6 +66,31 @@ func (*fetchOption) parseOption() {}
 
 func (*fetchOption) registerOption() {}
 
+// GenerateOption is a type of Option that can be passed to `jwk.Generate()`
+type GenerateOption interface {
+	Option
+	generateOption()
+}
+
+type generateOption struct {
+	Option
+}
+
+func (*generateOption) generateOption() {}
+
+// HandlerOption is a type of Option that can be passed to `jwk.NewHandler()`
+// and `jwk.NewHandlerFunc()`
+type HandlerOption interface {
+	Option
+	handlerOption()
+}
+
+type handlerOption struct {
+	Option
+}
+
+func (*handlerOption) handlerOption() {}
+
 // ParseOption is a type of Option that can be passed to `jwk.Parse()`
 // ParseOption also implmentsthe `ReadFileOption` and `CacheOption`,
 // and thus safely be passed to `jwk.ReadFile` and `(*jwk.Cache).Configure()`
@@ -101,18 +135,121 @@ type registerOption struct {
 
 func (*registerOption) registerOption() {}
 
+// RotationOption is a type of Option that can be passed to `jwk.NewRotationManager()`
+type RotationOption interface {
+	Option
+	rotationOption()
+}
+
+type rotationOption struct {
+	Option
+}
+
+func (*rotationOption) rotationOption() {}
+
+// ValidateOption is a type of Option that can be passed to `jwk.Validate()`.
+// ValidateOption also implements the `ParseOption`, and thus can be
+// passed to `jwk.Parse()` and friends along with `jwk.WithValidate(true)`
+type ValidateOption interface {
+	Option
+	validateOption()
+	fetchOption()
+	parseOption()
+	registerOption()
+	readFileOption()
+}
+
+type validateOption struct {
+	Option
+}
+
+func (*validateOption) validateOption() {}
+
+func (*validateOption) fetchOption() {}
+
+func (*validateOption) parseOption() {}
+
+func (*validateOption) registerOption() {}
+
+func (*validateOption) readFileOption() {}
+
+// VerifyX509Option is a type of Option that can be passed to `jwk.VerifyX509Chain()`.
+// VerifyX509Option also implements the `FetchOption`, and thus can be
+// passed to `jwk.FetchX5U()` and `(*jwk.Cache).Register()` along with `jwk.WithX5U(true)`
+type VerifyX509Option interface {
+	Option
+	verifyX509Option()
+	fetchOption()
+	parseOption()
+	registerOption()
+}
+
+type verifyX509Option struct {
+	Option
+}
+
+func (*verifyX509Option) verifyX509Option() {}
+
+func (*verifyX509Option) fetchOption() {}
+
+func (*verifyX509Option) parseOption() {}
+
+func (*verifyX509Option) registerOption() {}
+
+type identAlgorithm struct{}
+type identAssignKeyID struct{}
+type identCacheStorage struct{}
+type identCurve struct{}
 type identErrSink struct{}
 type identFS struct{}
 type identFetchWhitelist struct{}
 type identHTTPClient struct{}
 type identIgnoreParseError struct{}
+type identKeyGenerator struct{}
+type identKeyOps struct{}
+type identKeySize struct{}
+type identKeyUsage struct{}
 type identLocalRegistry struct{}
+type identMaxAge struct{}
+type identMaxStaleness struct{}
+type identMinRSAKeySize struct{}
 type identMinRefreshInterval struct{}
+type identMinSymmetricKeySize struct{}
+type identNegativeCacheTTL struct{}
 type identPEM struct{}
 type identPostFetcher struct{}
+type identPrePublishPeriod struct{}
+type identRandReader struct{}
 type identRefreshInterval struct{}
 type identRefreshWindow struct{}
+type identRetiredKeyRetention struct{}
+type identRetirementPeriod struct{}
+type identRetryBackoff struct{}
+type identRotationInterval struct{}
+type identRotationStorage struct{}
 type identThumbprintHash struct{}
+type identValidate struct{}
+type identX509CurrentTime struct{}
+type identX509Intermediates struct{}
+type identX509KeyUsages struct{}
+type identX509Roots struct{}
+type identX5U struct{}
+
+func (identAlgorithm) String() string {
+	return "WithAlgorithm"
+}
+
+func (identAssignKeyID) String() string {
+	return "WithAssignKeyID"
+}
+
+func (identCacheStorage) String() string {
+	return "WithCacheStorage"
+}
+
+func (identCurve) String() string {
+	return "WithCurve"
+}
 
 func (identErrSink) String() string {
 	return "WithErrSink"
@@ -134,14 +271,50 @@ func (identIgnoreParseError) String() string {
 	return "WithIgnoreParseError"
 }
 
+func (identKeyGenerator) String() string {
+	return "WithKeyGenerator"
+}
+
+func (identKeyOps) String() string {
+	return "WithKeyOps"
+}
+
+func (identKeySize) String() string {
+	return "WithKeySize"
+}
+
+func (identKeyUsage) String() string {
+	return "WithKeyUsage"
+}
+
 func (identLocalRegistry) String() string {
 	return "withLocalRegistry"
 }
 
+func (identMaxAge) String() string {
+	return "WithMaxAge"
+}
+
+func (identMaxStaleness) String() string {
+	return "WithMaxStaleness"
+}
+
+func (identMinRSAKeySize) String() string {
+	return "WithMinRSAKeySize"
+}
+
 func (identMinRefreshInterval) String() string {
 	return "WithMinRefreshInterval"
 }
 
+func (identMinSymmetricKeySize) String() string {
+	return "WithMinSymmetricKeySize"
+}
+
+func (identNegativeCacheTTL) String() string {
+	return "WithNegativeCacheTTL"
+}
+
 func (identPEM) String() string {
 	return "WithPEM"
 }
@@ -150,6 +323,14 @@ func (identPostFetcher) String() string {
 	return "WithPostFetcher"
 }
 
+func (identPrePublishPeriod) String() string {
+	return "WithPrePublishPeriod"
+}
+
+func (identRandReader) String() string {
+	return "WithRandReader"
+}
+
 func (identRefreshInterval) String() string {
 	return "WithRefreshInterval"
 }
@@ -158,10 +339,88 @@ func (identRefreshWindow) String() string {
 	return "WithRefreshWindow"
 }
 
+func (identRetiredKeyRetention) String() string {
+	return "WithRetiredKeyRetention"
+}
+
+func (identRetirementPeriod) String() string {
+	return "WithRetirementPeriod"
+}
+
+func (identRetryBackoff) String() string {
+	return "WithRetryBackoff"
+}
+
+func (identRotationInterval) String() string {
+	return "WithRotationInterval"
+}
+
+func (identRotationStorage) String() string {
+	return "WithRotationStorage"
+}
+
 func (identThumbprintHash) String() string {
 	return "WithThumbprintHash"
 }
 
+func (identValidate) String() string {
+	return "WithValidate"
+}
+
+func (identX509CurrentTime) String() string {
+	return "WithX509CurrentTime"
+}
+
+func (identX509Intermediates) String() string {
+	return "WithX509Intermediates"
+}
+
+func (identX509KeyUsages) String() string {
+	return "WithX509KeyUsages"
+}
+
+func (identX509Roots) String() string {
+	return "WithX509Roots"
+}
+
+func (identX5U) String() string {
+	return "WithX5U"
+}
+
+// WithAlgorithm specifies the value of the `alg` field of the key
+// generated by `jwk.Generate()`.
+func WithAlgorithm(v jwa.KeyAlgorithm) GenerateOption {
+	return &generateOption{option.New(identAlgorithm{}, v)}
+}
+
+// WithAssignKeyID specifies that `jwk.Generate()` should assign a
+// key ID (`kid`) to the generated key using `jwk.AssignKeyID()`.
+// The options to `jwk.AssignKeyID()` (e.g. `jwk.WithThumbprintHash()`)
+// may also be passed to `jwk.Generate()`
+func WithAssignKeyID(v bool) GenerateOption {
+	return &generateOption{option.New(identAssignKeyID{}, v)}
+}
+
+// WithCacheStorage specifies the `jwk.CacheStorage` object that `jwk.Cache`
+// uses to persist the fetched JWKS, so that they can be restored after
+// the process restarts.
+//
+// Stored JWKS are loaded when their URLs are registered via
+// `(jwk.Cache).Register()`, and are served until they are successfully
+// refreshed. See `jwk.NewFileCacheStorage()` for a file system based
+// implementation.
+func WithCacheStorage(v CacheStorage) CacheOption {
+	return &cacheOption{option.New(identCacheStorage{}, v)}
+}
+
+// WithCurve specifies the curve of the key to be generated by `jwk.Generate()`.
+// This is only applicable to EC and OKP keys.
+//
+// If unspecified, EC keys use P-256, and OKP keys use Ed25519.
+func WithCurve(v jwa.EllipticCurveAlgorithm) GenerateOption {
+	return &generateOption{option.New(identCurve{}, v)}
+}
+
 // WithErrSink specifies the `httprc.ErrSink` object that handles errors
 // that occurred during the cache's execution.
 //
@@ -211,11 +470,70 @@ func WithIgnoreParseError(v bool) ParseOption {
 	return &parseOption{option.New(identIgnoreParseError{}, v)}
 }
 
+// WithKeyGenerator specifies the function used by `jwk.RotationManager`
+// to generate new signing keys. The generated keys must have the `alg`
+// field populated. If the `kid` field is not populated, a key ID is
+// assigned using `jwk.AssignKeyID()`.
+//
+// By default, 2048 bit RSA keys to be used with RS256 are generated.
+func WithKeyGenerator(v KeyGeneratorFunc) RotationOption {
+	return &rotationOption{option.New(identKeyGenerator{}, v)}
+}
+
+// WithKeyOps specifies the value of the `key_ops` field of the key
+// generated by `jwk.Generate()`.
+func WithKeyOps(v KeyOperationList) GenerateOption {
+	return &generateOption{option.New(identKeyOps{}, v)}
+}
+
+// WithKeySize specifies the size of the key to be generated by `jwk.Generate()`,
+// in bits. This is only applicable to RSA and oct (symmetric) keys.
+// For oct keys, the value must be a multiple of 8.
+//
+// If unspecified, RSA keys are 2048 bits long. oct keys are
+// sized according to the algorithm specified via `jwk.WithAlgorithm()`
+// if possible, and 256 bits otherwise.
+func WithKeySize(v int) GenerateOption {
+	return &generateOption{option.New(identKeySize{}, v)}
+}
+
+// WithKeyUsage specifies the value of the `use` field of the key
+// generated by `jwk.Generate()`.
+func WithKeyUsage(v KeyUsageType) GenerateOption {
+	return &generateOption{option.New(identKeyUsage{}, v)}
+}
+
 // This option is only available for internal code. Users don't get to play with it
 func withLocalRegistry(v *json.Registry) ParseOption {
 	return &parseOption{option.New(identLocalRegistry{}, v)}
 }
 
+// WithMaxAge specifies the value of the max-age directive in the
+// Cache-Control header emitted by the handler created by `jwk.NewHandler()`.
+// The default value is 15 minutes.
+//
+// If the value is 0 or negative, `Cache-Control: no-cache` is emitted instead.
+func WithMaxAge(v time.Duration) HandlerOption {
+	return &handlerOption{option.New(identMaxAge{}, v)}
+}
+
+// WithMaxStaleness specifies how long `(jwk.Cache).Get()` keeps returning
+// the last successfully fetched `jwk.Set` while refreshing it fails.
+//
+// The duration is measured from the time of the last successful fetch.
+// Once it has been exceeded, `(jwk.Cache).Get()` returns an error
+// until the `jwk.Set` is successfully refreshed. By default, the last
+// successfully fetched `jwk.Set` is returned indefinitely.
+func WithMaxStaleness(v time.Duration) RegisterOption {
+	return &registerOption{option.New(identMaxStaleness{}, v)}
+}
+
+// WithMinRSAKeySize specifies the minimum size of the RSA modulus in bits
+// that `jwk.Validate()` accepts. The default value is 2048.
+func WithMinRSAKeySize(v int) ValidateOption {
+	return &validateOption{option.New(identMinRSAKeySize{}, v)}
+}
+
 // WithMinRefreshInterval specifies the minimum refresh interval to be used
 // when using `jwk.Cache`. This value is ONLY used if you did not specify
 // a user-supplied static refresh interval via `WithRefreshInterval`.
@@ -239,6 +557,26 @@ func WithMinRefreshInterval(v time.Duration) RegisterOption {
 	return &registerOption{option.New(identMinRefreshInterval{}, v)}
 }
 
+// WithMinSymmetricKeySize specifies the minimum size of symmetric keys in bits
+// that `jwk.Validate()` accepts. The default value is 128.
+//
+// Regardless of this value, symmetric keys must also be large enough
+// for the algorithm specified in their "alg" field.
+func WithMinSymmetricKeySize(v int) ValidateOption {
+	return &validateOption{option.New(identMinSymmetricKeySize{}, v)}
+}
+
+// WithNegativeCacheTTL specifies how long a fetch error is cached when
+// there is no usable `jwk.Set` (either because it was never fetched,
+// or because it exceeded `jwk.WithMaxStaleness`).
+//
+// While the error is cached, `(jwk.Cache).Get()` returns it immediately
+// without accessing the URL. Once it expires, the next call to
+// `(jwk.Cache).Get()` fetches the URL synchronously.
+func WithNegativeCacheTTL(v time.Duration) RegisterOption {
+	return &registerOption{option.New(identNegativeCacheTTL{}, v)}
+}
+
 // WithPEM specifies that the input to `Parse()` is a PEM encoded key.
 func WithPEM(v bool) ParseOption {
 	return &parseOption{option.New(identPEM{}, v)}
@@ -252,6 +590,25 @@ func WithPostFetcher(v PostFetcher) RegisterOption {
 	return &registerOption{option.New(identPostFetcher{}, v)}
 }
 
+// WithPrePublishPeriod specifies how long a new key is published in
+// the public `jwk.Set` before it is used for signing. This should be
+// longer than the interval at which consumers refresh their cached JWKS.
+// The default value is 24 hours.
+func WithPrePublishPeriod(v time.Duration) RotationOption {
+	return &rotationOption{option.New(identPrePublishPeriod{}, v)}
+}
+
+// WithRandReader specifies the source of randomness used by `jwk.Generate()`.
+// If unspecified, `crypto/rand.Reader` is used.
+//
+// This is mostly useful for generating deterministic keys in tests.
+// Please note that depending on the version of Go, some of the key
+// generation routines in the standard library (e.g. RSA and ECDSA)
+// may not read from the given source in a deterministic manner.
+func WithRandReader(v io.Reader) GenerateOption {
+	return &generateOption{option.New(identRandReader{}, v)}
+}
+
 // WithRefreshInterval specifies the static interval between refreshes
 // of jwk.Set objects controlled by jwk.Cache.
 //
@@ -269,6 +626,103 @@ func WithRefreshWindow(v time.Duration) CacheOption {
 	return &cacheOption{option.New(identRefreshWindow{}, v)}
 }
 
+// WithRetiredKeyRetention specifies how long retired keys are kept by
+// `jwk.RotationManager` (without their private key material) after they
+// have been removed from the public `jwk.Set`. Retired keys are discarded
+// once this period has passed. Symmetric keys are discarded as soon as
+// they are retired, regardless of this option.
+// The default value is 30 days.
+func WithRetiredKeyRetention(v time.Duration) RotationOption {
+	return &rotationOption{option.New(identRetiredKeyRetention{}, v)}
+}
+
+// WithRetirementPeriod specifies how long a key is kept in the public
+// `jwk.Set` after it has been replaced by a new key. This should be
+// longer than the lifetime of the tokens signed by the key.
+// The default value is 24 hours.
+func WithRetirementPeriod(v time.Duration) RotationOption {
+	return &rotationOption{option.New(identRetirementPeriod{}, v)}
+}
+
+// WithRetryBackoff specifies the initial delay before retrying a
+// failed fetch. The delay is doubled for each consecutive failure,
+// up to the refresh interval (`jwk.WithRefreshInterval`) or, if it is
+// not specified, the minimum refresh interval (`jwk.WithMinRefreshInterval`).
+//
+// By default, failed fetches are retried at the next scheduled refresh.
+func WithRetryBackoff(v time.Duration) RegisterOption {
+	return &registerOption{option.New(identRetryBackoff{}, v)}
+}
+
+// WithRotationInterval specifies how long a key is used for signing
+// before it is replaced by a new key. The default value is 30 days.
+func WithRotationInterval(v time.Duration) RotationOption {
+	return &rotationOption{option.New(identRotationInterval{}, v)}
+}
+
+// WithRotationStorage specifies the `jwk.RotationStorage` used by
+// `jwk.RotationManager` to persist the keys and their states.
+func WithRotationStorage(v RotationStorage) RotationOption {
+	return &rotationOption{option.New(identRotationStorage{}, v)}
+}
+
 func WithThumbprintHash(v crypto.Hash) AssignKeyIDOption {
 	return &assignKeyIDOption{option.New(identThumbprintHash{}, v)}
 }
+
+// WithValidate specifies that the keys should be validated using
+// `jwk.Validate()` after they have been parsed. `jwk.ValidateOption`s
+// such as `jwk.WithMinRSAKeySize()` may be passed along with this option.
+//
+// When used with `jwk.Parse()` and `jwk.WithIgnoreParseError(true)`,
+// keys that fail validation are dropped from the resulting `jwk.Set`
+// instead of causing an error.
+func WithValidate(v bool) ParseOption {
+	return &parseOption{option.New(identValidate{}, v)}
+}
+
+// WithX509CurrentTime specifies the time at which the validity of
+// the certificates are checked by `jwk.VerifyX509Chain()`.
+// By default the current system time is used.
+func WithX509CurrentTime(v time.Time) VerifyX509Option {
+	return &verifyX509Option{option.New(identX509CurrentTime{}, v)}
+}
+
+// WithX509Intermediates specifies additional intermediate certificates
+// that `jwk.VerifyX509Chain()` may use to build the certificate chain,
+// in addition to the certificates found in the "x5c" field of the key.
+func WithX509Intermediates(v []*x509.Certificate) VerifyX509Option {
+	return &verifyX509Option{option.New(identX509Intermediates{}, v)}
+}
+
+// WithX509KeyUsages specifies the extended key usages that the
+// certificate chain must be valid for. Certificates that do not
+// have the extended key usage extension are valid for any usage.
+//
+// By default any extended key usage is accepted. Note that this
+// differs from `(*x509.Certificate).Verify()`, which defaults to
+// `x509.ExtKeyUsageServerAuth`.
+func WithX509KeyUsages(v []x509.ExtKeyUsage) VerifyX509Option {
+	return &verifyX509Option{option.New(identX509KeyUsages{}, v)}
+}
+
+// WithX509Roots specifies the set of trusted root certificates that
+// `jwk.VerifyX509Chain()` verifies the certificate chain against.
+//
+// If this option is not specified, the system root certificates are used.
+func WithX509Roots(v *x509.CertPool) VerifyX509Option {
+	return &verifyX509Option{option.New(identX509Roots{}, v)}
+}
+
+// WithX5U specifies that the URL being registered to `jwk.Cache` points to
+// a PEM encoded X.509 certificate chain, such as those referred to by "x5u"
+// fields, instead of a JWKS. The chain is converted to a `jwk.Set`
+// as described in `jwk.FetchX5U()`.
+//
+// The trusted root certificates MUST be specified by passing
+// `jwk.WithX509Roots()` along with this option, otherwise `Register()`
+// fails. Other `jwk.VerifyX509Option`s may also be passed to control
+// how the chain is verified.
+func WithX5U(v bool) RegisterOption {
+	return &registerOption{option.New(identX5U{}, v)}
+}
@@ -9,16 +9,42 @@ import (
 )
 
 func TestOptionIdent(t *testing.T) {
+	require.Equal(t, "WithAlgorithm", identAlgorithm{}.String())
+	require.Equal(t, "WithAssignKeyID", identAssignKeyID{}.String())
+	require.Equal(t, "WithCacheStorage", identCacheStorage{}.String())
+	require.Equal(t, "WithCurve", identCurve{}.String())
 	require.Equal(t, "WithErrSink", identErrSink{}.String())
 	require.Equal(t, "WithFS", identFS{}.String())
 	require.Equal(t, "WithFetchWhitelist", identFetchWhitelist{}.String())
 	require.Equal(t, "WithHTTPClient", identHTTPClient{}.String())
 	require.Equal(t, "WithIgnoreParseError", identIgnoreParseError{}.String())
+	require.Equal(t, "WithKeyGenerator", identKeyGenerator{}.String())
+	require.Equal(t, "WithKeyOps", identKeyOps{}.String())
+	require.Equal(t, "WithKeySize", identKeySize{}.String())
+	require.Equal(t, "WithKeyUsage", identKeyUsage{}.String())
 	require.Equal(t, "withLocalRegistry", identLocalRegistry{}.String())
+	require.Equal(t, "WithMaxAge", identMaxAge{}.String())
+	require.Equal(t, "WithMaxStaleness", identMaxStaleness{}.String())
+	require.Equal(t, "WithMinRSAKeySize", identMinRSAKeySize{}.String())
 	require.Equal(t, "WithMinRefreshInterval", identMinRefreshInterval{}.String())
+	require.Equal(t, "WithMinSymmetricKeySize", identMinSymmetricKeySize{}.String())
+	require.Equal(t, "WithNegativeCacheTTL", identNegativeCacheTTL{}.String())
 	require.Equal(t, "WithPEM", identPEM{}.String())
 	require.Equal(t, "WithPostFetcher", identPostFetcher{}.String())
+	require.Equal(t, "WithPrePublishPeriod", identPrePublishPeriod{}.String())
+	require.Equal(t, "WithRandReader", identRandReader{}.String())
 	require.Equal(t, "WithRefreshInterval", identRefreshInterval{}.String())
 	require.Equal(t, "WithRefreshWindow", identRefreshWindow{}.String())
+	require.Equal(t, "WithRetiredKeyRetention", identRetiredKeyRetention{}.String())
+	require.Equal(t, "WithRetirementPeriod", identRetirementPeriod{}.String())
+	require.Equal(t, "WithRetryBackoff", identRetryBackoff{}.String())
+	require.Equal(t, "WithRotationInterval", identRotationInterval{}.String())
+	require.Equal(t, "WithRotationStorage", identRotationStorage{}.String())
 	require.Equal(t, "WithThumbprintHash", identThumbprintHash{}.String())
+	require.Equal(t, "WithValidate", identValidate{}.String())
+	require.Equal(t, "WithX509CurrentTime", identX509CurrentTime{}.String())
+	require.Equal(t, "WithX509Intermediates", identX509Intermediates{}.String())
+	require.Equal(t, "WithX509KeyUsages", identX509KeyUsages{}.String())
+	require.Equal(t, "WithX509Roots", identX509Roots{}.String())
+	require.Equal(t, "WithX5U", identX5U{}.String())
 }
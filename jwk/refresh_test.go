@@ -3,6 +3,7 @@ package jwk_test
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -14,6 +15,7 @@ import (
 	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 //nolint:revive,golint
@@ -549,3 +551,330 @@ func TestPostFetch(t *testing.T) {
 		})
 	}
 }
+
+func TestCacheStorage(t *testing.T) {
+	t.Parallel()
+
+	newSet := func(t *testing.T, kid string) jwk.Set {
+		t.Helper()
+		key, err := jwxtest.GenerateRsaJwk()
+		require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+		require.NoError(t, key.Set(jwk.KeyIDKey, kid), `key.Set should succeed`)
+		pubkey, err := jwk.PublicKeyOf(key)
+		require.NoError(t, err, `jwk.PublicKeyOf should succeed`)
+		set := jwk.NewSet()
+		require.NoError(t, set.AddKey(pubkey), `set.AddKey should succeed`)
+		return set
+	}
+
+	type server struct {
+		mu     sync.Mutex
+		set    jwk.Set
+		fail   bool
+		access int
+	}
+	startServer := func(t *testing.T, s *server) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.access++
+			if s.fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(s.set)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+
+	t.Run("Restore stored JWKS while the server is down", func(t *testing.T) {
+		t.Parallel()
+		s := &server{set: newSet(t, "key-1")}
+		srv := startServer(t, s)
+		storage := jwk.NewFileCacheStorage(t.TempDir())
+
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		c1 := jwk.NewCache(ctx1, jwk.WithCacheStorage(storage), jwk.WithRefreshWindow(time.Second))
+		require.NoError(t, c1.Register(srv.URL, jwk.WithRefreshInterval(time.Hour)), `c1.Register should succeed`)
+		_, err := c1.Refresh(ctx1, srv.URL)
+		require.NoError(t, err, `c1.Refresh should succeed`)
+		cancel1()
+
+		entry, err := storage.Load(context.Background(), srv.URL)
+		require.NoError(t, err, `storage.Load should succeed`)
+		require.NotNil(t, entry, `entry should have been stored`)
+		require.Equal(t, srv.URL, entry.URL, `entry.URL should match`)
+		require.True(t, entry.NextRefresh.After(entry.LastFetched), `entry.NextRefresh should be after entry.LastFetched`)
+
+		// simulate a restart while the server is down
+		s.mu.Lock()
+		s.fail = true
+		access := s.access
+		s.mu.Unlock()
+
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		defer cancel2()
+		c2 := jwk.NewCache(ctx2, jwk.WithCacheStorage(storage), jwk.WithRefreshWindow(time.Second))
+		require.NoError(t, c2.Register(srv.URL, jwk.WithRefreshInterval(time.Hour)), `c2.Register should succeed`)
+
+		set, err := c2.Get(ctx2, srv.URL)
+		require.NoError(t, err, `c2.Get should succeed`)
+		_, ok := set.LookupKeyID("key-1")
+		require.True(t, ok, `stored key should be available`)
+
+		s.mu.Lock()
+		require.Equal(t, access, s.access, `server should not have been accessed before the next refresh`)
+		s.mu.Unlock()
+
+		snapshot := c2.Snapshot()
+		require.Len(t, snapshot.Entries, 1, `there should be one entry`)
+		require.Equal(t, entry.LastFetched.Unix(), snapshot.Entries[0].LastFetched.Unix(), `LastFetched should be restored`)
+		require.Equal(t, set, snapshot.Entries[0].Data, `Data should be the stored set`)
+	})
+	t.Run("Refresh stored JWKS when due", func(t *testing.T) {
+		t.Parallel()
+		s := &server{set: newSet(t, "key-2")}
+		srv := startServer(t, s)
+		storage := jwk.NewFileCacheStorage(t.TempDir())
+
+		stale := newSet(t, "key-1")
+		buf, err := json.Marshal(stale)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		require.NoError(t, storage.Store(context.Background(), &jwk.CacheEntry{
+			URL:         srv.URL,
+			Data:        buf,
+			LastFetched: time.Now().Add(-2 * time.Hour),
+			NextRefresh: time.Now().Add(-time.Hour),
+		}), `storage.Store should succeed`)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx, jwk.WithCacheStorage(storage), jwk.WithRefreshWindow(time.Second))
+		require.NoError(t, c.Register(srv.URL, jwk.WithRefreshInterval(time.Hour)), `c.Register should succeed`)
+
+		require.Eventually(t, func() bool {
+			set, err := c.Get(ctx, srv.URL)
+			if err != nil {
+				return false
+			}
+			_, ok := set.LookupKeyID("key-2")
+			return ok
+		}, 5*time.Second, 50*time.Millisecond, `refreshed key should become available`)
+
+		require.Eventually(t, func() bool {
+			entry, err := storage.Load(ctx, srv.URL)
+			return err == nil && entry != nil && entry.NextRefresh.After(time.Now())
+		}, 5*time.Second, 50*time.Millisecond, `storage should have been updated`)
+	})
+	t.Run("Missing entry", func(t *testing.T) {
+		t.Parallel()
+		storage := jwk.NewFileCacheStorage(t.TempDir())
+		entry, err := storage.Load(context.Background(), "https://example.com/jwks.json")
+		require.NoError(t, err, `storage.Load should succeed`)
+		require.Nil(t, entry, `entry should be nil`)
+	})
+}
+
+func TestCachePolicy(t *testing.T) {
+	t.Parallel()
+
+	key, err := jwxtest.GenerateRsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+	pubkey, err := jwk.PublicKeyOf(key)
+	require.NoError(t, err, `jwk.PublicKeyOf should succeed`)
+	set := jwk.NewSet()
+	require.NoError(t, set.AddKey(pubkey), `set.AddKey should succeed`)
+
+	type server struct {
+		mu     sync.Mutex
+		fail   bool
+		delay  time.Duration
+		access int
+	}
+	startServer := func(t *testing.T, s *server) *httptest.Server {
+		t.Helper()
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			s.mu.Lock()
+			delay := s.delay
+			s.mu.Unlock()
+			time.Sleep(delay)
+
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.access++
+			if s.fail {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			json.NewEncoder(w).Encode(set)
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	setFail := func(s *server, v bool) {
+		s.mu.Lock()
+		s.fail = v
+		s.mu.Unlock()
+	}
+	accessCount := func(s *server) int {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.access
+	}
+
+	t.Run("WithMaxStaleness", func(t *testing.T) {
+		t.Parallel()
+		s := &server{}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithMaxStaleness(500*time.Millisecond)), `c.Register should succeed`)
+
+		_, err := c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should succeed`)
+
+		setFail(s, true)
+		_, err = c.Refresh(ctx, srv.URL)
+		require.Error(t, err, `c.Refresh should fail`)
+
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should return the stale set`)
+
+		time.Sleep(600 * time.Millisecond)
+		_, err = c.Get(ctx, srv.URL)
+		require.Error(t, err, `c.Get should refuse the stale set`)
+
+		setFail(s, false)
+		_, err = c.Refresh(ctx, srv.URL)
+		require.NoError(t, err, `c.Refresh should succeed`)
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should succeed after a successful refresh`)
+	})
+	t.Run("WithNegativeCacheTTL", func(t *testing.T) {
+		t.Parallel()
+		s := &server{fail: true}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithNegativeCacheTTL(500*time.Millisecond)), `c.Register should succeed`)
+
+		_, err := c.Get(ctx, srv.URL)
+		require.Error(t, err, `c.Get should fail`)
+		require.Equal(t, 1, accessCount(s), `server should have been accessed once`)
+
+		for i := 0; i < 5; i++ {
+			_, err = c.Get(ctx, srv.URL)
+			require.Error(t, err, `c.Get should return the cached error`)
+		}
+		require.Equal(t, 1, accessCount(s), `server should not be accessed while the error is cached`)
+
+		setFail(s, false)
+		time.Sleep(600 * time.Millisecond)
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should succeed once the cached error expires`)
+		require.Equal(t, 2, accessCount(s), `server should have been accessed again`)
+	})
+	t.Run("Canceled requests", func(t *testing.T) {
+		t.Parallel()
+		s := &server{}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithNegativeCacheTTL(time.Hour), jwk.WithRetryBackoff(time.Hour)), `c.Register should succeed`)
+
+		canceled, cancelReq := context.WithCancel(ctx)
+		cancelReq()
+		_, err := c.Get(canceled, srv.URL)
+		require.True(t, errors.Is(err, context.Canceled), `c.Get should fail with a canceled context`)
+		_, err = c.Refresh(canceled, srv.URL)
+		require.True(t, errors.Is(err, context.Canceled), `c.Refresh should fail with a canceled context`)
+
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should succeed, as canceled requests are not cached as failures`)
+		require.Equal(t, 1, accessCount(s), `server should have been accessed once`)
+	})
+	t.Run("Timed out request", func(t *testing.T) {
+		t.Parallel()
+		s := &server{delay: 500 * time.Millisecond}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithNegativeCacheTTL(time.Hour), jwk.WithRetryBackoff(time.Hour)), `c.Register should succeed`)
+
+		timeout, cancelReq := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancelReq()
+		_, err := c.Get(timeout, srv.URL)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), `c.Get should time out`)
+
+		s.mu.Lock()
+		s.delay = 0
+		s.mu.Unlock()
+
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should succeed, as the timed out request is not cached as a failure`)
+	})
+	t.Run("Canceled refetch after negative cache TTL", func(t *testing.T) {
+		t.Parallel()
+		s := &server{fail: true}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithNegativeCacheTTL(300*time.Millisecond)), `c.Register should succeed`)
+
+		_, err := c.Get(ctx, srv.URL)
+		require.Error(t, err, `c.Get should fail`)
+		time.Sleep(400 * time.Millisecond)
+
+		s.mu.Lock()
+		s.fail = false
+		s.delay = 500 * time.Millisecond
+		s.mu.Unlock()
+
+		timeout, cancelReq := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancelReq()
+		_, err = c.Get(timeout, srv.URL)
+		require.True(t, errors.Is(err, context.DeadlineExceeded), `c.Get should time out`)
+
+		_, err = c.Get(ctx, srv.URL)
+		require.NoError(t, err, `c.Get should fetch again, as the timed out request did not extend the cached error`)
+	})
+	t.Run("WithRetryBackoff", func(t *testing.T) {
+		t.Parallel()
+		s := &server{fail: true}
+		srv := startServer(t, s)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(srv.URL, jwk.WithRetryBackoff(100*time.Millisecond)), `c.Register should succeed`)
+
+		_, err := c.Get(ctx, srv.URL)
+		require.Error(t, err, `c.Get should fail`)
+
+		// retries happen at +100ms, +300ms, +700ms, +1500ms...
+		time.Sleep(1100 * time.Millisecond)
+		count := accessCount(s)
+		require.GreaterOrEqual(t, count, 3, `server should have been retried`)
+		require.LessOrEqual(t, count, 5, `retries should back off`)
+
+		setFail(s, false)
+		require.Eventually(t, func() bool {
+			_, err := c.Get(ctx, srv.URL)
+			return err == nil
+		}, 5*time.Second, 50*time.Millisecond, `c.Get should eventually succeed`)
+	})
+}
@@ -0,0 +1,504 @@
+package jwk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+)
+
+// KeyState represents the lifecycle state of a key managed by
+// `jwk.RotationManager`.
+type KeyState int
+
+const (
+	// KeyStatePending keys are published, but not yet used for signing
+	KeyStatePending KeyState = iota + 1
+	// KeyStateActive is the state of the key currently used for signing
+	KeyStateActive
+	// KeyStateRetiring keys are no longer used for signing, but are
+	// still published so that the tokens signed by them can be verified
+	KeyStateRetiring
+	// KeyStateRetired keys are neither used for signing nor published.
+	// Their private key material is discarded, and the keys themselves
+	// are discarded after the period specified by
+	// `jwk.WithRetiredKeyRetention()`. Symmetric keys are discarded as
+	// soon as they are retired.
+	KeyStateRetired
+)
+
+func (s KeyState) String() string {
+	switch s {
+	case KeyStatePending:
+		return `pending`
+	case KeyStateActive:
+		return `active`
+	case KeyStateRetiring:
+		return `retiring`
+	case KeyStateRetired:
+		return `retired`
+	default:
+		return fmt.Sprintf(`KeyState(%d)`, int(s))
+	}
+}
+
+func (s KeyState) MarshalText() ([]byte, error) {
+	switch s {
+	case KeyStatePending, KeyStateActive, KeyStateRetiring, KeyStateRetired:
+		return []byte(s.String()), nil
+	default:
+		return nil, fmt.Errorf(`invalid key state %d`, int(s))
+	}
+}
+
+func (s *KeyState) UnmarshalText(data []byte) error {
+	switch string(data) {
+	case `pending`:
+		*s = KeyStatePending
+	case `active`:
+		*s = KeyStateActive
+	case `retiring`:
+		*s = KeyStateRetiring
+	case `retired`:
+		*s = KeyStateRetired
+	default:
+		return fmt.Errorf(`invalid key state %q`, string(data))
+	}
+	return nil
+}
+
+// ManagedKey is a key managed by `jwk.RotationManager`, along with
+// its lifecycle state.
+type ManagedKey struct {
+	Key   Key
+	State KeyState
+	// CreatedAt is the time when the key was generated
+	CreatedAt time.Time
+	// ActivateAt is the time when the key is (or was) scheduled to be
+	// used for signing
+	ActivateAt time.Time
+	// DeactivatedAt is the time when the key stopped being used for
+	// signing. It is zero unless the key is retiring or retired
+	DeactivatedAt time.Time
+	// RetireAt is the time when the key is (or was) scheduled to be
+	// removed from the public `jwk.Set`. It is zero unless the key is
+	// retiring or retired
+	RetireAt time.Time
+}
+
+type managedKeyJSON struct {
+	Key           json.RawMessage `json:"key"`
+	State         KeyState        `json:"state"`
+	CreatedAt     time.Time       `json:"created_at"`
+	ActivateAt    time.Time       `json:"activate_at"`
+	DeactivatedAt time.Time       `json:"deactivated_at,omitempty"`
+	RetireAt      time.Time       `json:"retire_at,omitempty"`
+}
+
+func (mk *ManagedKey) MarshalJSON() ([]byte, error) {
+	buf, err := json.Marshal(mk.Key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to marshal key: %w`, err)
+	}
+	return json.Marshal(managedKeyJSON{
+		Key:           buf,
+		State:         mk.State,
+		CreatedAt:     mk.CreatedAt,
+		ActivateAt:    mk.ActivateAt,
+		DeactivatedAt: mk.DeactivatedAt,
+		RetireAt:      mk.RetireAt,
+	})
+}
+
+func (mk *ManagedKey) UnmarshalJSON(data []byte) error {
+	var proxy managedKeyJSON
+	if err := json.Unmarshal(data, &proxy); err != nil {
+		return fmt.Errorf(`failed to unmarshal managed key: %w`, err)
+	}
+
+	key, err := ParseKey(proxy.Key)
+	if err != nil {
+		return fmt.Errorf(`failed to parse key: %w`, err)
+	}
+
+	*mk = ManagedKey{
+		Key:           key,
+		State:         proxy.State,
+		CreatedAt:     proxy.CreatedAt,
+		ActivateAt:    proxy.ActivateAt,
+		DeactivatedAt: proxy.DeactivatedAt,
+		RetireAt:      proxy.RetireAt,
+	}
+	return nil
+}
+
+// KeyGeneratorFunc is a function that generates a new private key.
+// See `jwk.WithKeyGenerator()`
+type KeyGeneratorFunc func() (Key, error)
+
+// RotationStorage is used by `jwk.RotationManager` to persist the keys
+// and their lifecycle states, so that they survive process restarts.
+//
+// Note that the stored keys contain private key material.
+type RotationStorage interface {
+	// Load returns the stored keys. If nothing has been stored yet,
+	// Load should return an empty list without an error.
+	Load(ctx context.Context) ([]*ManagedKey, error)
+	// Store saves the keys, replacing the previously stored keys.
+	Store(ctx context.Context, keys []*ManagedKey) error
+}
+
+type fileRotationStorage struct {
+	path string
+}
+
+// NewFileRotationStorage creates a `jwk.RotationStorage` that stores
+// the keys as a JSON file at `path`. The file is created with permissions
+// that only allow the owner to access it.
+func NewFileRotationStorage(path string) RotationStorage {
+	return &fileRotationStorage{path: path}
+}
+
+func (s *fileRotationStorage) Load(_ context.Context) ([]*ManagedKey, error) {
+	buf, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf(`failed to read keys: %w`, err)
+	}
+
+	var keys []*ManagedKey
+	if err := json.Unmarshal(buf, &keys); err != nil {
+		return nil, fmt.Errorf(`failed to parse keys: %w`, err)
+	}
+	return keys, nil
+}
+
+func (s *fileRotationStorage) Store(_ context.Context, keys []*ManagedKey) error {
+	buf, err := json.Marshal(keys)
+	if err != nil {
+		return fmt.Errorf(`failed to marshal keys: %w`, err)
+	}
+
+	if err := writeFileAtomic(s.path, buf); err != nil {
+		return fmt.Errorf(`failed to save keys: %w`, err)
+	}
+	return nil
+}
+
+// RotationManager manages the lifecycle of signing keys:
+//
+//  1. A new key is generated, and is published in the public `jwk.Set`
+//     (pending) for the pre-publish period before it is used
+//  2. The key becomes the signing key (active), and the previous signing
+//     key stops being used for signing
+//  3. The previous signing key is kept in the public `jwk.Set` (retiring)
+//     for the retirement period, so that the tokens signed by it can
+//     still be verified
+//  4. The previous signing key is removed from the public `jwk.Set` (retired),
+//     and is discarded after the retention period
+//
+// A new key is generated every rotation interval. State transitions only
+// happen when `Tick()` or `Rotate()` is called, which receive the current
+// time from the caller. In production you would typically call `Tick()`
+// periodically:
+//
+//	m, err := jwk.NewRotationManager(ctx, jwk.WithRotationStorage(storage))
+//	...
+//	go func() {
+//	  ticker := time.NewTicker(time.Minute)
+//	  defer ticker.Stop()
+//	  for {
+//	    select {
+//	    case <-ctx.Done():
+//	      return
+//	    case now := <-ticker.C:
+//	      if err := m.Tick(ctx, now); err != nil {
+//	        log.Printf("failed to rotate keys: %s", err)
+//	      }
+//	    }
+//	  }
+//	}()
+//
+//	// sign using the current signing key
+//	key, err := m.SigningKey()
+//	signed, err := jwt.Sign(token, jwt.WithKey(key.Algorithm(), key))
+//
+//	// publish the public keys
+//	http.Handle("/.well-known/jwks.json", jwk.NewHandlerFunc(m.PublicSet))
+//
+// A single `jwk.RotationManager` is not meant to be shared by multiple
+// processes through the same `jwk.RotationStorage`.
+type RotationManager struct {
+	generate   KeyGeneratorFunc
+	interval   time.Duration
+	prePublish time.Duration
+	retirement time.Duration
+	retention  time.Duration
+	storage    RotationStorage
+
+	mu    sync.RWMutex
+	keys  []*ManagedKey
+	dirty bool // true if the keys have not been persisted since the last change
+}
+
+func defaultKeyGenerator() (Key, error) {
+	return Generate(jwa.RSA, WithAlgorithm(jwa.RS256), WithKeyUsage(ForSignature))
+}
+
+// NewRotationManager creates a new `jwk.RotationManager`. If a
+// `jwk.RotationStorage` is specified, the keys are loaded from it.
+//
+// No keys are generated until `Tick()` or `Rotate()` is called.
+func NewRotationManager(ctx context.Context, options ...RotationOption) (*RotationManager, error) {
+	m := &RotationManager{
+		generate:   defaultKeyGenerator,
+		interval:   30 * 24 * time.Hour,
+		prePublish: 24 * time.Hour,
+		retirement: 24 * time.Hour,
+		retention:  30 * 24 * time.Hour,
+	}
+
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identKeyGenerator{}:
+			m.generate = option.Value().(KeyGeneratorFunc)
+		case identRotationInterval{}:
+			m.interval = option.Value().(time.Duration)
+		case identPrePublishPeriod{}:
+			m.prePublish = option.Value().(time.Duration)
+		case identRetirementPeriod{}:
+			m.retirement = option.Value().(time.Duration)
+		case identRetiredKeyRetention{}:
+			m.retention = option.Value().(time.Duration)
+		case identRotationStorage{}:
+			m.storage = option.Value().(RotationStorage)
+		}
+	}
+
+	if m.interval <= 0 {
+		return nil, fmt.Errorf(`rotation interval must be positive`)
+	}
+
+	if m.storage != nil {
+		keys, err := m.storage.Load(ctx)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to load keys: %w`, err)
+		}
+		m.keys = keys
+	}
+	return m, nil
+}
+
+func (m *RotationManager) newKey(now, activateAt time.Time) (*ManagedKey, error) {
+	key, err := m.generate()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to generate key: %w`, err)
+	}
+	if key.Algorithm().String() == "" {
+		return nil, fmt.Errorf(`generated key does not have the "alg" field`)
+	}
+	if err := AssignKeyID(key); err != nil {
+		return nil, fmt.Errorf(`failed to assign key ID: %w`, err)
+	}
+
+	return &ManagedKey{
+		Key:        key,
+		State:      KeyStatePending,
+		CreatedAt:  now,
+		ActivateAt: activateAt,
+	}, nil
+}
+
+// find returns the first key in the given state. Must be called
+// while holding the lock
+func (m *RotationManager) find(state KeyState) *ManagedKey {
+	for _, mk := range m.keys {
+		if mk.State == state {
+			return mk
+		}
+	}
+	return nil
+}
+
+// Tick performs the state transitions that are due at `now`:
+// pending keys are activated, retiring keys are retired, and a new
+// pending key is generated if the active key is due to be replaced
+// within the pre-publish period. If there are no keys, a new key is
+// generated and activated immediately.
+//
+// The keys are persisted if anything has changed. If persisting fails,
+// it is attempted again on the next call.
+func (m *RotationManager) Tick(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tick(ctx, now, false)
+}
+
+// Rotate starts replacing the active key at `now`, regardless of the
+// rotation interval: a new pending key is generated, which becomes
+// active after the pre-publish period. If a pending key already exists,
+// it is scheduled to be activated no later than that.
+//
+// Use `jwk.WithPrePublishPeriod(0)` if keys should be replaced immediately.
+func (m *RotationManager) Rotate(ctx context.Context, now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.tick(ctx, now, true)
+}
+
+func (m *RotationManager) tick(ctx context.Context, now time.Time, force bool) error {
+	var changed bool
+
+	active := m.find(KeyStateActive)
+	pending := m.find(KeyStatePending)
+
+	// Schedule the next key
+	switch {
+	case active == nil && pending == nil:
+		mk, err := m.newKey(now, now)
+		if err != nil {
+			return err
+		}
+		m.keys = append(m.keys, mk)
+		pending = mk
+		changed = true
+	case force && pending == nil:
+		mk, err := m.newKey(now, now.Add(m.prePublish))
+		if err != nil {
+			return err
+		}
+		m.keys = append(m.keys, mk)
+		pending = mk
+		changed = true
+	case force:
+		if activateAt := now.Add(m.prePublish); activateAt.Before(pending.ActivateAt) {
+			pending.ActivateAt = activateAt
+			changed = true
+		}
+	case active != nil && pending == nil && !now.Before(active.ActivateAt.Add(m.interval-m.prePublish)):
+		// Never activate a key before it has been published for
+		// the pre-publish period
+		activateAt := active.ActivateAt.Add(m.interval)
+		if earliest := now.Add(m.prePublish); activateAt.Before(earliest) {
+			activateAt = earliest
+		}
+		mk, err := m.newKey(now, activateAt)
+		if err != nil {
+			return err
+		}
+		m.keys = append(m.keys, mk)
+		pending = mk
+		changed = true
+	}
+
+	// Activate the pending key. If there is no active key (which
+	// should only happen if the keys were modified externally), the
+	// pending key is activated immediately
+	if pending != nil && (active == nil || !now.Before(pending.ActivateAt)) {
+		if active != nil {
+			active.State = KeyStateRetiring
+			active.DeactivatedAt = now
+			active.RetireAt = now.Add(m.retirement)
+		}
+		pending.State = KeyStateActive
+		if now.Before(pending.ActivateAt) {
+			pending.ActivateAt = now
+		}
+		changed = true
+	}
+
+	// Retire keys, and discard retired keys after the retention period.
+	// Symmetric keys are discarded right away, as there is no way to
+	// separate them from their secret
+	keys := make([]*ManagedKey, 0, len(m.keys))
+	for _, mk := range m.keys {
+		if mk.State == KeyStateRetiring && !now.Before(mk.RetireAt) {
+			if mk.Key.KeyType() != jwa.OctetSeq {
+				pubkey, err := PublicKeyOf(mk.Key)
+				if err != nil {
+					return fmt.Errorf(`failed to get public key of %q: %w`, mk.Key.KeyID(), err)
+				}
+				mk.Key = pubkey
+			}
+			mk.State = KeyStateRetired
+			changed = true
+		}
+
+		if mk.State == KeyStateRetired && (mk.Key.KeyType() == jwa.OctetSeq || !now.Before(mk.RetireAt.Add(m.retention))) {
+			changed = true
+			continue
+		}
+		keys = append(keys, mk)
+	}
+	m.keys = keys
+
+	if changed {
+		m.dirty = true
+	}
+	if m.dirty && m.storage != nil {
+		if err := m.storage.Store(ctx, m.keys); err != nil {
+			return fmt.Errorf(`failed to store keys: %w`, err)
+		}
+	}
+	m.dirty = false
+	return nil
+}
+
+// SigningKey returns the key that should currently be used for signing,
+// which can be passed to `jws.WithKey()` or `jwt.WithKey()` along with
+// its `alg` field.
+func (m *RotationManager) SigningKey() (Key, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	active := m.find(KeyStateActive)
+	if active == nil {
+		return nil, fmt.Errorf(`no active key (has Tick() been called?)`)
+	}
+	return active.Key, nil
+}
+
+// PublicSet returns a `jwk.Set` containing the public keys of the
+// pending, active, and retiring keys. Symmetric keys are never
+// included. Its signature matches
+// `jwk.SetProviderFunc`, so that it can be passed to `jwk.NewHandlerFunc()`.
+func (m *RotationManager) PublicSet(_ context.Context) (Set, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set := NewSet()
+	for _, mk := range m.keys {
+		if mk.State == KeyStateRetired || mk.Key.KeyType() == jwa.OctetSeq {
+			continue
+		}
+		pubkey, err := PublicKeyOf(mk.Key)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to get public key of %q: %w`, mk.Key.KeyID(), err)
+		}
+		if err := set.AddKey(pubkey); err != nil {
+			return nil, fmt.Errorf(`failed to add key: %w`, err)
+		}
+	}
+	return set, nil
+}
+
+// Keys returns copies of all keys managed by the `jwk.RotationManager`,
+// including retired ones that have not been discarded yet. The `jwk.Key` objects themselves are shared,
+// and should be treated as read-only.
+func (m *RotationManager) Keys() []ManagedKey {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]ManagedKey, len(m.keys))
+	for i, mk := range m.keys {
+		keys[i] = *mk
+	}
+	return keys
+}
@@ -0,0 +1,191 @@
+package jwk_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotationManager(t *testing.T) {
+	ctx := context.Background()
+
+	generator := jwk.KeyGeneratorFunc(func() (jwk.Key, error) {
+		return jwk.Generate(jwa.EC, jwk.WithAlgorithm(jwa.ES256))
+	})
+
+	states := func(t *testing.T, m *jwk.RotationManager) []jwk.KeyState {
+		t.Helper()
+		var list []jwk.KeyState
+		for _, mk := range m.Keys() {
+			list = append(list, mk.State)
+		}
+		return list
+	}
+	publicKIDs := func(t *testing.T, m *jwk.RotationManager) []string {
+		t.Helper()
+		set, err := m.PublicSet(ctx)
+		require.NoError(t, err, `m.PublicSet should succeed`)
+		var list []string
+		for i := 0; i < set.Len(); i++ {
+			key, _ := set.Key(i)
+			_, ok := key.Get(`d`)
+			require.False(t, ok, `public set should not contain private keys`)
+			list = append(list, key.KeyID())
+		}
+		return list
+	}
+	signingKID := func(t *testing.T, m *jwk.RotationManager) string {
+		t.Helper()
+		key, err := m.SigningKey()
+		require.NoError(t, err, `m.SigningKey should succeed`)
+		return key.KeyID()
+	}
+
+	t.Run("Lifecycle", func(t *testing.T) {
+		storage := jwk.NewFileRotationStorage(filepath.Join(t.TempDir(), `keys.json`))
+		m, err := jwk.NewRotationManager(ctx,
+			jwk.WithKeyGenerator(generator),
+			jwk.WithRotationInterval(10*time.Hour),
+			jwk.WithPrePublishPeriod(2*time.Hour),
+			jwk.WithRetirementPeriod(3*time.Hour),
+			jwk.WithRotationStorage(storage),
+		)
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+
+		_, err = m.SigningKey()
+		require.Error(t, err, `m.SigningKey should fail before the first tick`)
+
+		t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		// the first key is activated immediately
+		require.NoError(t, m.Tick(ctx, t0), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateActive}, states(t, m))
+		kid1 := signingKID(t, m)
+		require.NotEmpty(t, kid1, `key ID should be assigned`)
+		require.Equal(t, []string{kid1}, publicKIDs(t, m))
+
+		require.NoError(t, m.Tick(ctx, t0.Add(7*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateActive}, states(t, m))
+
+		// the next key is published ahead of its use
+		require.NoError(t, m.Tick(ctx, t0.Add(8*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateActive, jwk.KeyStatePending}, states(t, m))
+		kid2 := m.Keys()[1].Key.KeyID()
+		require.Equal(t, kid1, signingKID(t, m))
+		require.Equal(t, []string{kid1, kid2}, publicKIDs(t, m))
+
+		// ... and replaces the signing key after the pre-publish period
+		require.NoError(t, m.Tick(ctx, t0.Add(10*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetiring, jwk.KeyStateActive}, states(t, m))
+		require.Equal(t, kid2, signingKID(t, m))
+		require.Equal(t, []string{kid1, kid2}, publicKIDs(t, m))
+
+		// the old key is removed after the retirement period
+		require.NoError(t, m.Tick(ctx, t0.Add(13*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetired, jwk.KeyStateActive}, states(t, m))
+		require.Equal(t, []string{kid2}, publicKIDs(t, m))
+		_, ok := m.Keys()[0].Key.Get(`d`)
+		require.False(t, ok, `private key material should be discarded from retired keys`)
+
+		// manual rotation
+		require.NoError(t, m.Rotate(ctx, t0.Add(14*time.Hour)), `m.Rotate should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetired, jwk.KeyStateActive, jwk.KeyStatePending}, states(t, m))
+		kid3 := m.Keys()[2].Key.KeyID()
+		require.Equal(t, kid2, signingKID(t, m))
+		require.NoError(t, m.Tick(ctx, t0.Add(16*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, kid3, signingKID(t, m))
+
+		// the keys survive restarts
+		restored, err := jwk.NewRotationManager(ctx,
+			jwk.WithKeyGenerator(generator),
+			jwk.WithRotationStorage(storage),
+		)
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+		require.Equal(t, states(t, m), states(t, restored))
+		require.Equal(t, publicKIDs(t, m), publicKIDs(t, restored))
+		require.Equal(t, kid3, signingKID(t, restored))
+		for i, mk := range restored.Keys() {
+			require.True(t, mk.ActivateAt.Equal(m.Keys()[i].ActivateAt), `ActivateAt should be restored`)
+			require.True(t, mk.RetireAt.Equal(m.Keys()[i].RetireAt), `RetireAt should be restored`)
+		}
+	})
+	t.Run("Pre-publish after downtime", func(t *testing.T) {
+		m, err := jwk.NewRotationManager(ctx,
+			jwk.WithKeyGenerator(generator),
+			jwk.WithRotationInterval(10*time.Hour),
+			jwk.WithPrePublishPeriod(2*time.Hour),
+		)
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+
+		t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, m.Tick(ctx, t0), `m.Tick should succeed`)
+		kid1 := signingKID(t, m)
+
+		// even if the rotation is overdue, the new key must be published first
+		require.NoError(t, m.Tick(ctx, t0.Add(100*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, kid1, signingKID(t, m))
+		require.NoError(t, m.Tick(ctx, t0.Add(102*time.Hour)), `m.Tick should succeed`)
+		require.NotEqual(t, kid1, signingKID(t, m))
+	})
+	t.Run("Retention", func(t *testing.T) {
+		m, err := jwk.NewRotationManager(ctx,
+			jwk.WithKeyGenerator(generator),
+			jwk.WithPrePublishPeriod(0),
+			jwk.WithRetirementPeriod(time.Hour),
+			jwk.WithRetiredKeyRetention(5*time.Hour),
+		)
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+
+		t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, m.Tick(ctx, t0), `m.Tick should succeed`)
+		require.NoError(t, m.Rotate(ctx, t0), `m.Rotate should succeed`)
+		require.NoError(t, m.Tick(ctx, t0.Add(time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetired, jwk.KeyStateActive}, states(t, m))
+
+		require.NoError(t, m.Tick(ctx, t0.Add(6*time.Hour-time.Second)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetired, jwk.KeyStateActive}, states(t, m))
+
+		// retired keys are discarded after the retention period
+		require.NoError(t, m.Tick(ctx, t0.Add(6*time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateActive}, states(t, m))
+	})
+	t.Run("Symmetric keys", func(t *testing.T) {
+		storage := jwk.NewFileRotationStorage(filepath.Join(t.TempDir(), `keys.json`))
+		m, err := jwk.NewRotationManager(ctx,
+			jwk.WithKeyGenerator(func() (jwk.Key, error) {
+				return jwk.Generate(jwa.OctetSeq, jwk.WithAlgorithm(jwa.HS256))
+			}),
+			jwk.WithPrePublishPeriod(0),
+			jwk.WithRetirementPeriod(time.Hour),
+			jwk.WithRotationStorage(storage),
+		)
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+
+		t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, m.Tick(ctx, t0), `m.Tick should succeed`)
+		require.Empty(t, publicKIDs(t, m), `symmetric keys should not be published`)
+
+		// the secret of retired keys must not be kept
+		require.NoError(t, m.Rotate(ctx, t0), `m.Rotate should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateRetiring, jwk.KeyStateActive}, states(t, m))
+		require.NoError(t, m.Tick(ctx, t0.Add(time.Hour)), `m.Tick should succeed`)
+		require.Equal(t, []jwk.KeyState{jwk.KeyStateActive}, states(t, m))
+
+		stored, err := storage.Load(ctx)
+		require.NoError(t, err, `storage.Load should succeed`)
+		require.Len(t, stored, 1, `retired symmetric keys should not be stored`)
+	})
+	t.Run("Missing alg", func(t *testing.T) {
+		m, err := jwk.NewRotationManager(ctx, jwk.WithKeyGenerator(func() (jwk.Key, error) {
+			return jwxtest.GenerateEcdsaJwk()
+		}))
+		require.NoError(t, err, `jwk.NewRotationManager should succeed`)
+		require.Error(t, m.Tick(ctx, time.Now()), `m.Tick should fail`)
+	})
+}
@@ -0,0 +1,366 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/lestrrat-go/jwx/v2/internal/ecutil"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
+)
+
+const (
+	defaultMinRSAKeySize       = 2048
+	defaultMinSymmetricKeySize = 128
+)
+
+type keyValidationError struct {
+	error
+}
+
+func (err *keyValidationError) Unwrap() error {
+	return err.error
+}
+
+var errInvalidKey = &keyValidationError{fmt.Errorf(`invalid key`)}
+var errKeyTooSmall = &keyValidationError{fmt.Errorf(`key is too small`)}
+var errInvalidRSAExponent = &keyValidationError{fmt.Errorf(`invalid RSA public exponent`)}
+var errInvalidCurvePoint = &keyValidationError{fmt.Errorf(`point is not on the curve`)}
+var errInconsistentPrivateKey = &keyValidationError{fmt.Errorf(`private key is inconsistent with the public key`)}
+
+// ErrInvalidKey returns the immutable error used when a key fails
+// validation for reasons not covered by the other errors, such as
+// missing or malformed parameters.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrInvalidKey() error {
+	return errInvalidKey
+}
+
+// ErrKeyTooSmall returns the immutable error used when an RSA modulus
+// or a symmetric key is smaller than the allowed minimum.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrKeyTooSmall() error {
+	return errKeyTooSmall
+}
+
+// ErrInvalidRSAExponent returns the immutable error used when the
+// public exponent of an RSA key is not an odd number greater than 1.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrInvalidRSAExponent() error {
+	return errInvalidRSAExponent
+}
+
+// ErrInvalidCurvePoint returns the immutable error used when the public
+// point of an EC key does not lie on the curve, or the public key of an
+// OKP key has the wrong length.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrInvalidCurvePoint() error {
+	return errInvalidCurvePoint
+}
+
+// ErrInconsistentPrivateKey returns the immutable error used when the
+// private parameters of a key do not correspond to its public parameters.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrInconsistentPrivateKey() error {
+	return errInconsistentPrivateKey
+}
+
+// IsKeyValidationError returns true if the error is a key validation
+// error returned by `jwk.Validate()`
+func IsKeyValidationError(err error) bool {
+	var kverr *keyValidationError
+	return errors.As(err, &kverr)
+}
+
+func newKeyValidationError(kind *keyValidationError, f string, args ...interface{}) error {
+	return fmt.Errorf(`%w: `+f, append([]interface{}{kind}, args...)...)
+}
+
+type validateCtx struct {
+	minRSAKeySize       int
+	minSymmetricKeySize int
+}
+
+// Validate performs structural checks on the key:
+//
+//   - RSA keys: the modulus must be at least `jwk.WithMinRSAKeySize()` bits,
+//     and the public exponent must be an odd number greater than 1. For
+//     private keys, the primes, the private exponent, and the CRT parameters
+//     (if present) must be consistent with the public key.
+//   - EC keys: the curve must be supported, and the public point must lie
+//     on the curve. For private keys, the private scalar must correspond to
+//     the public point.
+//   - OKP keys: the public key must have the correct length for the curve.
+//     For private keys, the public key derived from the private key must
+//     match the public key.
+//   - Symmetric keys: the key must be at least `jwk.WithMinSymmetricKeySize()`
+//     bits, and large enough for the algorithm specified in its "alg" field.
+//
+// The errors returned by this function can be compared against the
+// errors returned by functions such as `jwk.ErrKeyTooSmall()` using
+// `errors.Is()`.
+//
+// Keys can also be validated when they are parsed, by passing
+// `jwk.WithValidate(true)` to `jwk.Parse()`, `jwk.ParseKey()`, `jwk.Fetch()`, etc.
+func Validate(key Key, options ...ValidateOption) error {
+	vctx := validateCtx{
+		minRSAKeySize:       defaultMinRSAKeySize,
+		minSymmetricKeySize: defaultMinSymmetricKeySize,
+	}
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identMinRSAKeySize{}:
+			vctx.minRSAKeySize = option.Value().(int)
+		case identMinSymmetricKeySize{}:
+			vctx.minSymmetricKeySize = option.Value().(int)
+		}
+	}
+	return vctx.validate(key)
+}
+
+func (vctx *validateCtx) validate(key Key) error {
+	switch key := key.(type) {
+	case RSAPrivateKey:
+		return vctx.validateRSAPrivateKey(key)
+	case RSAPublicKey:
+		return vctx.validateRSAPublicKey(key.N(), key.E())
+	case ECDSAPrivateKey:
+		return validateECDSAKey(key.Crv(), key.X(), key.Y(), key.D())
+	case ECDSAPublicKey:
+		return validateECDSAKey(key.Crv(), key.X(), key.Y(), nil)
+	case OKPPrivateKey:
+		return validateOKPKey(key.Crv(), key.X(), key.D())
+	case OKPPublicKey:
+		return validateOKPKey(key.Crv(), key.X(), nil)
+	case SymmetricKey:
+		return vctx.validateSymmetricKey(key)
+	default:
+		return newKeyValidationError(errInvalidKey, `unsupported key type %T`, key)
+	}
+}
+
+func (vctx *validateCtx) validateRSAPublicKey(nbuf, ebuf []byte) error {
+	if len(nbuf) == 0 || len(ebuf) == 0 {
+		return newKeyValidationError(errInvalidKey, `missing required RSA parameters "n" and/or "e"`)
+	}
+
+	n := new(big.Int).SetBytes(nbuf)
+	if bits := n.BitLen(); bits < vctx.minRSAKeySize {
+		return newKeyValidationError(errKeyTooSmall, `RSA modulus is %d bits, expected at least %d bits`, bits, vctx.minRSAKeySize)
+	}
+
+	e := new(big.Int).SetBytes(ebuf)
+	if e.Cmp(big.NewInt(1)) <= 0 || e.Bit(0) == 0 || e.BitLen() > 31 {
+		return newKeyValidationError(errInvalidRSAExponent, `RSA public exponent %s must be an odd number between 3 and 2^31-1`, e)
+	}
+	return nil
+}
+
+func (vctx *validateCtx) validateRSAPrivateKey(key RSAPrivateKey) error {
+	if err := vctx.validateRSAPublicKey(key.N(), key.E()); err != nil {
+		return err
+	}
+
+	if len(key.D()) == 0 {
+		return newKeyValidationError(errInvalidKey, `missing required RSA parameter "d"`)
+	}
+
+	n := new(big.Int).SetBytes(key.N())
+	e := new(big.Int).SetBytes(key.E())
+	d := new(big.Int).SetBytes(key.D())
+	one := big.NewInt(1)
+
+	if len(key.P()) == 0 || len(key.Q()) == 0 {
+		// Without the primes we can still check that d inverts e
+		// for an arbitrary message
+		m := big.NewInt(2)
+		c := new(big.Int).Exp(m, e, n)
+		if new(big.Int).Exp(c, d, n).Cmp(m) != 0 {
+			return newKeyValidationError(errInconsistentPrivateKey, `RSA private exponent does not correspond to the public key`)
+		}
+		return nil
+	}
+
+	p := new(big.Int).SetBytes(key.P())
+	q := new(big.Int).SetBytes(key.Q())
+	if p.Cmp(one) <= 0 || q.Cmp(one) <= 0 || new(big.Int).Mul(p, q).Cmp(n) != 0 {
+		return newKeyValidationError(errInconsistentPrivateKey, `RSA primes do not correspond to the modulus`)
+	}
+
+	pminus1 := new(big.Int).Sub(p, one)
+	qminus1 := new(big.Int).Sub(q, one)
+
+	// d * e must be congruent to 1 modulo p-1 and q-1
+	de := new(big.Int).Mul(d, e)
+	for _, v := range []*big.Int{pminus1, qminus1} {
+		if new(big.Int).Mod(de, v).Cmp(one) != 0 {
+			return newKeyValidationError(errInconsistentPrivateKey, `RSA private exponent does not correspond to the public exponent`)
+		}
+	}
+
+	if v := key.DP(); len(v) > 0 {
+		if new(big.Int).SetBytes(v).Cmp(new(big.Int).Mod(d, pminus1)) != 0 {
+			return newKeyValidationError(errInconsistentPrivateKey, `RSA CRT parameter "dp" is inconsistent with "d"`)
+		}
+	}
+	if v := key.DQ(); len(v) > 0 {
+		if new(big.Int).SetBytes(v).Cmp(new(big.Int).Mod(d, qminus1)) != 0 {
+			return newKeyValidationError(errInconsistentPrivateKey, `RSA CRT parameter "dq" is inconsistent with "d"`)
+		}
+	}
+	if v := key.QI(); len(v) > 0 {
+		qi := new(big.Int).SetBytes(v)
+		if new(big.Int).Mod(new(big.Int).Mul(qi, q), p).Cmp(one) != 0 {
+			return newKeyValidationError(errInconsistentPrivateKey, `RSA CRT parameter "qi" is inconsistent with "p" and "q"`)
+		}
+	}
+	return nil
+}
+
+func validateECDSAKey(alg jwa.EllipticCurveAlgorithm, xbuf, ybuf, dbuf []byte) error {
+	crv, ok := ecutil.CurveForAlgorithm(alg)
+	if !ok {
+		return newKeyValidationError(errInvalidKey, `unsupported curve %q`, alg)
+	}
+
+	if len(xbuf) == 0 || len(ybuf) == 0 {
+		return newKeyValidationError(errInvalidKey, `missing required EC parameters "x" and/or "y"`)
+	}
+
+	params := crv.Params()
+	size := (params.BitSize + 7) / 8
+	if len(xbuf) != size || len(ybuf) != size {
+		return newKeyValidationError(errInvalidCurvePoint, `EC coordinates must be %d bytes long for curve %q`, size, alg)
+	}
+
+	x := new(big.Int).SetBytes(xbuf)
+	y := new(big.Int).SetBytes(ybuf)
+	if x.Cmp(params.P) >= 0 || y.Cmp(params.P) >= 0 || !crv.IsOnCurve(x, y) {
+		return newKeyValidationError(errInvalidCurvePoint, `EC point is not on curve %q`, alg)
+	}
+
+	if dbuf == nil {
+		return nil
+	}
+
+	d := new(big.Int).SetBytes(dbuf)
+	if d.Sign() <= 0 || d.Cmp(params.N) >= 0 {
+		return newKeyValidationError(errInconsistentPrivateKey, `EC private scalar is out of range for curve %q`, alg)
+	}
+
+	if px, py := crv.ScalarBaseMult(d.Bytes()); px.Cmp(x) != 0 || py.Cmp(y) != 0 {
+		return newKeyValidationError(errInconsistentPrivateKey, `EC private scalar does not correspond to the public point`)
+	}
+	return nil
+}
+
+func validateOKPKey(alg jwa.EllipticCurveAlgorithm, x, d []byte) error {
+	var pubSize, privSize int
+	var derive func([]byte) []byte
+	switch alg {
+	case jwa.Ed25519:
+		pubSize, privSize = ed25519.PublicKeySize, ed25519.SeedSize
+		derive = func(seed []byte) []byte {
+			//nolint:forcetypeassert
+			return ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey)
+		}
+	case jwa.X25519:
+		pubSize, privSize = x25519.PublicKeySize, x25519.SeedSize
+		derive = func(seed []byte) []byte {
+			priv, err := x25519.NewKeyFromSeed(seed)
+			if err != nil {
+				return nil
+			}
+			//nolint:forcetypeassert
+			return priv.Public().(x25519.PublicKey)
+		}
+	case jwa.Ed448:
+		pubSize, privSize = ed448.PublicKeySize, ed448.SeedSize
+		derive = func(seed []byte) []byte {
+			//nolint:forcetypeassert
+			return ed448.NewKeyFromSeed(seed).Public().(ed448.PublicKey)
+		}
+	case jwa.X448:
+		pubSize, privSize = x448.PublicKeySize, x448.SeedSize
+		derive = func(seed []byte) []byte {
+			priv, err := x448.NewKeyFromSeed(seed)
+			if err != nil {
+				return nil
+			}
+			//nolint:forcetypeassert
+			return priv.Public().(x448.PublicKey)
+		}
+	default:
+		return newKeyValidationError(errInvalidKey, `unsupported curve %q`, alg)
+	}
+
+	if len(x) != pubSize {
+		return newKeyValidationError(errInvalidCurvePoint, `OKP public key must be %d bytes long for curve %q`, pubSize, alg)
+	}
+
+	if d == nil {
+		return nil
+	}
+
+	if len(d) != privSize {
+		return newKeyValidationError(errInconsistentPrivateKey, `OKP private key must be %d bytes long for curve %q`, privSize, alg)
+	}
+	if !bytes.Equal(derive(d), x) {
+		return newKeyValidationError(errInconsistentPrivateKey, `OKP private key does not correspond to the public key`)
+	}
+	return nil
+}
+
+// symmetricKeySizes lists the minimum key sizes in bits for the
+// algorithms that use symmetric keys
+var symmetricKeySizes = map[string]int{
+	jwa.HS256.String():         256,
+	jwa.HS384.String():         384,
+	jwa.HS512.String():         512,
+	jwa.A128KW.String():        128,
+	jwa.A192KW.String():        192,
+	jwa.A256KW.String():        256,
+	jwa.A128GCMKW.String():     128,
+	jwa.A192GCMKW.String():     192,
+	jwa.A256GCMKW.String():     256,
+	jwa.A128GCM.String():       128,
+	jwa.A192GCM.String():       192,
+	jwa.A256GCM.String():       256,
+	jwa.A128CBC_HS256.String(): 256,
+	jwa.A192CBC_HS384.String(): 384,
+	jwa.A256CBC_HS512.String(): 512,
+	jwa.C20P.String():          256,
+	jwa.XC20P.String():         256,
+}
+
+func (vctx *validateCtx) validateSymmetricKey(key SymmetricKey) error {
+	octets := key.Octets()
+	if len(octets) == 0 {
+		return newKeyValidationError(errInvalidKey, `missing required symmetric key parameter "k"`)
+	}
+
+	bits := len(octets) * 8
+	if bits < vctx.minSymmetricKeySize {
+		return newKeyValidationError(errKeyTooSmall, `symmetric key is %d bits, expected at least %d bits`, bits, vctx.minSymmetricKeySize)
+	}
+
+	alg := key.Algorithm().String()
+	if alg == "" {
+		return nil
+	}
+	if required, ok := symmetricKeySizes[alg]; ok && bits < required {
+		return newKeyValidationError(errKeyTooSmall, `symmetric key is %d bits, expected at least %d bits for %s`, bits, required, alg)
+	}
+	return nil
+}
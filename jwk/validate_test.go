@@ -0,0 +1,203 @@
+package jwk_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	mustKey := func(t *testing.T, f func() (jwk.Key, error)) jwk.Key {
+		t.Helper()
+		key, err := f()
+		require.NoError(t, err, `key generation should succeed`)
+		return key
+	}
+	mustClone := func(t *testing.T, key jwk.Key) jwk.Key {
+		t.Helper()
+		buf, err := json.Marshal(key)
+		require.NoError(t, err, `json.Marshal should succeed`)
+		cloned, err := jwk.ParseKey(buf)
+		require.NoError(t, err, `jwk.ParseKey should succeed`)
+		return cloned
+	}
+	mustSet := func(t *testing.T, key jwk.Key, name string, value interface{}) jwk.Key {
+		t.Helper()
+		key = mustClone(t, key)
+		require.NoError(t, key.Set(name, value), `key.Set should succeed`)
+		return key
+	}
+
+	rsaKey := mustKey(t, jwxtest.GenerateRsaJwk)
+	ecKey := mustKey(t, jwxtest.GenerateEcdsaJwk)
+	otherECKey := mustKey(t, jwxtest.GenerateEcdsaJwk)
+	ed25519Key := mustKey(t, jwxtest.GenerateEd25519Jwk)
+	otherEd25519Key := mustKey(t, jwxtest.GenerateEd25519Jwk)
+	x25519Key := mustKey(t, jwxtest.GenerateX25519Jwk)
+	ed448Key := mustKey(t, jwxtest.GenerateEd448Jwk)
+	x448Key := mustKey(t, jwxtest.GenerateX448Jwk)
+
+	smallRSA, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err, `rsa.GenerateKey should succeed`)
+	smallRSAKey, err := jwk.FromRaw(smallRSA)
+	require.NoError(t, err, `jwk.FromRaw should succeed`)
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err, `ecdsa.GenerateKey should succeed`)
+	p384Key, err := jwk.FromRaw(p384)
+	require.NoError(t, err, `jwk.FromRaw should succeed`)
+
+	symKey := func(size int, alg jwa.KeyAlgorithm) jwk.Key {
+		buf := make([]byte, size/8)
+		_, _ = rand.Read(buf)
+		key, err := jwk.FromRaw(buf)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		if alg != nil {
+			require.NoError(t, key.Set(jwk.AlgorithmKey, alg), `key.Set should succeed`)
+		}
+		return key
+	}
+
+	rsaPub := mustKey(t, func() (jwk.Key, error) { return jwk.PublicKeyOf(rsaKey) })
+	ecPub := mustKey(t, func() (jwk.Key, error) { return jwk.PublicKeyOf(ecKey) })
+	ed25519Pub := mustKey(t, func() (jwk.Key, error) { return jwk.PublicKeyOf(ed25519Key) })
+
+	testcases := []struct {
+		Name     string
+		Key      jwk.Key
+		Options  []jwk.ValidateOption
+		Expected error
+	}{
+		{Name: "RSA private key", Key: rsaKey},
+		{Name: "RSA public key", Key: rsaPub},
+		{Name: "EC private key", Key: ecKey},
+		{Name: "EC public key", Key: ecPub},
+		{Name: "EC P-384 private key", Key: p384Key},
+		{Name: "Ed25519 private key", Key: ed25519Key},
+		{Name: "Ed25519 public key", Key: ed25519Pub},
+		{Name: "X25519 private key", Key: x25519Key},
+		{Name: "Ed448 private key", Key: ed448Key},
+		{Name: "X448 private key", Key: x448Key},
+		{Name: "Symmetric key", Key: symKey(256, jwa.HS256)},
+		{
+			Name:     "RSA modulus too small",
+			Key:      smallRSAKey,
+			Expected: jwk.ErrKeyTooSmall(),
+		},
+		{
+			Name:    "RSA modulus with custom minimum",
+			Key:     smallRSAKey,
+			Options: []jwk.ValidateOption{jwk.WithMinRSAKeySize(1024)},
+		},
+		{
+			Name:     "RSA even exponent",
+			Key:      mustSet(t, rsaPub, jwk.RSAEKey, []byte{0x01, 0x00, 0x00}),
+			Expected: jwk.ErrInvalidRSAExponent(),
+		},
+		{
+			Name:     "RSA exponent of 1",
+			Key:      mustSet(t, rsaPub, jwk.RSAEKey, []byte{0x01}),
+			Expected: jwk.ErrInvalidRSAExponent(),
+		},
+		{
+			Name:     "RSA inconsistent dp",
+			Key:      mustSet(t, rsaKey, jwk.RSADPKey, []byte{0x01, 0x02, 0x03}),
+			Expected: jwk.ErrInconsistentPrivateKey(),
+		},
+		{
+			Name:     "RSA inconsistent d",
+			Key:      mustSet(t, rsaKey, jwk.RSADKey, []byte{0x01, 0x02, 0x03}),
+			Expected: jwk.ErrInconsistentPrivateKey(),
+		},
+		{
+			Name:     "EC point not on curve",
+			Key:      mustSet(t, ecPub, jwk.ECDSAYKey, ecPub.(jwk.ECDSAPublicKey).X()),
+			Expected: jwk.ErrInvalidCurvePoint(),
+		},
+		{
+			Name:     "EC inconsistent d",
+			Key:      mustSet(t, ecKey, jwk.ECDSADKey, otherECKey.(jwk.ECDSAPrivateKey).D()),
+			Expected: jwk.ErrInconsistentPrivateKey(),
+		},
+		{
+			Name:     "OKP public key with wrong length",
+			Key:      mustSet(t, ed25519Pub, jwk.OKPXKey, []byte{0x01, 0x02, 0x03}),
+			Expected: jwk.ErrInvalidCurvePoint(),
+		},
+		{
+			Name:     "OKP inconsistent x",
+			Key:      mustSet(t, ed25519Key, jwk.OKPXKey, otherEd25519Key.(jwk.OKPPrivateKey).X()),
+			Expected: jwk.ErrInconsistentPrivateKey(),
+		},
+		{
+			Name:     "Symmetric key too small for alg",
+			Key:      symKey(128, jwa.HS256),
+			Expected: jwk.ErrKeyTooSmall(),
+		},
+		{
+			Name:     "Symmetric key too small",
+			Key:      symKey(64, nil),
+			Expected: jwk.ErrKeyTooSmall(),
+		},
+		{
+			Name:    "Symmetric key with custom minimum",
+			Key:     symKey(64, nil),
+			Options: []jwk.ValidateOption{jwk.WithMinSymmetricKeySize(64)},
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			err := jwk.Validate(tc.Key, tc.Options...)
+			if tc.Expected == nil {
+				require.NoError(t, err, `jwk.Validate should succeed`)
+				return
+			}
+			require.Error(t, err, `jwk.Validate should fail`)
+			require.True(t, errors.Is(err, tc.Expected), `error should be %q (got %q)`, tc.Expected, err)
+			require.True(t, jwk.IsKeyValidationError(err), `jwk.IsKeyValidationError should be true`)
+		})
+	}
+
+	t.Run("Parse", func(t *testing.T) {
+		set := jwk.NewSet()
+		require.NoError(t, set.AddKey(rsaPub), `set.AddKey should succeed`)
+		require.NoError(t, set.AddKey(smallRSAKey), `set.AddKey should succeed`)
+		buf, err := json.Marshal(set)
+		require.NoError(t, err, `json.Marshal should succeed`)
+
+		_, err = jwk.Parse(buf)
+		require.NoError(t, err, `jwk.Parse should succeed without validation`)
+
+		_, err = jwk.Parse(buf, jwk.WithValidate(true))
+		require.True(t, errors.Is(err, jwk.ErrKeyTooSmall()), `jwk.Parse should fail`)
+
+		parsed, err := jwk.Parse(buf, jwk.WithValidate(true), jwk.WithMinRSAKeySize(1024))
+		require.NoError(t, err, `jwk.Parse should succeed`)
+		require.Equal(t, 2, parsed.Len())
+
+		parsed, err = jwk.Parse(buf, jwk.WithValidate(true), jwk.WithIgnoreParseError(true))
+		require.NoError(t, err, `jwk.Parse should succeed`)
+		require.Equal(t, 1, parsed.Len(), `invalid keys should be dropped`)
+	})
+	t.Run("ParseKey", func(t *testing.T) {
+		buf, err := json.Marshal(smallRSAKey)
+		require.NoError(t, err, `json.Marshal should succeed`)
+
+		_, err = jwk.ParseKey(buf, jwk.WithValidate(true))
+		require.True(t, errors.Is(err, jwk.ErrKeyTooSmall()), `jwk.ParseKey should fail`)
+
+		_, err = jwk.ParseKey(buf, jwk.WithValidate(true), jwk.WithMinRSAKeySize(1024))
+		require.NoError(t, err, `jwk.ParseKey should succeed`)
+	})
+}
@@ -0,0 +1,124 @@
+package jwk
+
+import (
+	"crypto"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/cert"
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+)
+
+// VerifyX509Chain verifies the certificate chain stored in the "x5c"
+// field of `key`, and returns the verified chains as computed by
+// `(*x509.Certificate).Verify()`.
+//
+// The first certificate in "x5c" is treated as the leaf certificate,
+// and the rest of the certificates are used as intermediates. In addition
+// to verifying the chain against the trusted roots specified by
+// `jwk.WithX509Roots()`, the following are checked:
+//
+//   - The public key of the leaf certificate matches the key material of `key`
+//   - If present, the "x5t" and "x5t#S256" fields match the leaf certificate
+//
+// Note that by default, certificates with any extended key usage are
+// accepted. Use `jwk.WithX509KeyUsages()` to restrict this.
+func VerifyX509Chain(key Key, options ...VerifyX509Option) ([][]*x509.Certificate, error) {
+	var roots *x509.CertPool
+	var intermediates []*x509.Certificate
+	var now time.Time
+	keyUsages := []x509.ExtKeyUsage{x509.ExtKeyUsageAny}
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identX509Roots{}:
+			roots = option.Value().(*x509.CertPool)
+		case identX509Intermediates{}:
+			intermediates = option.Value().([]*x509.Certificate)
+		case identX509CurrentTime{}:
+			now = option.Value().(time.Time)
+		case identX509KeyUsages{}:
+			keyUsages = option.Value().([]x509.ExtKeyUsage)
+		}
+	}
+
+	chain := key.X509CertChain()
+	if chain == nil || chain.Len() == 0 {
+		return nil, fmt.Errorf(`key does not contain a certificate chain ("x5c")`)
+	}
+
+	pool := x509.NewCertPool()
+	for _, c := range intermediates {
+		pool.AddCert(c)
+	}
+
+	var leaf *x509.Certificate
+	for i := 0; i < chain.Len(); i++ {
+		src, _ := chain.Get(i)
+		c, err := cert.Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to parse certificate #%d in "x5c": %w`, i, err)
+		}
+		if i == 0 {
+			leaf = c
+			continue
+		}
+		pool.AddCert(c)
+	}
+
+	if err := verifyX509Thumbprints(key, leaf.Raw); err != nil {
+		return nil, err
+	}
+
+	pubkey, err := PublicRawKeyOf(key)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to obtain public key: %w`, err)
+	}
+	leafKey, ok := leaf.PublicKey.(interface {
+		Equal(crypto.PublicKey) bool
+	})
+	if !ok || !leafKey.Equal(pubkey) {
+		return nil, fmt.Errorf(`public key of the leaf certificate does not match the key`)
+	}
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		CurrentTime:   now,
+		KeyUsages:     keyUsages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf(`failed to verify certificate chain: %w`, err)
+	}
+	return chains, nil
+}
+
+func verifyX509Thumbprints(key Key, der []byte) error {
+	if v := key.X509CertThumbprint(); v != "" {
+		expected, err := base64.DecodeString(v)
+		if err != nil {
+			return fmt.Errorf(`failed to decode "x5t": %w`, err)
+		}
+		//nolint:gosec
+		computed := sha1.Sum(der)
+		if subtle.ConstantTimeCompare(expected, computed[:]) != 1 {
+			return fmt.Errorf(`"x5t" does not match the leaf certificate`)
+		}
+	}
+
+	if v := key.X509CertThumbprintS256(); v != "" {
+		expected, err := base64.DecodeString(v)
+		if err != nil {
+			return fmt.Errorf(`failed to decode "x5t#S256": %w`, err)
+		}
+		computed := sha256.Sum256(der)
+		if subtle.ConstantTimeCompare(expected, computed[:]) != 1 {
+			return fmt.Errorf(`"x5t#S256" does not match the leaf certificate`)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package jwk_test
+
+import (
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyX509Chain(t *testing.T) {
+	leafKey, chain, root, err := jwxtest.GenerateX509Chain(x509.ExtKeyUsageClientAuth)
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+	_, _, otherRoot, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot)
+
+	src, _ := chain.Get(0)
+	der, err := base64.Decode(src)
+	require.NoError(t, err, `base64.Decode should succeed`)
+
+	newKey := func(t *testing.T, raw interface{}, fields map[string]interface{}) jwk.Key {
+		t.Helper()
+		key, err := jwk.FromRaw(raw)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		require.NoError(t, key.Set(jwk.X509CertChainKey, chain), `key.Set should succeed`)
+		for name, value := range fields {
+			require.NoError(t, key.Set(name, value), `key.Set should succeed`)
+		}
+		return key
+	}
+
+	sha1sum := sha1.Sum(der) //nolint:gosec
+	sha256sum := sha256.Sum256(der)
+	otherKey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+
+	testcases := []struct {
+		Name    string
+		Key     jwk.Key
+		Options []jwk.VerifyX509Option
+		Error   bool
+	}{
+		{
+			Name:    "Valid chain",
+			Key:     newKey(t, &leafKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+		},
+		{
+			Name:    "Valid chain with private key",
+			Key:     newKey(t, leafKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+		},
+		{
+			Name: "Valid thumbprints",
+			Key: newKey(t, &leafKey.PublicKey, map[string]interface{}{
+				jwk.X509CertThumbprintKey:     base64.EncodeToString(sha1sum[:]),
+				jwk.X509CertThumbprintS256Key: base64.EncodeToString(sha256sum[:]),
+			}),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+		},
+		{
+			Name:    "Valid key usage",
+			Key:     newKey(t, &leafKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots), jwk.WithX509KeyUsages([]x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth})},
+		},
+		{
+			Name:    "Untrusted root",
+			Key:     newKey(t, &leafKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(otherRoots)},
+			Error:   true,
+		},
+		{
+			Name:    "Key mismatch",
+			Key:     newKey(t, &otherKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+			Error:   true,
+		},
+		{
+			Name: "x5t mismatch",
+			Key: newKey(t, &leafKey.PublicKey, map[string]interface{}{
+				jwk.X509CertThumbprintKey: base64.EncodeToString(make([]byte, sha1.Size)),
+			}),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+			Error:   true,
+		},
+		{
+			Name: "x5t#S256 mismatch",
+			Key: newKey(t, &leafKey.PublicKey, map[string]interface{}{
+				jwk.X509CertThumbprintS256Key: base64.EncodeToString(make([]byte, sha256.Size)),
+			}),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots)},
+			Error:   true,
+		},
+		{
+			Name:    "Expired",
+			Key:     newKey(t, &leafKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots), jwk.WithX509CurrentTime(time.Now().Add(2 * time.Hour))},
+			Error:   true,
+		},
+		{
+			Name:    "Invalid key usage",
+			Key:     newKey(t, &leafKey.PublicKey, nil),
+			Options: []jwk.VerifyX509Option{jwk.WithX509Roots(roots), jwk.WithX509KeyUsages([]x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning})},
+			Error:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			chains, err := jwk.VerifyX509Chain(tc.Key, tc.Options...)
+			if tc.Error {
+				require.Error(t, err, `jwk.VerifyX509Chain should fail`)
+				return
+			}
+			require.NoError(t, err, `jwk.VerifyX509Chain should succeed`)
+			require.Len(t, chains, 1, `there should be one verified chain`)
+			require.Len(t, chains[0], 3, `the chain should contain the leaf, intermediate, and root`)
+			require.True(t, chains[0][2].Equal(root), `the chain should end with the root`)
+		})
+	}
+
+	t.Run("No x5c", func(t *testing.T) {
+		key, err := jwk.FromRaw(&leafKey.PublicKey)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		_, err = jwk.VerifyX509Chain(key, jwk.WithX509Roots(roots))
+		require.Error(t, err, `jwk.VerifyX509Chain should fail`)
+	})
+}
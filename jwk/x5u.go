@@ -0,0 +1,140 @@
+package jwk
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"github.com/lestrrat-go/httprc"
+	"github.com/lestrrat-go/jwx/v2/cert"
+)
+
+// FetchX5U fetches a PEM encoded X.509 certificate chain, such as those
+// referred to by "x5u" fields, from the URL `u`. The URL must be pointing
+// to a resource that is supported by `net/http`.
+//
+// The result is a `jwk.Set` containing a single public key, which is
+// taken from the first certificate in the chain. The "x5c" field of the
+// key is populated with the certificates in the chain, and the "x5u" field
+// is set to `u`. The signature of this function matches that of `jwk.Fetch()`,
+// so it can be used as a `jwk.Fetcher` via `jwk.FetchFunc(jwk.FetchX5U)`.
+//
+// The chain is always verified using `jwk.VerifyX509Chain()`. The trusted
+// root certificates MUST be specified using `jwk.WithX509Roots()`: the
+// system root certificates are never used, as they would allow any
+// publicly trusted certificate to be used as a key. Other
+// `jwk.VerifyX509Option`s may be used to further restrict how the chain
+// is verified. `jwk.WithHTTPClient()` and `jwk.WithFetchWhitelist()` are
+// honored in the same way as `jwk.Fetch()`.
+//
+// If you would like to periodically refresh the chain, register the URL
+// with a `jwk.Cache` using `jwk.WithX5U(true)`.
+func FetchX5U(ctx context.Context, u string, options ...FetchOption) (Set, error) {
+	var hrfopts []httprc.FetchOption
+	var verifyOptions []VerifyX509Option
+	for _, option := range options {
+		if verifyOpt, ok := option.(VerifyX509Option); ok {
+			verifyOptions = append(verifyOptions, verifyOpt)
+			continue
+		}
+
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identHTTPClient{}:
+			hrfopts = append(hrfopts, httprc.WithHTTPClient(option.Value().(HTTPClient)))
+		case identFetchWhitelist{}:
+			hrfopts = append(hrfopts, httprc.WithWhitelist(option.Value().(httprc.Whitelist)))
+		}
+	}
+
+	if !hasX509Roots(verifyOptions) {
+		return nil, fmt.Errorf(`jwk.FetchX5U: trusted root certificates must be specified using jwk.WithX509Roots()`)
+	}
+
+	res, err := globalFetcher.Fetch(ctx, u, hrfopts...)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to fetch %q: %w`, u, err)
+	}
+
+	buf, err := io.ReadAll(res.Body)
+	defer res.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read response body for %q: %w`, u, err)
+	}
+
+	return parseX5U(u, buf, verifyOptions)
+}
+
+// parseX5U parses a PEM encoded certificate chain fetched from `u`,
+// and returns a Set containing the verified public key of the leaf
+// certificate
+func parseX5U(u string, src []byte, options []VerifyX509Option) (Set, error) {
+	if !hasX509Roots(options) {
+		return nil, fmt.Errorf(`trusted root certificates for %q have not been specified`, u)
+	}
+
+	var chain cert.Chain
+	for {
+		block, rest := pem.Decode(src)
+		if block == nil {
+			break
+		}
+		src = rest
+
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf(`invalid PEM block type %s in certificate chain at %q`, block.Type, u)
+		}
+		encoded, err := cert.EncodeBase64(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to encode certificate: %w`, err)
+		}
+		if err := chain.Add(encoded); err != nil {
+			return nil, fmt.Errorf(`failed to add certificate to chain: %w`, err)
+		}
+	}
+
+	if chain.Len() == 0 {
+		return nil, fmt.Errorf(`no certificates found at %q`, u)
+	}
+
+	der, _ := chain.Get(0)
+	leaf, err := cert.Parse(der)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to parse leaf certificate at %q: %w`, u, err)
+	}
+
+	key, err := FromRaw(leaf.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to create key from leaf certificate at %q: %w`, u, err)
+	}
+	if err := key.Set(X509CertChainKey, &chain); err != nil {
+		return nil, fmt.Errorf(`failed to set "x5c": %w`, err)
+	}
+	if err := key.Set(X509URLKey, u); err != nil {
+		return nil, fmt.Errorf(`failed to set "x5u": %w`, err)
+	}
+
+	if _, err := VerifyX509Chain(key, options...); err != nil {
+		return nil, fmt.Errorf(`failed to verify certificate chain at %q: %w`, u, err)
+	}
+
+	set := NewSet()
+	if err := set.AddKey(key); err != nil {
+		return nil, fmt.Errorf(`failed to add key: %w`, err)
+	}
+	return set, nil
+}
+
+// hasX509Roots returns true if `options` contain a non-nil set of
+// trusted root certificates specified via `jwk.WithX509Roots()`
+func hasX509Roots(options []VerifyX509Option) bool {
+	var roots *x509.CertPool
+	for _, option := range options {
+		if option.Ident() == (identX509Roots{}) {
+			roots = option.Value().(*x509.CertPool) //nolint:forcetypeassert
+		}
+	}
+	return roots != nil
+}
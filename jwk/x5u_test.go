@@ -0,0 +1,115 @@
+package jwk_test
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/cert"
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeChainPEM(t *testing.T, chain *cert.Chain) []byte {
+	t.Helper()
+	var buf []byte
+	for i := 0; i < chain.Len(); i++ {
+		src, _ := chain.Get(i)
+		der, err := base64.Decode(src)
+		require.NoError(t, err, `base64.Decode should succeed`)
+		buf = append(buf, pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: der})...)
+	}
+	return buf
+}
+
+func TestFetchX5U(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leafKey, chain, root, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+	_, _, otherRoot, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot)
+
+	chainPEM := encodeChainPEM(t, chain)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case `/chain.pem`:
+			_, _ = w.Write(chainPEM)
+		case `/key.pem`:
+			_, _ = w.Write(pem.EncodeToMemory(&pem.Block{Type: `PUBLIC KEY`, Bytes: []byte(`dummy`)}))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	u := srv.URL + `/chain.pem`
+	checkSet := func(t *testing.T, set jwk.Set) {
+		t.Helper()
+		require.Equal(t, 1, set.Len(), `set should contain the leaf key`)
+		key, _ := set.Key(0)
+		require.Equal(t, u, key.X509URL(), `"x5u" should be populated`)
+		require.Equal(t, chain.Len(), key.X509CertChain().Len(), `"x5c" should be populated`)
+
+		var raw interface{}
+		require.NoError(t, key.Raw(&raw), `key.Raw should succeed`)
+		require.True(t, leafKey.PublicKey.Equal(raw), `key should be the leaf public key`)
+	}
+
+	t.Run("Fetch", func(t *testing.T) {
+		set, err := jwk.FetchX5U(ctx, u, jwk.WithX509Roots(roots))
+		require.NoError(t, err, `jwk.FetchX5U should succeed`)
+		checkSet(t, set)
+	})
+	t.Run("Untrusted chain", func(t *testing.T) {
+		_, err := jwk.FetchX5U(ctx, u, jwk.WithX509Roots(otherRoots))
+		require.Error(t, err, `jwk.FetchX5U should fail`)
+	})
+	t.Run("No roots", func(t *testing.T) {
+		_, err := jwk.FetchX5U(ctx, u)
+		require.Error(t, err, `jwk.FetchX5U should fail`)
+		_, err = jwk.FetchX5U(ctx, u, jwk.WithX509Roots(nil))
+		require.Error(t, err, `jwk.FetchX5U should fail`)
+
+		c := jwk.NewCache(ctx)
+		require.Error(t, c.Register(u, jwk.WithX5U(true)), `c.Register should fail`)
+	})
+	t.Run("Not a certificate", func(t *testing.T) {
+		_, err := jwk.FetchX5U(ctx, srv.URL+`/key.pem`, jwk.WithX509Roots(roots))
+		require.Error(t, err, `jwk.FetchX5U should fail`)
+	})
+	t.Run("Whitelist", func(t *testing.T) {
+		_, err := jwk.FetchX5U(ctx, u, jwk.WithX509Roots(roots), jwk.WithFetchWhitelist(jwk.NewMapWhitelist()))
+		require.Error(t, err, `jwk.FetchX5U should fail`)
+	})
+	t.Run("Cache", func(t *testing.T) {
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(u, jwk.WithX5U(true), jwk.WithX509Roots(roots), jwk.WithRefreshInterval(time.Hour)), `c.Register should succeed`)
+
+		f := jwk.NewCachedFetcher(c)
+		set, err := f.Fetch(ctx, u)
+		require.NoError(t, err, `f.Fetch should succeed`)
+		checkSet(t, set)
+
+		_, err = f.Fetch(ctx, srv.URL+`/unregistered.pem`)
+		require.Error(t, err, `f.Fetch should fail for unregistered URLs`)
+	})
+	t.Run("Cache with untrusted chain", func(t *testing.T) {
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(u, jwk.WithX5U(true), jwk.WithX509Roots(otherRoots)), `c.Register should succeed`)
+		_, err := c.Refresh(ctx, u)
+		require.Error(t, err, `c.Refresh should fail`)
+	})
+}
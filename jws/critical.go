@@ -0,0 +1,80 @@
+package jws
+
+import (
+	"fmt"
+)
+
+// reservedHeaderNames lists the header parameter names that are defined
+// by RFC7515 and RFC7518. These names may not appear in the "crit"
+// header parameter (RFC7515 Section 4.1.11)
+var reservedHeaderNames = map[string]struct{}{
+	AlgorithmKey:              {},
+	ContentTypeKey:            {},
+	CriticalKey:               {},
+	JWKKey:                    {},
+	JWKSetURLKey:              {},
+	KeyIDKey:                  {},
+	TypeKey:                   {},
+	X509CertChainKey:          {},
+	X509CertThumbprintKey:     {},
+	X509CertThumbprintS256Key: {},
+	X509URLKey:                {},
+	// RFC7518
+	"enc": {},
+	"zip": {},
+	"epk": {},
+	"apu": {},
+	"apv": {},
+	"iv":  {},
+	"tag": {},
+	"p2s": {},
+	"p2c": {},
+}
+
+// defaultCriticalExtensions lists the extensions that this library
+// understands and processes on its own.
+var defaultCriticalExtensions = []string{
+	"b64", // RFC7797
+}
+
+// checkCritical verifies that the "crit" header parameter in the given
+// signature satisfies the requirements described in RFC7515 Section 4.1.11.
+//
+// `understood` should contain the names of all extensions that the
+// caller understands and processes.
+func checkCritical(sig *Signature, understood map[string]struct{}) error {
+	if public := sig.headers; public != nil {
+		if _, ok := public.Get(CriticalKey); ok {
+			return fmt.Errorf(`"crit" must be in the protected header`)
+		}
+	}
+
+	protected := sig.protected
+	if protected == nil {
+		return nil
+	}
+
+	if _, ok := protected.Get(CriticalKey); !ok {
+		return nil
+	}
+
+	list := protected.Critical()
+	if len(list) == 0 {
+		return fmt.Errorf(`"crit" must not be an empty list`)
+	}
+
+	for _, name := range list {
+		if _, ok := reservedHeaderNames[name]; ok {
+			return fmt.Errorf(`"crit" must not contain header parameter %q defined by the specifications`, name)
+		}
+
+		if _, ok := understood[name]; !ok {
+			return fmt.Errorf(`"crit" contains extension %q that is not understood`, name)
+		}
+
+		if _, ok := protected.Get(name); !ok {
+			return fmt.Errorf(`header parameter %q listed in "crit" is not present in the protected header`, name)
+		}
+	}
+	return nil
+}
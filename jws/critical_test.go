@@ -0,0 +1,127 @@
+package jws_test
+
+import (
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCritical(t *testing.T) {
+	key := []byte("abracadabra")
+	payload := []byte("Lorem ipsum")
+
+	makeHeaders := func(t *testing.T, crit []string, extra map[string]interface{}) jws.Headers {
+		t.Helper()
+		hdrs := jws.NewHeaders()
+		if crit != nil {
+			require.NoError(t, hdrs.Set(jws.CriticalKey, crit), `hdrs.Set should succeed`)
+		}
+		for k, v := range extra {
+			require.NoError(t, hdrs.Set(k, v), `hdrs.Set should succeed`)
+		}
+		return hdrs
+	}
+
+	testcases := []struct {
+		Name    string
+		Crit    []string
+		Extra   map[string]interface{}
+		Options []jws.VerifyOption
+		Error   bool
+	}{
+		{
+			Name: "no crit",
+		},
+		{
+			Name:  "understood extension",
+			Crit:  []string{"myext"},
+			Extra: map[string]interface{}{"myext": "foo"},
+			Options: []jws.VerifyOption{
+				jws.WithCriticalExtensions("myext"),
+			},
+		},
+		{
+			Name:  "multiple understood extensions",
+			Crit:  []string{"myext", "otherext"},
+			Extra: map[string]interface{}{"myext": "foo", "otherext": true},
+			Options: []jws.VerifyOption{
+				jws.WithCriticalExtensions("myext"),
+				jws.WithCriticalExtensions("otherext"),
+			},
+		},
+		{
+			Name:  "extension not understood",
+			Crit:  []string{"myext"},
+			Extra: map[string]interface{}{"myext": "foo"},
+			Error: true,
+		},
+		{
+			Name:  "partially understood extensions",
+			Crit:  []string{"myext", "otherext"},
+			Extra: map[string]interface{}{"myext": "foo", "otherext": true},
+			Options: []jws.VerifyOption{
+				jws.WithCriticalExtensions("myext"),
+			},
+			Error: true,
+		},
+		{
+			Name: "extension not present",
+			Crit: []string{"myext"},
+			Options: []jws.VerifyOption{
+				jws.WithCriticalExtensions("myext"),
+			},
+			Error: true,
+		},
+		{
+			Name: "reserved name",
+			Crit: []string{jws.KeyIDKey},
+			Options: []jws.VerifyOption{
+				jws.WithCriticalExtensions(jws.KeyIDKey),
+			},
+			Error: true,
+		},
+		{
+			Name:  "empty list",
+			Crit:  []string{},
+			Error: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			hdrs := makeHeaders(t, tc.Crit, tc.Extra)
+			signed, err := jws.Sign(payload, jws.WithKey(jwa.HS256, key, jws.WithProtectedHeaders(hdrs)))
+			require.NoError(t, err, `jws.Sign should succeed`)
+
+			options := append([]jws.VerifyOption{jws.WithKey(jwa.HS256, key)}, tc.Options...)
+			verified, err := jws.Verify(signed, options...)
+			if tc.Error {
+				require.Error(t, err, `jws.Verify should fail`)
+				return
+			}
+			require.NoError(t, err, `jws.Verify should succeed`)
+			require.Equal(t, payload, verified, `payload should match`)
+		})
+	}
+
+	t.Run("b64 is understood by default", func(t *testing.T) {
+		hdrs := makeHeaders(t, []string{"b64"}, map[string]interface{}{"b64": false})
+		signed, err := jws.Sign(nil, jws.WithKey(jwa.HS256, key, jws.WithProtectedHeaders(hdrs)), jws.WithDetachedPayload(payload))
+		require.NoError(t, err, `jws.Sign should succeed`)
+
+		_, err = jws.Verify(signed, jws.WithKey(jwa.HS256, key), jws.WithDetachedPayload(payload))
+		require.NoError(t, err, `jws.Verify should succeed`)
+	})
+	t.Run("crit in unprotected header", func(t *testing.T) {
+		public := makeHeaders(t, []string{"myext"}, nil)
+		protected := makeHeaders(t, nil, map[string]interface{}{"myext": "foo"})
+		signed, err := jws.Sign(payload, jws.WithJSON(), jws.WithKey(jwa.HS256, key, jws.WithProtectedHeaders(protected), jws.WithPublicHeaders(public)))
+		require.NoError(t, err, `jws.Sign should succeed`)
+
+		_, err = jws.Verify(signed, jws.WithKey(jwa.HS256, key), jws.WithCriticalExtensions("myext"))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+}
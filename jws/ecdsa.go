@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/asn1"
 	"fmt"
+	"hash"
 	"math/big"
 
 	"github.com/lestrrat-go/jwx/v2/internal/keyconv"
@@ -53,14 +54,24 @@ func (es ecdsaSigner) Algorithm() jwa.SignatureAlgorithm {
 }
 
 func (es *ecdsaSigner) Sign(payload []byte, key interface{}) ([]byte, error) {
-	if key == nil {
-		return nil, fmt.Errorf(`missing private key while signing payload`)
+	h, err := es.NewHash(key)
+	if err != nil {
+		return nil, err
 	}
-
-	h := es.hash.New()
 	if _, err := h.Write(payload); err != nil {
 		return nil, fmt.Errorf(`failed to write payload using ecdsa: %w`, err)
 	}
+	return es.SignHash(h, key)
+}
+
+func (es *ecdsaSigner) NewHash(_ interface{}) (hash.Hash, error) {
+	return es.hash.New(), nil
+}
+
+func (es *ecdsaSigner) SignHash(h hash.Hash, key interface{}) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf(`missing private key while signing payload`)
+	}
 
 	signer, ok := key.(crypto.Signer)
 	if ok {
@@ -151,6 +162,21 @@ func (v ecdsaVerifier) Algorithm() jwa.SignatureAlgorithm {
 }
 
 func (v *ecdsaVerifier) Verify(payload []byte, signature []byte, key interface{}) error {
+	h, err := v.NewHash(key)
+	if err != nil {
+		return err
+	}
+	if _, err := h.Write(payload); err != nil {
+		return fmt.Errorf(`failed to write payload using ecdsa: %w`, err)
+	}
+	return v.VerifyHash(h, signature, key)
+}
+
+func (v *ecdsaVerifier) NewHash(_ interface{}) (hash.Hash, error) {
+	return v.hash.New(), nil
+}
+
+func (v *ecdsaVerifier) VerifyHash(h hash.Hash, signature []byte, key interface{}) error {
 	if key == nil {
 		return fmt.Errorf(`missing public key while verifying payload`)
 	}
@@ -185,11 +211,6 @@ func (v *ecdsaVerifier) Verify(payload []byte, signature []byte, key interface{}
 	r.SetBytes(signature[:n])
 	s.SetBytes(signature[n:])
 
-	h := v.hash.New()
-	if _, err := h.Write(payload); err != nil {
-		return fmt.Errorf(`failed to write payload using ecdsa: %w`, err)
-	}
-
 	if !ecdsa.Verify(&pubkey, h.Sum(nil), r, s) {
 		return fmt.Errorf(`failed to verify signature using ecdsa`)
 	}
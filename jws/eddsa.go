@@ -6,8 +6,10 @@ import (
 	"crypto/rand"
 	"fmt"
 
+	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/lestrrat-go/jwx/v2/internal/keyconv"
 	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
 )
 
 type eddsaSigner struct{}
@@ -25,8 +27,19 @@ func (s eddsaSigner) Sign(payload []byte, key interface{}) ([]byte, error) {
 		return nil, fmt.Errorf(`missing private key while signing payload`)
 	}
 
-	// The ed25519.PrivateKey object implements crypto.Signer, so we should
-	// simply accept a crypto.Signer here.
+	// jwk.Key needs to be converted to its raw form first, so that we can
+	// tell Ed25519 and Ed448 keys apart
+	if jwkKey, ok := key.(jwk.Key); ok {
+		var raw interface{}
+		if err := jwkKey.Raw(&raw); err != nil {
+			return nil, fmt.Errorf(`failed to retrieve raw key out of %T: %w`, key, err)
+		}
+		key = raw
+	}
+
+	// Both ed25519.PrivateKey and ed448.PrivateKey objects implement
+	// crypto.Signer, so we should simply accept a crypto.Signer here.
+	// For Ed448, crypto.Hash(0) selects pure Ed448 with an empty context
 	signer, ok := key.(crypto.Signer)
 	if !ok {
 		// This fallback exists for cases when jwk.Key was passed, or
@@ -51,14 +64,28 @@ func (v eddsaVerifier) Verify(payload, signature []byte, key interface{}) (err e
 		return fmt.Errorf(`missing public key while verifying payload`)
 	}
 
-	var pubkey ed25519.PublicKey
-	signer, ok := key.(crypto.Signer)
-	if ok {
-		v := signer.Public()
-		pubkey, ok = v.(ed25519.PublicKey)
-		if !ok {
-			return fmt.Errorf(`expected crypto.Signer.Public() to return ed25519.PublicKey, but got %T`, v)
+	if jwkKey, ok := key.(jwk.Key); ok {
+		var raw interface{}
+		if err := jwkKey.Raw(&raw); err != nil {
+			return fmt.Errorf(`failed to retrieve raw key out of %T: %w`, key, err)
 		}
+		key = raw
+	}
+
+	if signer, ok := key.(crypto.Signer); ok {
+		key = signer.Public()
+	}
+
+	switch key := key.(type) {
+	case ed448.PublicKey:
+		return verifyEd448(key, payload, signature)
+	case *ed448.PublicKey:
+		return verifyEd448(*key, payload, signature)
+	}
+
+	var pubkey ed25519.PublicKey
+	if v, ok := key.(ed25519.PublicKey); ok {
+		pubkey = v
 	} else {
 		if err := keyconv.Ed25519PublicKey(&pubkey, key); err != nil {
 			return fmt.Errorf(`failed to retrieve ed25519.PublicKey out of %T: %w`, key, err)
@@ -71,3 +98,10 @@ func (v eddsaVerifier) Verify(payload, signature []byte, key interface{}) (err e
 
 	return nil
 }
+
+func verifyEd448(pubkey ed448.PublicKey, payload, signature []byte) error {
+	if !ed448.Verify(pubkey, payload, signature, "") {
+		return fmt.Errorf(`failed to match EdDSA signature`)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 )
 
 var hmacSignFuncs = map[jwa.SignatureAlgorithm]hmacSignFunc{}
+var hmacHashFuncs = map[jwa.SignatureAlgorithm]func() hash.Hash{}
 
 func init() {
 	algs := map[jwa.SignatureAlgorithm]func() hash.Hash{
@@ -22,12 +23,14 @@ func init() {
 
 	for alg, h := range algs {
 		hmacSignFuncs[alg] = makeHMACSignFunc(h)
+		hmacHashFuncs[alg] = h
 	}
 }
 
 func newHMACSigner(alg jwa.SignatureAlgorithm) Signer {
 	return &HMACSigner{
 		alg:  alg,
+		hash: hmacHashFuncs[alg], // we know this will succeed
 		sign: hmacSignFuncs[alg], // we know this will succeed
 	}
 }
@@ -59,6 +62,25 @@ func (s HMACSigner) Sign(payload []byte, key interface{}) ([]byte, error) {
 	return s.sign(payload, hmackey)
 }
 
+func (s HMACSigner) NewHash(key interface{}) (hash.Hash, error) {
+	var hmackey []byte
+	if err := keyconv.ByteSliceKey(&hmackey, key); err != nil {
+		return nil, fmt.Errorf(`invalid key type %T. []byte is required: %w`, key, err)
+	}
+
+	if len(hmackey) == 0 {
+		return nil, fmt.Errorf(`missing key while signing payload`)
+	}
+
+	return hmac.New(s.hash, hmackey), nil
+}
+
+// SignHash returns the HMAC computed by the hash.Hash. The key has
+// already been given to NewHash, and is therefore not used.
+func (s HMACSigner) SignHash(h hash.Hash, _ interface{}) ([]byte, error) {
+	return h.Sum(nil), nil
+}
+
 func newHMACVerifier(alg jwa.SignatureAlgorithm) Verifier {
 	s := newHMACSigner(alg)
 	return &HMACVerifier{signer: s}
@@ -75,3 +97,18 @@ func (v HMACVerifier) Verify(payload, signature []byte, key interface{}) (err er
 	}
 	return nil
 }
+
+func (v HMACVerifier) NewHash(key interface{}) (hash.Hash, error) {
+	hs, ok := v.signer.(HashSigner)
+	if !ok {
+		return nil, fmt.Errorf(`signer %T does not implement jws.HashSigner`, v.signer)
+	}
+	return hs.NewHash(key)
+}
+
+func (v HMACVerifier) VerifyHash(h hash.Hash, signature []byte, _ interface{}) error {
+	if !hmac.Equal(signature, h.Sum(nil)) {
+		return fmt.Errorf(`failed to match hmac signature`)
+	}
+	return nil
+}
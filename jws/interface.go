@@ -1,6 +1,8 @@
 package jws
 
 import (
+	"hash"
+
 	"github.com/lestrrat-go/iter/mapiter"
 	"github.com/lestrrat-go/jwx/v2/internal/iter"
 	"github.com/lestrrat-go/jwx/v2/jwa"
@@ -82,11 +84,32 @@ type Signer interface {
 	Algorithm() jwa.SignatureAlgorithm
 }
 
+// HashSigner is a Signer that can generate signatures from a hash.Hash
+// that the signing input has been written to, instead of from the
+// signing input itself. This allows payloads that are too large to
+// be held in memory to be signed (see `jws.SignStream()`).
+//
+// The signers for the RSA, ECDSA, and HMAC family of algorithms implement
+// this interface. The EdDSA signer does not, as Ed25519 requires the
+// entire message to be available when computing the signature.
+type HashSigner interface {
+	Signer
+
+	// NewHash creates a new hash.Hash to write the signing input to.
+	// `key` is the same key that will be passed to SignHash.
+	NewHash(key interface{}) (hash.Hash, error)
+
+	// SignHash creates a signature from a hash.Hash that was created
+	// by NewHash, after the entire signing input has been written to it.
+	SignHash(h hash.Hash, key interface{}) ([]byte, error)
+}
+
 type hmacSignFunc func([]byte, []byte) ([]byte, error)
 
 // HMACSigner uses crypto/hmac to sign the payloads.
 type HMACSigner struct {
 	alg  jwa.SignatureAlgorithm
+	hash func() hash.Hash
 	sign hmacSignFunc
 }
 
@@ -101,6 +124,26 @@ type Verifier interface {
 	Verify(payload []byte, signature []byte, key interface{}) error
 }
 
+// HashVerifier is a Verifier that can verify signatures against a
+// hash.Hash that the signing input has been written to, instead of
+// against the signing input itself (see `jws.VerifyStream()`).
+//
+// Similar to HashSigner, the verifiers for the RSA, ECDSA, and HMAC
+// family of algorithms implement this interface, while the EdDSA
+// verifier does not.
+type HashVerifier interface {
+	Verifier
+
+	// NewHash creates a new hash.Hash to write the signing input to.
+	// `key` is the same key that will be passed to VerifyHash.
+	NewHash(key interface{}) (hash.Hash, error)
+
+	// VerifyHash checks whether the signature is valid for the
+	// hash.Hash that was created by NewHash, after the entire
+	// signing input has been written to it.
+	VerifyHash(h hash.Hash, signature []byte, key interface{}) error
+}
+
 type HMACVerifier struct {
 	signer Signer
 }
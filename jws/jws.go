@@ -36,6 +36,7 @@ import (
 	"unicode"
 	"unicode/utf8"
 
+	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/lestrrat-go/blackmagic"
 	"github.com/lestrrat-go/jwx/v2/internal/base64"
 	"github.com/lestrrat-go/jwx/v2/internal/json"
@@ -43,6 +44,7 @@ import (
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/lestrrat-go/jwx/v2/x25519"
+	"github.com/lestrrat-go/jwx/v2/x448"
 )
 
 var registry = json.NewRegistry()
@@ -162,6 +164,8 @@ func Sign(payload []byte, options ...SignOption) ([]byte, error) {
 				return nil, fmt.Errorf(`jws.Sign: payload must be nil when jws.WithDetachedPayload() is specified`)
 			}
 			payload = option.Value().([]byte)
+		case identDetachedPayloadReader{}:
+			return nil, fmt.Errorf(`jws.Sign: jws.WithDetachedPayloadReader() can only be used with jws.SignStream()`)
 		}
 	}
 
@@ -251,12 +255,24 @@ var allowNoneWhitelist = jwk.WhitelistFunc(func(string) bool {
 // `Verifier` in `verify` subpackage, and call `Verify` method on it.
 // If you need to access signatures and JOSE headers in a JWS message,
 // use `Parse` function to get `Message` object.
+//
+// Signatures whose protected header contain the "crit" header parameter
+// are only considered if all of the extensions listed in it are understood.
+// Other than "b64" (RFC7797), which is handled by this library, you must
+// declare the extensions that you process by using `jws.WithCriticalExtensions()`.
+// Signatures with a "crit" header parameter in the unprotected header are
+// never considered.
 func Verify(buf []byte, options ...VerifyOption) ([]byte, error) {
 	var dst *Message
 	var detachedPayload []byte
 	var keyProviders []KeyProvider
 	var keyUsed interface{}
 
+	understood := make(map[string]struct{})
+	for _, name := range defaultCriticalExtensions {
+		understood[name] = struct{}{}
+	}
+
 	ctx := context.Background()
 
 	//nolint:forcetypeassert
@@ -282,6 +298,10 @@ func Verify(buf []byte, options ...VerifyOption) ([]byte, error) {
 			keyUsed = option.Value()
 		case identContext{}:
 			ctx = option.Value().(context.Context)
+		case identCriticalExtensions{}:
+			for _, name := range option.Value().([]string) {
+				understood[name] = struct{}{}
+			}
 		default:
 			return nil, fmt.Errorf(`invalid jws.VerifyOption %q passed`, `With`+strings.TrimPrefix(fmt.Sprintf(`%T`, option.Ident()), `jws.ident`))
 		}
@@ -316,23 +336,18 @@ func Verify(buf []byte, options ...VerifyOption) ([]byte, error) {
 	verifyBuf := pool.GetBytesBuffer()
 	defer pool.ReleaseBytesBuffer(verifyBuf)
 
+	var critErr error
 	for i, sig := range msg.signatures {
-		verifyBuf.Reset()
-
-		var encodedProtectedHeader string
-		if rbp, ok := sig.protected.(interface{ rawBuffer() []byte }); ok {
-			if raw := rbp.rawBuffer(); raw != nil {
-				encodedProtectedHeader = base64.EncodeToString(raw)
-			}
+		if err := checkCritical(sig, understood); err != nil {
+			critErr = fmt.Errorf(`signature #%d: %w`, i+1, err)
+			continue
 		}
 
-		if encodedProtectedHeader == "" {
-			protected, err := json.Marshal(sig.protected)
-			if err != nil {
-				return nil, fmt.Errorf(`failed to marshal "protected" for signature #%d: %w`, i+1, err)
-			}
+		verifyBuf.Reset()
 
-			encodedProtectedHeader = base64.EncodeToString(protected)
+		encodedProtectedHeader, err := encodeProtectedHeader(sig)
+		if err != nil {
+			return nil, fmt.Errorf(`failed to marshal "protected" for signature #%d: %w`, i+1, err)
 		}
 
 		verifyBuf.WriteString(encodedProtectedHeader)
@@ -375,9 +390,31 @@ func Verify(buf []byte, options ...VerifyOption) ([]byte, error) {
 			}
 		}
 	}
+
+	if critErr != nil {
+		return nil, fmt.Errorf(`could not verify message using any of the signatures or keys: %w`, critErr)
+	}
 	return nil, fmt.Errorf(`could not verify message using any of the signatures or keys`)
 }
 
+// encodeProtectedHeader returns the base64 encoded protected header of
+// the signature, as it should be used in the signing input. If the
+// original representation of the header is available, it is used
+// instead of re-marshaling the header.
+func encodeProtectedHeader(sig *Signature) (string, error) {
+	if rbp, ok := sig.protected.(interface{ rawBuffer() []byte }); ok {
+		if raw := rbp.rawBuffer(); raw != nil {
+			return base64.EncodeToString(raw), nil
+		}
+	}
+
+	protected, err := json.Marshal(sig.protected)
+	if err != nil {
+		return "", err
+	}
+	return base64.EncodeToString(protected), nil
+}
+
 // get the value of b64 header field.
 // If the field does not exist, returns true (default)
 // Otherwise return the value specified by the header field.
@@ -665,6 +702,7 @@ var keyTypeToAlgorithms = make(map[jwa.KeyType][]jwa.SignatureAlgorithm)
 func init() {
 	rawKeyToKeyType[reflect.TypeOf([]byte(nil))] = jwa.OctetSeq
 	rawKeyToKeyType[reflect.TypeOf(ed25519.PublicKey(nil))] = jwa.OKP
+	rawKeyToKeyType[reflect.TypeOf(ed448.PublicKey(nil))] = jwa.OKP
 	rawKeyToKeyType[reflect.TypeOf(rsa.PublicKey{})] = jwa.RSA
 	rawKeyToKeyType[reflect.TypeOf((*rsa.PublicKey)(nil))] = jwa.RSA
 	rawKeyToKeyType[reflect.TypeOf(ecdsa.PublicKey{})] = jwa.EC
@@ -699,7 +737,8 @@ func AlgorithmsForKey(key interface{}) ([]jwa.SignatureAlgorithm, error) {
 		kty = jwa.RSA
 	case ecdsa.PublicKey, *ecdsa.PublicKey, ecdsa.PrivateKey, *ecdsa.PrivateKey:
 		kty = jwa.EC
-	case ed25519.PublicKey, ed25519.PrivateKey, x25519.PublicKey, x25519.PrivateKey:
+	case ed25519.PublicKey, ed25519.PrivateKey, x25519.PublicKey, x25519.PrivateKey,
+		ed448.PublicKey, ed448.PrivateKey, x448.PublicKey, x448.PrivateKey:
 		kty = jwa.OKP
 	case []byte:
 		kty = jwa.OctetSeq
@@ -9,7 +9,9 @@ import (
 	"crypto/ed25519"
 	"crypto/rsa"
 	"crypto/sha512"
+	"crypto/x509"
 	"encoding/asn1"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"math/big"
@@ -18,10 +20,13 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/cloudflare/circl/sign/ed448"
 	"github.com/lestrrat-go/httprc"
+	"github.com/lestrrat-go/jwx/v2/cert"
 	"github.com/lestrrat-go/jwx/v2/internal/base64"
 	"github.com/lestrrat-go/jwx/v2/internal/json"
 	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
@@ -370,6 +375,27 @@ func TestRoundtrip(t *testing.T) {
 			})
 		}
 	})
+	t.Run("EdDSA (Ed448)", func(t *testing.T) {
+		t.Parallel()
+		key, err := jwxtest.GenerateEd448Key()
+		require.NoError(t, err, "ed448 key generated")
+		pubkey := key.Public()
+		jwkKey, err := jwk.FromRaw(pubkey)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		keys := map[string]interface{}{
+			"Verify(ed448.Public())": pubkey,
+			"Verify(jwk.Key)":        jwkKey,
+		}
+		testRoundtrip(t, payload, jwa.EdDSA, key, keys)
+
+		jwkPrivKey, err := jwk.FromRaw(key)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		signed, err := jws.Sign(payload, jws.WithKey(jwa.EdDSA, jwkPrivKey))
+		require.NoError(t, err, `jws.Sign with jwk.Key should succeed`)
+		verified, err := jws.Verify(signed, jws.WithKey(jwa.EdDSA, pubkey))
+		require.NoError(t, err, `jws.Verify should succeed`)
+		require.Equal(t, payload, verified, `verified payload should match`)
+	})
 }
 
 func TestSignMulti2(t *testing.T) {
@@ -1516,7 +1542,8 @@ func TestGH485(t *testing.T) {
     "signatures": [{"protected": %q, "signature": %q}]
 }`, payload, protected, signature)
 
-	verified, err := jws.Verify([]byte(signed), jws.WithKey(jwa.HS256, []byte("secret")))
+	// "exp" is listed in "crit", so it must be declared as understood
+	verified, err := jws.Verify([]byte(signed), jws.WithKey(jwa.HS256, []byte("secret")), jws.WithCriticalExtensions("exp"))
 	if !assert.NoError(t, err, `jws.Verify should succeed`) {
 		return
 	}
@@ -1525,7 +1552,7 @@ func TestGH485(t *testing.T) {
 	}
 
 	compact := strings.Join([]string{protected, payload, signature}, ".")
-	verified, err = jws.Verify([]byte(compact), jws.WithKey(jwa.HS256, []byte("secret")))
+	verified, err = jws.Verify([]byte(compact), jws.WithKey(jwa.HS256, []byte("secret")), jws.WithCriticalExtensions("exp"))
 	if !assert.NoError(t, err, `jws.Verify should succeed`) {
 		return
 	}
@@ -1872,6 +1899,11 @@ func TestAlgorithmsForKey(t *testing.T) {
 			Key:      x25519.PublicKey(nil),
 			Expected: []jwa.SignatureAlgorithm{jwa.EdDSA},
 		},
+		{
+			Name:     "ed448.PublicKey",
+			Key:      ed448.PublicKey(nil),
+			Expected: []jwa.SignatureAlgorithm{jwa.EdDSA},
+		},
 	}
 
 	for _, tc := range testcases {
@@ -1951,3 +1983,251 @@ func TestGH840(t *testing.T) {
 	_, err = jwt.Parse(signed, jwt.WithKey(jwa.ES256, pubkey))
 	require.Error(t, err, `jwt.Parse should FAIL`) // pubkey's X/Y is not on the curve
 }
+
+func TestCachedKeySet(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var keys []jwk.Key
+	for i := 0; i < 3; i++ {
+		key, err := jwxtest.GenerateRsaJwk()
+		require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+		require.NoError(t, key.Set(jwk.KeyIDKey, fmt.Sprintf(`key-%d`, i)), `key.Set should succeed`)
+		require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.RS256), `key.Set should succeed`)
+		keys = append(keys, key)
+	}
+
+	var mu sync.Mutex
+	var hits int
+	published := keys[:1]
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		hits++
+		set := jwk.NewSet()
+		for _, key := range published {
+			pubkey, _ := jwk.PublicKeyOf(key)
+			_ = set.AddKey(pubkey)
+		}
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer srv.Close()
+
+	getHits := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits
+	}
+	publish := func(v []jwk.Key) {
+		mu.Lock()
+		defer mu.Unlock()
+		published = v
+	}
+
+	c := jwk.NewCache(ctx)
+	require.NoError(t, c.Register(srv.URL, jwk.WithMinRefreshInterval(time.Hour)), `c.Register should succeed`)
+	_, err := c.Refresh(ctx, srv.URL)
+	require.NoError(t, err, `c.Refresh should succeed`)
+	require.Equal(t, 1, getHits(), `JWKS should have been fetched once`)
+
+	payload := []byte(`Lorem Ipsum`)
+	sign := func(key jwk.Key) []byte {
+		signed, err := jws.Sign(payload, jws.WithKey(jwa.RS256, key))
+		require.NoError(t, err, `jws.Sign should succeed`)
+		return signed
+	}
+
+	// known key ID: no refresh
+	verified, err := jws.Verify(sign(keys[0]), jws.WithCachedKeySet(c, srv.URL))
+	require.NoError(t, err, `jws.Verify should succeed`)
+	require.Equal(t, payload, verified)
+	require.Equal(t, 1, getHits(), `JWKS should not have been fetched`)
+
+	// rotated key ID: refreshed on demand
+	publish(keys[:2])
+	_, err = jws.Verify(sign(keys[1]), jws.WithCachedKeySet(c, srv.URL))
+	require.NoError(t, err, `jws.Verify should succeed after the JWKS has been refreshed`)
+	require.Equal(t, 2, getHits(), `JWKS should have been fetched`)
+
+	// the same works for jwt.Parse
+	token := jwt.New()
+	require.NoError(t, token.Set(jwt.IssuerKey, `foo`), `token.Set should succeed`)
+	signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, keys[1]))
+	require.NoError(t, err, `jwt.Sign should succeed`)
+	_, err = jwt.Parse(signed, jwt.WithCachedKeySet(c, srv.URL))
+	require.NoError(t, err, `jwt.Parse should succeed`)
+
+	// unknown key IDs can not force further fetches within the
+	// minimum refresh interval
+	for i := 0; i < 10; i++ {
+		bogus, err := jwxtest.GenerateRsaJwk()
+		require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+		require.NoError(t, bogus.Set(jwk.KeyIDKey, fmt.Sprintf(`bogus-%d`, i)), `bogus.Set should succeed`)
+		_, err = jws.Verify(sign(bogus), jws.WithCachedKeySet(c, srv.URL))
+		require.Error(t, err, `jws.Verify should fail`)
+	}
+	require.Equal(t, 2, getHits(), `JWKS should not have been fetched`)
+
+	publish(keys)
+	_, err = jws.Verify(sign(keys[2]), jws.WithCachedKeySet(c, srv.URL))
+	require.Error(t, err, `jws.Verify should fail until the minimum refresh interval has passed`)
+	require.Equal(t, 2, getHits(), `JWKS should not have been fetched`)
+}
+
+func TestVerifyX5C(t *testing.T) {
+	leafKey, chain, root, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+	_, _, otherRoot, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	otherRoots := x509.NewCertPool()
+	otherRoots.AddCert(otherRoot)
+
+	const payload = `Lorem ipsum`
+	sign := func(t *testing.T, key interface{}, chain *cert.Chain) []byte {
+		t.Helper()
+		hdrs := jws.NewHeaders()
+		if chain != nil {
+			require.NoError(t, hdrs.Set(jws.X509CertChainKey, chain), `hdrs.Set should succeed`)
+		}
+		signed, err := jws.Sign([]byte(payload), jws.WithKey(jwa.ES256, key, jws.WithProtectedHeaders(hdrs)))
+		require.NoError(t, err, `jws.Sign should succeed`)
+		return signed
+	}
+
+	otherKey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+
+	t.Run("Trusted chain", func(t *testing.T) {
+		verified, err := jws.Verify(sign(t, leafKey, chain), jws.WithVerifyX5C(roots))
+		require.NoError(t, err, `jws.Verify should succeed`)
+		require.Equal(t, payload, string(verified))
+	})
+	t.Run("Untrusted chain", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, chain), jws.WithVerifyX5C(otherRoots))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("Signed by another key", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, otherKey, chain), jws.WithVerifyX5C(roots))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("Expired chain", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, chain), jws.WithVerifyX5C(roots, jwk.WithX509CurrentTime(time.Now().Add(2*time.Hour))))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("No x5c", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, nil), jws.WithVerifyX5C(roots))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("No roots", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, chain), jws.WithVerifyX5C(nil))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+}
+
+func TestVerifyX5U(t *testing.T) {
+	leafKey, chain, root, err := jwxtest.GenerateX509Chain()
+	require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+
+	var chainPEM []byte
+	for i := 0; i < chain.Len(); i++ {
+		src, _ := chain.Get(i)
+		der, err := base64.Decode(src)
+		require.NoError(t, err, `base64.Decode should succeed`)
+		chainPEM = append(chainPEM, pem.EncodeToMemory(&pem.Block{Type: `CERTIFICATE`, Bytes: der})...)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(chainPEM)
+	}))
+	defer srv.Close()
+
+	const payload = `Lorem ipsum`
+	sign := func(t *testing.T, key interface{}, u string) []byte {
+		t.Helper()
+		hdrs := jws.NewHeaders()
+		require.NoError(t, hdrs.Set(jws.X509URLKey, u), `hdrs.Set should succeed`)
+		signed, err := jws.Sign([]byte(payload), jws.WithKey(jwa.ES256, key, jws.WithProtectedHeaders(hdrs)))
+		require.NoError(t, err, `jws.Sign should succeed`)
+		return signed
+	}
+
+	otherKey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+
+	u := srv.URL + `/chain.pem`
+	options := []jwk.FetchOption{
+		jwk.WithHTTPClient(srv.Client()),
+		jwk.WithFetchWhitelist(jwk.InsecureWhitelist{}),
+	}
+
+	t.Run("Fetch", func(t *testing.T) {
+		verified, err := jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(roots, nil, options...))
+		require.NoError(t, err, `jws.Verify should succeed`)
+		require.Equal(t, payload, string(verified))
+	})
+	t.Run("Signed by another key", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, otherKey, u), jws.WithVerifyX5U(roots, nil, options...))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("No whitelist", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(roots, nil, jwk.WithHTTPClient(srv.Client())))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("No roots", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(nil, nil, options...))
+		require.Error(t, err, `jws.Verify should fail`)
+
+		// roots passed as a fetch option are not enough
+		_, err = jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(nil, nil, append(options, jwk.WithX509Roots(roots))...))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("Not HTTPS", func(t *testing.T) {
+		_, err := jws.Verify(sign(t, leafKey, strings.Replace(u, `https://`, `http://`, 1)), jws.WithVerifyX5U(roots, nil, options...))
+		require.Error(t, err, `jws.Verify should fail`)
+	})
+	t.Run("Cache", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		c := jwk.NewCache(ctx)
+		require.NoError(t, c.Register(u, jwk.WithX5U(true), jwk.WithHTTPClient(srv.Client()), jwk.WithX509Roots(roots)), `c.Register should succeed`)
+
+		verified, err := jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(roots, jwk.NewCachedFetcher(c)))
+		require.NoError(t, err, `jws.Verify should succeed`)
+		require.Equal(t, payload, string(verified))
+
+		// the cached chain is verified again each time it is used
+		_, err = jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(roots, jwk.NewCachedFetcher(c), jwk.WithX509CurrentTime(time.Now().Add(2*time.Hour))))
+		require.Error(t, err, `jws.Verify should fail after the chain expires`)
+
+		_, _, otherRoot, err := jwxtest.GenerateX509Chain()
+		require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+		otherRoots := x509.NewCertPool()
+		otherRoots.AddCert(otherRoot)
+		_, err = jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(otherRoots, jwk.NewCachedFetcher(c)))
+		require.Error(t, err, `jws.Verify should fail with roots that the chain does not validate against`)
+	})
+	t.Run("Fetcher ignoring roots", func(t *testing.T) {
+		_, _, otherRoot, err := jwxtest.GenerateX509Chain()
+		require.NoError(t, err, `jwxtest.GenerateX509Chain should succeed`)
+		otherRoots := x509.NewCertPool()
+		otherRoots.AddCert(otherRoot)
+
+		f := jwk.FetchFunc(func(ctx context.Context, u string, _ ...jwk.FetchOption) (jwk.Set, error) {
+			return jwk.FetchX5U(ctx, u, jwk.WithHTTPClient(srv.Client()), jwk.WithX509Roots(roots))
+		})
+		_, err = jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(otherRoots, f, options...))
+		require.Error(t, err, `jws.Verify should fail`)
+
+		verified, err := jws.Verify(sign(t, leafKey, u), jws.WithVerifyX5U(roots, f, options...))
+		require.NoError(t, err, `jws.Verify should succeed`)
+		require.Equal(t, payload, string(verified))
+	})
+}
@@ -2,10 +2,12 @@ package jws
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net/url"
 	"sync"
 
+	"github.com/lestrrat-go/jwx/v2/cert"
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 )
@@ -15,7 +17,8 @@ import (
 //
 // `jws.Sign()` can only accept static key providers via `jws.WithKey()`,
 // while `jws.Verify()` can accept `jws.WithKey()`, `jws.WithKeySet()`,
-// `jws.WithVerifyAuto()`, and `jws.WithKeyProvider()`.
+// `jws.WithCachedKeySet()`, `jws.WithVerifyAuto()`, `jws.WithVerifyX5C()`,
+// `jws.WithVerifyX5U()`, and `jws.WithKeyProvider()`.
 //
 // Understanding how this works is crucial to learn how this package works.
 //
@@ -109,8 +112,14 @@ type keySetProvider struct {
 	multipleKeysPerKeyID bool // true if we should attempt to match multiple keys per key ID. if false we assume that only one key exists for a given key ID
 }
 
+// signatureKeyFilter matches keys that may be used for signatures
+var signatureKeyFilter = jwk.MatchAny(
+	jwk.MatchKeyUsage(""),
+	jwk.MatchKeyUsage(jwk.ForSignature),
+)
+
 func (kp *keySetProvider) selectKey(sink KeySink, key jwk.Key, sig *Signature, _ *Message) error {
-	if usage := key.KeyUsage(); usage != "" && usage != jwk.ForSignature.String() {
+	if !signatureKeyFilter.Match(key) {
 		return nil
 	}
 
@@ -181,13 +190,13 @@ func (kp *keySetProvider) FetchKeys(_ context.Context, sink KeySink, sig *Signat
 
 		// if multipleKeysPerKeyID is true, we attempt all keys whose key ID matches
 		// the wantedKey
+		candidates, err := jwk.Select(kp.set, jwk.MatchKeyID(wantedKid))
+		if err != nil {
+			return fmt.Errorf(`failed to select keys: %w`, err)
+		}
 		var ok bool
-		for i := 0; i < kp.set.Len(); i++ {
-			key, _ := kp.set.Key(i)
-			if key.KeyID() != wantedKid {
-				continue
-			}
-
+		for i := 0; i < candidates.Len(); i++ {
+			key, _ := candidates.Key(i)
 			if err := kp.selectKey(sink, key, sig, msg); err != nil {
 				continue
 			}
@@ -200,9 +209,13 @@ func (kp *keySetProvider) FetchKeys(_ context.Context, sink KeySink, sig *Signat
 		return nil
 	}
 
-	// Otherwise just try all keys
-	for i := 0; i < kp.set.Len(); i++ {
-		key, _ := kp.set.Key(i)
+	// Otherwise just try all keys that may be used for signatures
+	candidates, err := jwk.Select(kp.set, signatureKeyFilter)
+	if err != nil {
+		return fmt.Errorf(`failed to select keys: %w`, err)
+	}
+	for i := 0; i < candidates.Len(); i++ {
+		key, _ := candidates.Key(i)
 		if err := kp.selectKey(sink, key, sig, msg); err != nil {
 			continue
 		}
@@ -210,6 +223,37 @@ func (kp *keySetProvider) FetchKeys(_ context.Context, sink KeySink, sig *Signat
 	return nil
 }
 
+type cachedKeySetProvider struct {
+	cache *jwk.Cache
+	url   string
+	// template for the keySetProvider. `set` is filled in for each call
+	keySetProvider
+}
+
+func (kp *cachedKeySetProvider) FetchKeys(ctx context.Context, sink KeySink, sig *Signature, msg *Message) error {
+	set, err := kp.cache.Get(ctx, kp.url)
+	if err != nil {
+		return fmt.Errorf(`failed to fetch %q: %w`, kp.url, err)
+	}
+
+	// If the key ID is not in the set, the keys may have been rotated
+	// since we last fetched the set. Refresh it, unless it has been
+	// refreshed recently.
+	if kid := sig.ProtectedHeaders().KeyID(); kid != "" {
+		if _, ok := set.LookupKeyID(kid); !ok {
+			refreshed, _, err := kp.cache.RefreshLazy(ctx, kp.url)
+			if err != nil {
+				return fmt.Errorf(`failed to refresh %q: %w`, kp.url, err)
+			}
+			set = refreshed
+		}
+	}
+
+	sub := kp.keySetProvider
+	sub.set = set
+	return sub.FetchKeys(ctx, sink, sig, msg)
+}
+
 type jkuProvider struct {
 	fetcher jwk.Fetcher
 	options []jwk.FetchOption
@@ -266,6 +310,135 @@ func (kp jkuProvider) FetchKeys(ctx context.Context, sink KeySink, sig *Signatur
 	return nil
 }
 
+type x5uProvider struct {
+	roots   *x509.CertPool
+	fetcher jwk.Fetcher
+	options []jwk.FetchOption
+}
+
+func (kp x5uProvider) FetchKeys(ctx context.Context, sink KeySink, sig *Signature, _ *Message) error {
+	if kp.roots == nil {
+		return fmt.Errorf(`use of "x5u" requires a set of trusted root certificates`)
+	}
+
+	hdrs := sig.ProtectedHeaders()
+	u := hdrs.X509URL()
+	if u == "" {
+		return fmt.Errorf(`use of "x5u" requires that the payload contain a "x5u" field in the protected header`)
+	}
+	uo, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf(`failed to parse "x5u": %w`, err)
+	}
+	if uo.Scheme != "https" {
+		return fmt.Errorf(`url in "x5u" must be HTTPS`)
+	}
+
+	set, err := kp.fetcher.Fetch(ctx, u, kp.options...)
+	if err != nil {
+		return fmt.Errorf(`failed to fetch %q: %w`, u, err)
+	}
+
+	// the set contains the key from the leaf certificate
+	key, ok := set.Key(0)
+	if !ok {
+		return fmt.Errorf(`no keys found in %q`, u)
+	}
+
+	// The fetcher may not have verified the chain against our roots
+	// (e.g. jwk.CachedFetcher), and cached chains may have expired
+	// since they were fetched, so always verify the chain here
+	verifyOptions := make([]jwk.VerifyX509Option, 0, len(kp.options)+1)
+	for _, option := range kp.options {
+		if verifyOption, ok := option.(jwk.VerifyX509Option); ok {
+			verifyOptions = append(verifyOptions, verifyOption)
+		}
+	}
+	verifyOptions = append(verifyOptions, jwk.WithX509Roots(kp.roots))
+	if _, err := jwk.VerifyX509Chain(key, verifyOptions...); err != nil {
+		return fmt.Errorf(`failed to verify certificate chain in %q: %w`, u, err)
+	}
+
+	algs, err := AlgorithmsForKey(key)
+	if err != nil {
+		return fmt.Errorf(`failed to get a list of signature methods for key type %s: %w`, key.KeyType(), err)
+	}
+
+	hdrAlg := hdrs.Algorithm()
+	for _, alg := range algs {
+		if hdrAlg != "" && hdrAlg != alg {
+			continue
+		}
+
+		sink.Key(alg, key)
+		break
+	}
+	return nil
+}
+
+type x5cProvider struct {
+	roots   *x509.CertPool
+	options []jwk.VerifyX509Option
+}
+
+func (kp x5cProvider) FetchKeys(_ context.Context, sink KeySink, sig *Signature, _ *Message) error {
+	if kp.roots == nil {
+		return fmt.Errorf(`use of "x5c" requires a set of trusted root certificates`)
+	}
+
+	hdrs := sig.ProtectedHeaders()
+	chain := hdrs.X509CertChain()
+	if chain == nil || chain.Len() == 0 {
+		return fmt.Errorf(`use of "x5c" requires that the payload contain a "x5c" field in the protected header`)
+	}
+
+	src, _ := chain.Get(0)
+	leaf, err := cert.Parse(src)
+	if err != nil {
+		return fmt.Errorf(`failed to parse leaf certificate in "x5c": %w`, err)
+	}
+
+	key, err := jwk.FromRaw(leaf.PublicKey)
+	if err != nil {
+		return fmt.Errorf(`failed to create key from leaf certificate: %w`, err)
+	}
+	if err := key.Set(jwk.X509CertChainKey, chain); err != nil {
+		return fmt.Errorf(`failed to set "x5c": %w`, err)
+	}
+	// the thumbprints in the header, if any, must match the leaf as well
+	if v := hdrs.X509CertThumbprint(); v != "" {
+		if err := key.Set(jwk.X509CertThumbprintKey, v); err != nil {
+			return fmt.Errorf(`failed to set "x5t": %w`, err)
+		}
+	}
+	if v := hdrs.X509CertThumbprintS256(); v != "" {
+		if err := key.Set(jwk.X509CertThumbprintS256Key, v); err != nil {
+			return fmt.Errorf(`failed to set "x5t#S256": %w`, err)
+		}
+	}
+
+	options := append([]jwk.VerifyX509Option{jwk.WithX509Roots(kp.roots)}, kp.options...)
+	if _, err := jwk.VerifyX509Chain(key, options...); err != nil {
+		return fmt.Errorf(`failed to verify "x5c": %w`, err)
+	}
+
+	algs, err := AlgorithmsForKey(key)
+	if err != nil {
+		return fmt.Errorf(`failed to get a list of signature methods for key type %s: %w`, key.KeyType(), err)
+	}
+
+	hdrAlg := hdrs.Algorithm()
+	for _, alg := range algs {
+		if hdrAlg != "" && hdrAlg != alg {
+			continue
+		}
+
+		sink.Key(alg, key)
+		break
+	}
+	return nil
+}
+
 // KeyProviderFunc is a type of KeyProvider that is implemented by
 // a single function. You can use this to create ad-hoc `KeyProvider`
 // instances.
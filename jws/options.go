@@ -1,12 +1,15 @@
 package jws
 
 import (
+	"crypto/x509"
+
 	"github.com/lestrrat-go/jwx/v2/jwa"
 	"github.com/lestrrat-go/jwx/v2/jwk"
 	"github.com/lestrrat-go/option"
 )
 
 type identHeaders struct{}
+type identCriticalExtensions struct{}
 
 // WithHeaders allows you to specify extra header values to include in the
 // final JWS message
@@ -118,6 +121,10 @@ func WithKey(alg jwa.KeyAlgorithm, key interface{}, options ...WithKeySuboption)
 // The behavior can be tweaked by using the `jws.WithKeySetSuboption`
 // suboption types.
 func WithKeySet(set jwk.Set, options ...WithKeySetSuboption) VerifyOption {
+	return WithKeyProvider(newKeySetProvider(set, options...))
+}
+
+func newKeySetProvider(set jwk.Set, options ...WithKeySetSuboption) *keySetProvider {
 	requireKid := true
 	var useDefault, inferAlgorithm, multipleKeysPerKeyID bool
 	for _, option := range options {
@@ -134,12 +141,83 @@ func WithKeySet(set jwk.Set, options ...WithKeySetSuboption) VerifyOption {
 		}
 	}
 
-	return WithKeyProvider(&keySetProvider{
+	return &keySetProvider{
 		set:                  set,
 		requireKid:           requireKid,
 		useDefault:           useDefault,
 		multipleKeysPerKeyID: multipleKeysPerKeyID,
 		inferAlgorithm:       inferAlgorithm,
+	}
+}
+
+// WithCachedKeySet specifies a JWKS stored in a `jwk.Cache` to use for
+// verification. The URL `u` must have been registered with the cache.
+//
+// Keys are selected in the same way as `jws.WithKeySet()`, and the
+// same suboptions are accepted. However, when the JWS message is signed
+// by a key ID that is not found in the JWKS, the JWKS is refreshed
+// before looking up the key again, so that keys that have just been
+// rotated in can be used without waiting for the next scheduled refresh.
+//
+// In order to prevent attackers from forcing the JWKS to be fetched
+// by sending random key IDs, such refreshes are performed at most once
+// per minimum refresh interval, as specified by `jwk.WithMinRefreshInterval()`
+// when `u` was registered. See `(jwk.Cache).RefreshLazy()`
+func WithCachedKeySet(cache *jwk.Cache, u string, options ...WithKeySetSuboption) VerifyOption {
+	return WithKeyProvider(&cachedKeySetProvider{
+		cache:          cache,
+		url:            u,
+		keySetProvider: *newKeySetProvider(nil, options...),
+	})
+}
+
+// WithVerifyX5C specifies that the JWS message should be verified using
+// the public key of the leaf certificate found in the "x5c" field of the
+// protected header.
+//
+// The key is only used if the certificate chain in "x5c" can be verified
+// against `roots` using `jwk.VerifyX509Chain()`. Additional constraints
+// such as the verification time and the extended key usages can be
+// specified using `jwk.VerifyX509Option`s. `roots` must not be nil.
+func WithVerifyX5C(roots *x509.CertPool, options ...jwk.VerifyX509Option) VerifyOption {
+	return WithKeyProvider(x5cProvider{
+		roots:   roots,
+		options: options,
+	})
+}
+
+// WithVerifyX5U specifies that the JWS message should be verified using
+// the public key of the leaf certificate in the PEM encoded certificate
+// chain referred to by the "x5u" field of the protected header.
+// The URL must be HTTPS.
+//
+// The key is only used if the certificate chain can be verified against
+// `roots` using `jwk.VerifyX509Chain()`. As with `jws.WithVerifyX5C()`,
+// `roots` must not be nil. Other `jwk.VerifyX509Option`s may be passed to
+// further restrict how the chain is verified. As with `jws.WithVerifyAuto()`,
+// you MUST provide a `jwk.Whitelist` using `jwk.WithFetchWhitelist()`
+// to allow the URLs to be fetched.
+//
+// By default `jwk.FetchX5U()` is used to fetch and verify the chain.
+// To cache and periodically refresh the chains, register the URLs
+// with a `jwk.Cache` using `jwk.WithX5U(true)` and `jwk.WithX509Roots()`,
+// and pass a `jwk.CachedFetcher` as `f`. Regardless of `f`, the chain
+// of the key that it returns is verified against `roots` every time
+// a message is verified.
+func WithVerifyX5U(roots *x509.CertPool, f jwk.Fetcher, options ...jwk.FetchOption) VerifyOption {
+	if f == nil {
+		f = jwk.FetchFunc(jwk.FetchX5U)
+	}
+
+	// the option MUST start with a "disallow no whitelist" to force
+	// users provide a whitelist, and MUST end with the roots so that
+	// they cannot be overridden
+	options = append(append([]jwk.FetchOption{jwk.WithFetchWhitelist(allowNoneWhitelist)}, options...), jwk.WithX509Roots(roots))
+
+	return WithKeyProvider(x5uProvider{
+		roots:   roots,
+		fetcher: f,
+		options: options,
 	})
 }
 
@@ -157,3 +235,17 @@ func WithVerifyAuto(f jwk.Fetcher, options ...jwk.FetchOption) VerifyOption {
 		options: options,
 	})
 }
+
+// WithCriticalExtensions specifies the names of the extension header
+// parameters that the caller understands and processes. When a JWS
+// message lists extensions in its "crit" header parameter, `jws.Verify()`
+// rejects the signature unless all of them have been declared using
+// this option (RFC7515 Section 4.1.11).
+//
+// "b64" (RFC7797) is always understood, and need not be specified.
+//
+// This option may be specified multiple times, in which case the
+// names are accumulated.
+func WithCriticalExtensions(names ...string) VerifyOption {
+	return &verifyOption{option.New(identCriticalExtensions{}, append([]string(nil), names...))}
+}
@@ -4,6 +4,7 @@ package jws
 
 import (
 	"context"
+	"io"
 	"io/fs"
 
 	"github.com/lestrrat-go/option"
@@ -126,6 +127,7 @@ func (*withKeySuboption) withKeySuboption() {}
 type identContext struct{}
 type identDetached struct{}
 type identDetachedPayload struct{}
+type identDetachedPayloadReader struct{}
 type identFS struct{}
 type identInferAlgorithmFromKey struct{}
 type identKey struct{}
@@ -152,6 +154,10 @@ func (identDetachedPayload) String() string {
 	return "WithDetachedPayload"
 }
 
+func (identDetachedPayloadReader) String() string {
+	return "WithDetachedPayloadReader"
+}
+
 func (identFS) String() string {
 	return "WithFS"
 }
@@ -226,6 +232,16 @@ func WithDetachedPayload(v []byte) SignVerifyOption {
 	return &signVerifyOption{option.New(identDetachedPayload{}, v)}
 }
 
+// WithDetachedPayloadReader can be used to both sign or verify a JWS message
+// with a detached payload that is read from an `io.Reader`. It can only be
+// used with `jws.SignStream()` and `jws.VerifyStream()`.
+//
+// When this option is used for `jws.SignStream()`, the payload parameter
+// must be set to `nil`.
+func WithDetachedPayloadReader(v io.Reader) SignVerifyOption {
+	return &signVerifyOption{option.New(identDetachedPayloadReader{}, v)}
+}
+
 // WithFS specifies the source `fs.FS` object to read the file from.
 func WithFS(v fs.FS) ReadFileOption {
 	return &readFileOption{option.New(identFS{}, v)}
@@ -12,6 +12,7 @@ func TestOptionIdent(t *testing.T) {
 	require.Equal(t, "WithContext", identContext{}.String())
 	require.Equal(t, "WithDetached", identDetached{}.String())
 	require.Equal(t, "WithDetachedPayload", identDetachedPayload{}.String())
+	require.Equal(t, "WithDetachedPayloadReader", identDetachedPayloadReader{}.String())
 	require.Equal(t, "WithFS", identFS{}.String())
 	require.Equal(t, "WithInferAlgorithmFromKey", identInferAlgorithmFromKey{}.String())
 	require.Equal(t, "WithKey", identKey{}.String())
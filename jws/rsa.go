@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
+	"hash"
 
 	"github.com/lestrrat-go/jwx/v2/internal/keyconv"
 	"github.com/lestrrat-go/jwx/v2/jwa"
@@ -72,6 +73,21 @@ func (rs *rsaSigner) Algorithm() jwa.SignatureAlgorithm {
 }
 
 func (rs *rsaSigner) Sign(payload []byte, key interface{}) ([]byte, error) {
+	h, err := rs.NewHash(key)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := h.Write(payload); err != nil {
+		return nil, fmt.Errorf(`failed to write payload to hash: %w`, err)
+	}
+	return rs.SignHash(h, key)
+}
+
+func (rs *rsaSigner) NewHash(_ interface{}) (hash.Hash, error) {
+	return rs.hash.New(), nil
+}
+
+func (rs *rsaSigner) SignHash(h hash.Hash, key interface{}) ([]byte, error) {
 	if key == nil {
 		return nil, fmt.Errorf(`missing private key while signing payload`)
 	}
@@ -85,10 +101,6 @@ func (rs *rsaSigner) Sign(payload []byte, key interface{}) ([]byte, error) {
 		signer = &privkey
 	}
 
-	h := rs.hash.New()
-	if _, err := h.Write(payload); err != nil {
-		return nil, fmt.Errorf(`failed to write payload to hash: %w`, err)
-	}
 	if rs.pss {
 		return signer.Sign(rand.Reader, h.Sum(nil), &rsa.PSSOptions{
 			Hash:       rs.hash,
@@ -109,6 +121,21 @@ func newRSAVerifier(alg jwa.SignatureAlgorithm) Verifier {
 }
 
 func (rv *rsaVerifier) Verify(payload, signature []byte, key interface{}) error {
+	h, err := rv.NewHash(key)
+	if err != nil {
+		return err
+	}
+	if _, err := h.Write(payload); err != nil {
+		return fmt.Errorf(`failed to write payload to hash: %w`, err)
+	}
+	return rv.VerifyHash(h, signature, key)
+}
+
+func (rv *rsaVerifier) NewHash(_ interface{}) (hash.Hash, error) {
+	return rv.hash.New(), nil
+}
+
+func (rv *rsaVerifier) VerifyHash(h hash.Hash, signature []byte, key interface{}) error {
 	if key == nil {
 		return fmt.Errorf(`missing public key while verifying payload`)
 	}
@@ -130,11 +157,6 @@ func (rv *rsaVerifier) Verify(payload, signature []byte, key interface{}) error
 		}
 	}
 
-	h := rv.hash.New()
-	if _, err := h.Write(payload); err != nil {
-		return fmt.Errorf(`failed to write payload to hash: %w`, err)
-	}
-
 	if rv.pss {
 		return rsa.VerifyPSS(&pubkey, rv.hash, h.Sum(nil), signature, nil)
 	}
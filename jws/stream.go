@@ -0,0 +1,607 @@
+package jws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/lestrrat-go/blackmagic"
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+// streamSignature is used to serialize the parts of a JWS message in
+// JSON serialization that follow the payload.
+type streamSignature struct {
+	Headers   Headers `json:"header,omitempty"`
+	Protected string  `json:"protected"`
+	Signature string  `json:"signature"`
+}
+
+// dotCheckWriter makes sure that no '.' is written to the underlying
+// writer, which would break a compact serialization with an unencoded
+// payload.
+type dotCheckWriter struct {
+	dst io.Writer
+}
+
+func (w *dotCheckWriter) Write(p []byte) (int, error) {
+	if bytes.IndexByte(p, '.') >= 0 {
+		return 0, fmt.Errorf(`payload must not contain a "."`)
+	}
+	return w.dst.Write(p)
+}
+
+// SignStream generates a JWS message for the payload read from `payload`,
+// and writes it in serialized form to `dst`. It works just like `jws.Sign()`,
+// except that the payload is hashed incrementally as it is being read, so
+// that the payload never needs to be held in memory as a whole.
+//
+// Only signers that implement `jws.HashSigner` can be used with this
+// function. Signers for the RSA, ECDSA, and HMAC family of algorithms do,
+// but EdDSA cannot be used as Ed25519 requires the entire payload to be
+// available when computing the signature.
+//
+// If you want to use a detached payload, pass `nil` as `payload` and
+// use the `jws.WithDetachedPayloadReader()` option. When the `b64` header
+// parameter is set to false (RFC7797), the payload can only be written
+// in compact serialization or as a detached payload.
+//
+//	err := jws.SignStream(dst, nil,
+//	  jws.WithDetachedPayloadReader(f),
+//	  jws.WithKey(jwa.RS256, key, jws.WithProtectedHeaders(hdrs)),
+//	)
+//
+// Note that when an error occurs while the payload is being processed,
+// `dst` may already contain a partially written message.
+func SignStream(dst io.Writer, payload io.Reader, options ...SignOption) error {
+	format := fmtCompact
+	var signers []*payloadSigner
+	var detached bool
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identSerialization{}:
+			format = option.Value().(int)
+		case identKey{}:
+			data := option.Value().(*withKey)
+
+			alg, ok := data.alg.(jwa.SignatureAlgorithm)
+			if !ok {
+				return fmt.Errorf(`jws.SignStream: expected algorithm to be of type jwa.SignatureAlgorithm but got (%[1]q, %[1]T)`, data.alg)
+			}
+			signer, err := makeSigner(alg, data.key, data.public, data.protected)
+			if err != nil {
+				return fmt.Errorf(`jws.SignStream: failed to create signer: %w`, err)
+			}
+			signers = append(signers, signer)
+		case identDetachedPayload{}:
+			return fmt.Errorf(`jws.SignStream: use jws.WithDetachedPayloadReader() to sign a detached payload`)
+		case identDetachedPayloadReader{}:
+			detached = true
+			if payload != nil {
+				return fmt.Errorf(`jws.SignStream: payload must be nil when jws.WithDetachedPayloadReader() is specified`)
+			}
+			payload = option.Value().(io.Reader)
+		}
+	}
+
+	if payload == nil {
+		return fmt.Errorf(`jws.SignStream: payload must be provided`)
+	}
+
+	lsigner := len(signers)
+	if lsigner == 0 {
+		return fmt.Errorf(`jws.SignStream: no signers available. Specify an algorithm and a key using jws.WithKey()`)
+	}
+
+	if format == fmtCompact && lsigner != 1 {
+		return fmt.Errorf(`jws.SignStream: cannot have multiple signers (keys) specified for compact serialization. Use only one jws.WithKey()`)
+	}
+
+	b64 := true
+	hashSigners := make([]HashSigner, lsigner)
+	hashes := make([]hash.Hash, lsigner)
+	hashWriters := make([]io.Writer, lsigner)
+	encodedHeaders := make([]string, lsigner)
+	for i, signer := range signers {
+		hs, ok := signer.signer.(HashSigner)
+		if !ok {
+			return fmt.Errorf(`jws.SignStream: signer for algorithm %q does not support streaming (it must implement jws.HashSigner)`, signer.Algorithm())
+		}
+
+		if format == fmtCompact && signer.PublicHeader() != nil {
+			return fmt.Errorf(`jws.SignStream: public headers cannot be used with compact serialization`)
+		}
+
+		protected := signer.ProtectedHeader()
+		if protected == nil {
+			protected = NewHeaders()
+		}
+
+		if err := protected.Set(AlgorithmKey, signer.Algorithm()); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to set "alg" header: %w`, err)
+		}
+
+		if key, ok := signer.key.(jwk.Key); ok {
+			if kid := key.KeyID(); kid != "" {
+				if err := protected.Set(KeyIDKey, kid); err != nil {
+					return fmt.Errorf(`jws.SignStream: failed to set "kid" header: %w`, err)
+				}
+			}
+		}
+
+		if v := getB64Value(protected); i == 0 {
+			b64 = v
+		} else if v != b64 {
+			return fmt.Errorf(`jws.SignStream: b64 value must be the same for all signatures`)
+		}
+
+		hdrbuf, err := json.Marshal(protected)
+		if err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to marshal headers for signer #%d: %w`, i, err)
+		}
+		encodedHeaders[i] = base64.EncodeToString(hdrbuf)
+
+		h, err := hs.NewHash(signer.key)
+		if err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to create hash for signer #%d (alg=%s): %w`, i, signer.Algorithm(), err)
+		}
+		if _, err := io.WriteString(h, encodedHeaders[i]+"."); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to write protected headers to hash: %w`, err)
+		}
+
+		hashSigners[i] = hs
+		hashes[i] = h
+		hashWriters[i] = h
+	}
+
+	if !b64 && !detached && format != fmtCompact {
+		return fmt.Errorf(`jws.SignStream: unencoded payload ("b64": false) can only be used with compact serialization or a detached payload`)
+	}
+
+	payloadDst := io.MultiWriter(hashWriters...)
+	if !detached {
+		var prefix string
+		switch format {
+		case fmtCompact:
+			prefix = encodedHeaders[0] + "."
+		case fmtJSON:
+			prefix = `{"payload":"`
+		case fmtJSONPretty:
+			prefix = "{\n  \"payload\": \""
+		}
+		if _, err := io.WriteString(dst, prefix); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to write message: %w`, err)
+		}
+		payloadDst = io.MultiWriter(payloadDst, dst)
+	}
+
+	if b64 {
+		enc := base64.NewEncoder(payloadDst)
+		if _, err := io.Copy(enc, payload); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to process payload: %w`, err)
+		}
+		if err := enc.Close(); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to process payload: %w`, err)
+		}
+	} else {
+		if !detached {
+			payloadDst = &dotCheckWriter{dst: payloadDst}
+		}
+		if _, err := io.Copy(payloadDst, payload); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to process payload: %w`, err)
+		}
+	}
+
+	sigs := make([]*streamSignature, lsigner)
+	for i, signer := range signers {
+		signature, err := hashSigners[i].SignHash(hashes[i], signer.key)
+		if err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to generate signature for signer #%d (alg=%s): %w`, i, signer.Algorithm(), err)
+		}
+		sigs[i] = &streamSignature{
+			Headers:   signer.PublicHeader(),
+			Protected: encodedHeaders[i],
+			Signature: base64.EncodeToString(signature),
+		}
+	}
+
+	if format == fmtCompact {
+		var suffix string
+		if detached {
+			suffix = encodedHeaders[0] + ".."
+		} else {
+			suffix = "."
+		}
+		if _, err := io.WriteString(dst, suffix+sigs[0].Signature); err != nil {
+			return fmt.Errorf(`jws.SignStream: failed to write message: %w`, err)
+		}
+		return nil
+	}
+
+	var rest interface{}
+	if lsigner == 1 {
+		rest = sigs[0]
+	} else {
+		rest = struct {
+			Signatures []*streamSignature `json:"signatures"`
+		}{Signatures: sigs}
+	}
+
+	var restbuf []byte
+	var err error
+	if format == fmtJSONPretty {
+		restbuf, err = json.MarshalIndent(rest, "", "  ")
+	} else {
+		restbuf, err = json.Marshal(rest)
+	}
+	if err != nil {
+		return fmt.Errorf(`jws.SignStream: failed to marshal signatures: %w`, err)
+	}
+
+	if !detached {
+		// The opening brace has already been written along with the payload
+		restbuf = append([]byte(`",`), restbuf[1:]...)
+	}
+	if _, err := dst.Write(restbuf); err != nil {
+		return fmt.Errorf(`jws.SignStream: failed to write message: %w`, err)
+	}
+	return nil
+}
+
+// streamCandidate is a combination of a signature and a key that
+// may be able to verify it, along with the hash that the signing
+// input is being written to.
+type streamCandidate struct {
+	sig      *Signature
+	key      interface{}
+	verifier HashVerifier
+	hash     hash.Hash
+}
+
+// segmentReader reads a single segment of a compact serialization,
+// that is, up to (but not including) the next '.' in the input.
+type segmentReader struct {
+	src     *bufio.Reader
+	pending []byte
+	done    bool
+}
+
+func (r *segmentReader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+
+		chunk, err := r.src.ReadSlice('.')
+		switch {
+		case err == nil:
+			chunk = chunk[:len(chunk)-1]
+			r.done = true
+		case errors.Is(err, bufio.ErrBufferFull):
+		case errors.Is(err, io.EOF):
+			return 0, fmt.Errorf(`invalid number of segments`)
+		default:
+			return 0, err
+		}
+		// chunk refers to the internal buffer of r.src, but it is not
+		// read from until all of the pending bytes have been consumed
+		r.pending = chunk
+	}
+
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+// VerifyStream checks if the JWS message read from `src` is verifiable,
+// just like `jws.Verify()`. Upon successful verification, `err` is nil.
+//
+// Unlike `jws.Verify()`, the payload is hashed incrementally as it is
+// being read, so that it never needs to be held in memory as a whole.
+// This works with the following inputs:
+//
+//   - A message with a detached payload, in either compact or JSON
+//     serialization. The payload is read from the `io.Reader` given in the
+//     `jws.WithDetachedPayloadReader()` option, and nothing is written to `dst`.
+//   - A message in compact serialization. The decoded payload is written to
+//     `dst` as it is read from `src`.
+//
+// Messages in JSON serialization that contain the payload are read into
+// memory as a whole, as the order of the members in the JSON object is
+// not guaranteed. The payload is written to `dst` after verification.
+//
+// Note that when the payload is streamed, it is written to `dst` BEFORE the
+// signature has been verified. You MUST discard what has been written to
+// `dst` if an error is returned.
+//
+// Only verifiers that implement `jws.HashVerifier` can be used with this
+// function. Verifiers for the RSA, ECDSA, and HMAC family of algorithms do,
+// but EdDSA cannot be used. Keys for algorithms that do not support
+// streaming are ignored.
+//
+// `dst` may be nil, in which case the payload is discarded.
+func VerifyStream(src io.Reader, dst io.Writer, options ...VerifyOption) error {
+	var detachedPayload io.Reader
+	var keyProviders []KeyProvider
+	var keyUsed interface{}
+
+	understood := make(map[string]struct{})
+	for _, name := range defaultCriticalExtensions {
+		understood[name] = struct{}{}
+	}
+
+	ctx := context.Background()
+
+	//nolint:forcetypeassert
+	for _, option := range options {
+		switch option.Ident() {
+		case identDetachedPayloadReader{}:
+			detachedPayload = option.Value().(io.Reader)
+		case identKey{}:
+			pair := option.Value().(*withKey)
+			alg, ok := pair.alg.(jwa.SignatureAlgorithm)
+			if !ok {
+				return fmt.Errorf(`WithKey() option must be specified using jwa.SignatureAlgorithm (got %T)`, pair.alg)
+			}
+			keyProviders = append(keyProviders, &staticKeyProvider{
+				alg: alg,
+				key: pair.key,
+			})
+		case identKeyProvider{}:
+			keyProviders = append(keyProviders, option.Value().(KeyProvider))
+		case identKeyUsed{}:
+			keyUsed = option.Value()
+		case identContext{}:
+			ctx = option.Value().(context.Context)
+		case identCriticalExtensions{}:
+			for _, name := range option.Value().([]string) {
+				understood[name] = struct{}{}
+			}
+		default:
+			return fmt.Errorf(`invalid jws.VerifyOption %q passed to jws.VerifyStream`, `With`+strings.TrimPrefix(fmt.Sprintf(`%T`, option.Ident()), `jws.ident`))
+		}
+	}
+
+	if len(keyProviders) < 1 {
+		return fmt.Errorf(`jws.VerifyStream: no key providers have been provided (see jws.WithKey(), jws.WithKeySet(), jws.WithVerifyAuto(), and jws.WithKeyProvider()`)
+	}
+
+	if dst == nil {
+		dst = io.Discard
+	}
+
+	rdr := bufio.NewReader(src)
+
+	if detachedPayload != nil {
+		buf, err := io.ReadAll(rdr)
+		if err != nil {
+			return fmt.Errorf(`jws.VerifyStream: failed to read message: %w`, err)
+		}
+
+		msg, err := Parse(buf)
+		if err != nil {
+			return fmt.Errorf(`jws.VerifyStream: failed to parse jws: %w`, err)
+		}
+		defer msg.clearRaw()
+
+		if len(msg.payload) != 0 {
+			return fmt.Errorf(`jws.VerifyStream: can't specify detached payload for JWS with payload`)
+		}
+
+		var candidates []*streamCandidate
+		var critErr error
+		for i, sig := range msg.signatures {
+			if err := checkCritical(sig, understood); err != nil {
+				critErr = fmt.Errorf(`signature #%d: %w`, i+1, err)
+				continue
+			}
+
+			encoded, err := encodeProtectedHeader(sig)
+			if err != nil {
+				return fmt.Errorf(`jws.VerifyStream: failed to marshal "protected" for signature #%d: %w`, i+1, err)
+			}
+
+			list, err := fetchStreamCandidates(ctx, keyProviders, sig, msg, encoded+".")
+			if err != nil {
+				return fmt.Errorf(`jws.VerifyStream: %w`, err)
+			}
+			candidates = append(candidates, list...)
+		}
+
+		hashes := make([]io.Writer, len(candidates))
+		for i, c := range candidates {
+			hashes[i] = c.hash
+		}
+
+		w := io.MultiWriter(hashes...)
+		if msg.b64 {
+			enc := base64.NewEncoder(w)
+			if _, err := io.Copy(enc, detachedPayload); err != nil {
+				return fmt.Errorf(`jws.VerifyStream: failed to process payload: %w`, err)
+			}
+			if err := enc.Close(); err != nil {
+				return fmt.Errorf(`jws.VerifyStream: failed to process payload: %w`, err)
+			}
+		} else {
+			if _, err := io.Copy(w, detachedPayload); err != nil {
+				return fmt.Errorf(`jws.VerifyStream: failed to process payload: %w`, err)
+			}
+		}
+
+		if err := verifyStreamCandidates(candidates, keyUsed); err != nil {
+			if critErr != nil {
+				return fmt.Errorf(`jws.VerifyStream: %w: %s`, err, critErr)
+			}
+			return fmt.Errorf(`jws.VerifyStream: %w`, err)
+		}
+		return nil
+	}
+
+	var first rune
+	for {
+		r, _, err := rdr.ReadRune()
+		if err != nil {
+			return fmt.Errorf(`jws.VerifyStream: failed to read rune: %w`, err)
+		}
+		if !unicode.IsSpace(r) {
+			first = r
+			if err := rdr.UnreadRune(); err != nil {
+				return fmt.Errorf(`jws.VerifyStream: failed to unread rune: %w`, err)
+			}
+			break
+		}
+	}
+
+	if first == '{' {
+		buf, err := io.ReadAll(rdr)
+		if err != nil {
+			return fmt.Errorf(`jws.VerifyStream: failed to read message: %w`, err)
+		}
+
+		payload, err := Verify(buf, options...)
+		if err != nil {
+			return fmt.Errorf(`jws.VerifyStream: %w`, err)
+		}
+
+		if _, err := dst.Write(payload); err != nil {
+			return fmt.Errorf(`jws.VerifyStream: failed to write payload: %w`, err)
+		}
+		return nil
+	}
+
+	protected, err := rdr.ReadBytes('.')
+	if err != nil {
+		return fmt.Errorf(`jws.VerifyStream: invalid compact serialization format: invalid number of segments`)
+	}
+	protected = protected[:len(protected)-1]
+
+	decodedHeader, err := base64.Decode(protected)
+	if err != nil {
+		return fmt.Errorf(`jws.VerifyStream: failed to decode protected headers: %w`, err)
+	}
+
+	hdr := NewHeaders()
+	if err := json.Unmarshal(decodedHeader, hdr); err != nil {
+		return fmt.Errorf(`jws.VerifyStream: failed to parse JOSE headers: %w`, err)
+	}
+
+	sig := &Signature{protected: hdr}
+	msg := &Message{
+		signatures: []*Signature{sig},
+		b64:        getB64Value(hdr),
+	}
+
+	if err := checkCritical(sig, understood); err != nil {
+		return fmt.Errorf(`jws.VerifyStream: could not verify message using any of the signatures or keys: signature #1: %w`, err)
+	}
+
+	candidates, err := fetchStreamCandidates(ctx, keyProviders, sig, msg, string(protected)+".")
+	if err != nil {
+		return fmt.Errorf(`jws.VerifyStream: %w`, err)
+	}
+
+	hashes := make([]io.Writer, len(candidates))
+	for i, c := range candidates {
+		hashes[i] = c.hash
+	}
+
+	var payload io.Reader = io.TeeReader(&segmentReader{src: rdr}, io.MultiWriter(hashes...))
+	if msg.b64 {
+		payload = base64.NewDecoder(payload)
+	}
+	if _, err := io.Copy(dst, payload); err != nil {
+		return fmt.Errorf(`jws.VerifyStream: failed to process payload: %w`, err)
+	}
+
+	signature, err := io.ReadAll(rdr)
+	if err != nil {
+		return fmt.Errorf(`jws.VerifyStream: failed to read signature: %w`, err)
+	}
+	signature = bytes.TrimSpace(signature)
+	if bytes.IndexByte(signature, '.') >= 0 {
+		return fmt.Errorf(`jws.VerifyStream: invalid compact serialization format: invalid number of segments`)
+	}
+
+	decodedSignature, err := base64.Decode(signature)
+	if err != nil {
+		return fmt.Errorf(`jws.VerifyStream: failed to decode signature: %w`, err)
+	}
+	sig.signature = decodedSignature
+
+	if err := verifyStreamCandidates(candidates, keyUsed); err != nil {
+		return fmt.Errorf(`jws.VerifyStream: %w`, err)
+	}
+	return nil
+}
+
+// fetchStreamCandidates collects the keys from the key providers that
+// may be used to verify the signature, and prepares a hash for each of
+// them with `prefix` (the encoded protected header and a '.') written
+// to it.
+func fetchStreamCandidates(ctx context.Context, keyProviders []KeyProvider, sig *Signature, msg *Message, prefix string) ([]*streamCandidate, error) {
+	var candidates []*streamCandidate
+	for i, kp := range keyProviders {
+		var sink algKeySink
+		if err := kp.FetchKeys(ctx, &sink, sig, msg); err != nil {
+			return nil, fmt.Errorf(`key provider %d failed: %w`, i, err)
+		}
+
+		for _, pair := range sink.list {
+			//nolint:forcetypeassert
+			alg := pair.alg.(jwa.SignatureAlgorithm)
+			verifier, err := NewVerifier(alg)
+			if err != nil {
+				return nil, fmt.Errorf(`failed to create verifier for algorithm %q: %w`, alg, err)
+			}
+
+			hv, ok := verifier.(HashVerifier)
+			if !ok {
+				// This algorithm does not support streaming
+				continue
+			}
+
+			h, err := hv.NewHash(pair.key)
+			if err != nil {
+				continue
+			}
+
+			if _, err := io.WriteString(h, prefix); err != nil {
+				return nil, fmt.Errorf(`failed to write protected headers to hash: %w`, err)
+			}
+
+			candidates = append(candidates, &streamCandidate{
+				sig:      sig,
+				key:      pair.key,
+				verifier: hv,
+				hash:     h,
+			})
+		}
+	}
+	return candidates, nil
+}
+
+func verifyStreamCandidates(candidates []*streamCandidate, keyUsed interface{}) error {
+	for _, c := range candidates {
+		if err := c.verifier.VerifyHash(c.hash, c.sig.signature, c.key); err != nil {
+			continue
+		}
+
+		if keyUsed != nil {
+			if err := blackmagic.AssignIfCompatible(keyUsed, c.key); err != nil {
+				return fmt.Errorf(`failed to assign used key (%T) to %T: %w`, c.key, keyUsed, err)
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf(`could not verify message using any of the signatures or keys`)
+}
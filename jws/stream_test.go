@@ -0,0 +1,196 @@
+package jws_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStream(t *testing.T) {
+	rsakey, err := jwxtest.GenerateRsaKey()
+	require.NoError(t, err, `jwxtest.GenerateRsaKey should succeed`)
+	eckey, err := jwxtest.GenerateEcdsaKey(jwa.P256)
+	require.NoError(t, err, `jwxtest.GenerateEcdsaKey should succeed`)
+	octkey := jwxtest.GenerateSymmetricKey()
+
+	// large enough to span multiple reads
+	payload := make([]byte, 256*1024+3)
+	_, err = rand.Read(payload)
+	require.NoError(t, err, `rand.Read should succeed`)
+
+	keys := []struct {
+		Alg     jwa.SignatureAlgorithm
+		Private interface{}
+		Public  interface{}
+	}{
+		{Alg: jwa.RS256, Private: rsakey, Public: &rsakey.PublicKey},
+		{Alg: jwa.PS384, Private: rsakey, Public: &rsakey.PublicKey},
+		{Alg: jwa.ES256, Private: eckey, Public: &eckey.PublicKey},
+		{Alg: jwa.HS512, Private: octkey, Public: octkey},
+	}
+
+	for _, key := range keys {
+		key := key
+		t.Run(key.Alg.String(), func(t *testing.T) {
+			for _, format := range []struct {
+				Name    string
+				Options []jws.SignOption
+			}{
+				{Name: "compact"},
+				{Name: "JSON", Options: []jws.SignOption{jws.WithJSON()}},
+				{Name: "JSON (pretty)", Options: []jws.SignOption{jws.WithJSON(jws.WithPretty(true))}},
+			} {
+				format := format
+				t.Run(format.Name, func(t *testing.T) {
+					var signed bytes.Buffer
+					options := append([]jws.SignOption{jws.WithKey(key.Alg, key.Private)}, format.Options...)
+					require.NoError(t, jws.SignStream(&signed, bytes.NewReader(payload), options...), `jws.SignStream should succeed`)
+
+					// Should be verifiable using jws.Verify
+					verified, err := jws.Verify(signed.Bytes(), jws.WithKey(key.Alg, key.Public))
+					require.NoError(t, err, `jws.Verify should succeed`)
+					require.Equal(t, payload, verified, `payload should match`)
+
+					// ...as well as jws.VerifyStream
+					var dst bytes.Buffer
+					require.NoError(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), &dst, jws.WithKey(key.Alg, key.Public)), `jws.VerifyStream should succeed`)
+					require.Equal(t, payload, dst.Bytes(), `payload should match`)
+				})
+			}
+
+			t.Run("verify message created by jws.Sign", func(t *testing.T) {
+				signed, err := jws.Sign(payload, jws.WithKey(key.Alg, key.Private))
+				require.NoError(t, err, `jws.Sign should succeed`)
+
+				var dst bytes.Buffer
+				require.NoError(t, jws.VerifyStream(bytes.NewReader(signed), &dst, jws.WithKey(key.Alg, key.Public)), `jws.VerifyStream should succeed`)
+				require.Equal(t, payload, dst.Bytes(), `payload should match`)
+			})
+
+			t.Run("detached payload", func(t *testing.T) {
+				for _, b64 := range []bool{true, false} {
+					hdrs := jws.NewHeaders()
+					if !b64 {
+						require.NoError(t, hdrs.Set("b64", false), `hdrs.Set should succeed`)
+						require.NoError(t, hdrs.Set(jws.CriticalKey, []string{"b64"}), `hdrs.Set should succeed`)
+					}
+
+					var signed bytes.Buffer
+					require.NoError(t, jws.SignStream(&signed, nil,
+						jws.WithDetachedPayloadReader(bytes.NewReader(payload)),
+						jws.WithKey(key.Alg, key.Private, jws.WithProtectedHeaders(hdrs)),
+					), `jws.SignStream should succeed`)
+					require.Less(t, signed.Len(), 1024, `signed message should not contain the payload`)
+
+					_, err := jws.Verify(signed.Bytes(), jws.WithKey(key.Alg, key.Public), jws.WithDetachedPayload(payload))
+					require.NoError(t, err, `jws.Verify should succeed`)
+
+					require.NoError(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), nil,
+						jws.WithKey(key.Alg, key.Public),
+						jws.WithDetachedPayloadReader(bytes.NewReader(payload)),
+					), `jws.VerifyStream should succeed`)
+
+					tampered := append(append([]byte(nil), payload...), 'x')
+					require.Error(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), nil,
+						jws.WithKey(key.Alg, key.Public),
+						jws.WithDetachedPayloadReader(bytes.NewReader(tampered)),
+					), `jws.VerifyStream should fail`)
+				}
+			})
+		})
+	}
+
+	t.Run("multiple signatures", func(t *testing.T) {
+		var signed bytes.Buffer
+		require.NoError(t, jws.SignStream(&signed, bytes.NewReader(payload),
+			jws.WithJSON(),
+			jws.WithKey(jwa.RS256, rsakey),
+			jws.WithKey(jwa.ES256, eckey),
+		), `jws.SignStream should succeed`)
+
+		msg, err := jws.Parse(signed.Bytes())
+		require.NoError(t, err, `jws.Parse should succeed`)
+		require.Len(t, msg.Signatures(), 2, `there should be 2 signatures`)
+
+		for _, key := range []jws.VerifyOption{jws.WithKey(jwa.RS256, &rsakey.PublicKey), jws.WithKey(jwa.ES256, &eckey.PublicKey)} {
+			var dst bytes.Buffer
+			require.NoError(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), &dst, key), `jws.VerifyStream should succeed`)
+			require.Equal(t, payload, dst.Bytes(), `payload should match`)
+		}
+	})
+	t.Run("public headers", func(t *testing.T) {
+		public := jws.NewHeaders()
+		require.NoError(t, public.Set(jws.KeyIDKey, "my-key"), `public.Set should succeed`)
+
+		var signed bytes.Buffer
+		require.Error(t, jws.SignStream(&signed, bytes.NewReader(payload), jws.WithKey(jwa.RS256, rsakey, jws.WithPublicHeaders(public))), `jws.SignStream should fail for compact serialization`)
+
+		signed.Reset()
+		require.NoError(t, jws.SignStream(&signed, bytes.NewReader(payload), jws.WithJSON(), jws.WithKey(jwa.RS256, rsakey, jws.WithPublicHeaders(public))), `jws.SignStream should succeed`)
+
+		var m map[string]interface{}
+		require.NoError(t, json.Unmarshal(signed.Bytes(), &m), `json.Unmarshal should succeed`)
+		require.Equal(t, map[string]interface{}{"kid": "my-key"}, m["header"], `public header should match`)
+
+		_, err := jws.Verify(signed.Bytes(), jws.WithKey(jwa.RS256, &rsakey.PublicKey))
+		require.NoError(t, err, `jws.Verify should succeed`)
+	})
+	t.Run("unencoded payload", func(t *testing.T) {
+		hdrs := jws.NewHeaders()
+		require.NoError(t, hdrs.Set("b64", false), `hdrs.Set should succeed`)
+		require.NoError(t, hdrs.Set(jws.CriticalKey, []string{"b64"}), `hdrs.Set should succeed`)
+
+		const unencoded = `$Lorem ipsum dolor sit amet`
+		var signed bytes.Buffer
+		require.NoError(t, jws.SignStream(&signed, strings.NewReader(unencoded), jws.WithKey(jwa.HS256, octkey, jws.WithProtectedHeaders(hdrs))), `jws.SignStream should succeed`)
+		require.Contains(t, signed.String(), "."+unencoded+".", `payload should not be encoded`)
+
+		var dst bytes.Buffer
+		require.NoError(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), &dst, jws.WithKey(jwa.HS256, octkey)), `jws.VerifyStream should succeed`)
+		require.Equal(t, unencoded, dst.String(), `payload should match`)
+
+		signed.Reset()
+		require.Error(t, jws.SignStream(&signed, strings.NewReader(`foo.bar`), jws.WithKey(jwa.HS256, octkey, jws.WithProtectedHeaders(hdrs))), `jws.SignStream should fail for payloads containing "."`)
+		require.Error(t, jws.SignStream(&signed, strings.NewReader(unencoded), jws.WithJSON(), jws.WithKey(jwa.HS256, octkey, jws.WithProtectedHeaders(hdrs))), `jws.SignStream should fail for JSON serialization`)
+	})
+	t.Run("key used", func(t *testing.T) {
+		var signed bytes.Buffer
+		require.NoError(t, jws.SignStream(&signed, bytes.NewReader(payload), jws.WithKey(jwa.ES256, eckey)), `jws.SignStream should succeed`)
+
+		pubkey, err := jwk.FromRaw(&eckey.PublicKey)
+		require.NoError(t, err, `jwk.FromRaw should succeed`)
+		require.NoError(t, pubkey.Set(jwk.AlgorithmKey, jwa.ES256), `pubkey.Set should succeed`)
+		set := jwk.NewSet()
+		require.NoError(t, set.AddKey(pubkey), `set.AddKey should succeed`)
+
+		var used interface{}
+		require.NoError(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), nil, jws.WithKeySet(set, jws.WithRequireKid(false)), jws.WithKeyUsed(&used)), `jws.VerifyStream should succeed`)
+		require.Equal(t, pubkey, used, `key used should match`)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		var signed bytes.Buffer
+		require.NoError(t, jws.SignStream(&signed, bytes.NewReader(payload), jws.WithKey(jwa.HS256, octkey)), `jws.SignStream should succeed`)
+
+		require.Error(t, jws.VerifyStream(bytes.NewReader(signed.Bytes()), nil, jws.WithKey(jwa.HS256, []byte("wrong key"))), `jws.VerifyStream should fail`)
+
+		buf := signed.Bytes()
+		i := bytes.IndexByte(buf, '.')
+		buf[i+1] ^= 0x1
+		require.Error(t, jws.VerifyStream(bytes.NewReader(buf), nil, jws.WithKey(jwa.HS256, octkey)), `jws.VerifyStream should fail`)
+	})
+	t.Run("EdDSA", func(t *testing.T) {
+		edkey, err := jwxtest.GenerateEd25519Key()
+		require.NoError(t, err, `jwxtest.GenerateEd25519Key should succeed`)
+
+		var signed bytes.Buffer
+		require.Error(t, jws.SignStream(&signed, bytes.NewReader(payload), jws.WithKey(jwa.EdDSA, edkey)), `jws.SignStream should fail`)
+	})
+}
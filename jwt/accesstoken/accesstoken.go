@@ -0,0 +1,162 @@
+// Package accesstoken provides a specialized token that provides utilities
+// to work with OAuth 2.0 access tokens in the JWT profile defined in RFC9068.
+//
+// Use `accesstoken.Parse` to verify the "typ" header, the required claims,
+// and optionally the scopes of an access token:
+//
+//	tok, err := accesstoken.Parse(data,
+//	  jwt.WithKeySet(set),
+//	  jwt.WithIssuer(`https://as.example.com`),
+//	  jwt.WithAudience(`https://rs.example.com`),
+//	  jwt.WithRequiredScopes(`read`),
+//	)
+package accesstoken
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lestrrat-go/jwx/v2"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+)
+
+// MediaType is the value of the "typ" header of access tokens
+const MediaType = `at+jwt`
+
+var registry = json.NewRegistry()
+
+func (t *stdToken) Clone() (jwt.Token, error) {
+	var dst jwt.Token = New()
+
+	for _, pair := range t.makePairs() {
+		//nolint:forcetypeassert
+		key := pair.Key.(string)
+		if err := dst.Set(key, pair.Value); err != nil {
+			return nil, fmt.Errorf(`failed to set %s: %w`, key, err)
+		}
+	}
+	return dst, nil
+}
+
+// RegisterCustomField allows users to specify that a private field
+// be decoded as an instance of the specified type. This option has
+// a global effect.
+//
+// See `jwt.RegisterCustomField()` for details.
+func RegisterCustomField(name string, object interface{}) {
+	registry.Register(name, object)
+}
+
+// ParseString is the same as Parse, but takes a string instead of []byte
+func ParseString(s string, options ...jwt.ParseOption) (Token, error) {
+	return Parse([]byte(s), options...)
+}
+
+// Parse parses a signed access token in the JWT profile defined in
+// RFC9068. In addition to what `jwt.Parse()` does, the "typ" header
+// is checked to be "at+jwt" (or "application/at+jwt"), and the claims
+// that are required by RFC9068 are validated using the Validator
+// returned by `accesstoken.HasRequiredClaims()`.
+//
+// The options are passed to `jwt.Parse()`. Pass `jwt.WithRequiredScopes()`
+// to require the token to grant specific scopes.
+//
+// Encrypted access tokens are decrypted using the options specified
+// via `jwt.WithDecryptOption()`, and the "typ" header of the signed
+// token inside it is checked.
+//
+// Note that the "typ" header is checked even if `jwt.WithVerify(false)`
+// is specified, while the claims are not validated if `jwt.WithValidate(false)`
+// is specified.
+func Parse(src []byte, options ...jwt.ParseOption) (Token, error) {
+	if jwx.GuessFormat(src) == jwx.JWE {
+		var decryptOptions []jwe.DecryptOption
+		for _, option := range options {
+			if decryptOption, ok := option.Value().(jwe.DecryptOption); ok {
+				decryptOptions = append(decryptOptions, decryptOption)
+			}
+		}
+
+		decrypted, err := jwe.Decrypt(src, decryptOptions...)
+		if err != nil {
+			return nil, fmt.Errorf(`accesstoken.Parse: failed to decrypt JWE message: %w`, err)
+		}
+		src = decrypted
+	}
+
+	msg, err := jws.Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf(`accesstoken.Parse: failed to parse JWS message: %w`, err)
+	}
+
+	if err := checkType(msg); err != nil {
+		return nil, fmt.Errorf(`accesstoken.Parse: %w`, err)
+	}
+
+	options = append(append([]jwt.ParseOption{jwt.WithToken(New())}, options...), jwt.WithValidator(HasRequiredClaims()))
+	tok, err := jwt.Parse(src, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	at, ok := tok.(Token)
+	if !ok {
+		return nil, fmt.Errorf(`accesstoken.Parse: expected accesstoken.Token, got %T`, tok)
+	}
+	return at, nil
+}
+
+func checkType(msg *jws.Message) error {
+	sigs := msg.Signatures()
+	if len(sigs) == 0 {
+		return fmt.Errorf(`no signatures found`)
+	}
+
+	for _, sig := range sigs {
+		if !IsMediaType(sig.ProtectedHeaders().Type()) {
+			return fmt.Errorf(`invalid "typ" header (expected %q)`, MediaType)
+		}
+	}
+	return nil
+}
+
+// IsMediaType returns true if `typ` identifies an access token in the
+// JWT profile, that is, if it is "at+jwt" or "application/at+jwt"
+// (compared case-insensitively).
+func IsMediaType(typ string) bool {
+	typ = strings.ToLower(typ)
+	return typ == MediaType || typ == `application/`+MediaType
+}
+
+var requiredClaims = []string{
+	IssuerKey,
+	ExpirationKey,
+	AudienceKey,
+	SubjectKey,
+	ClientIDKey,
+	IssuedAtKey,
+	JwtIDKey,
+}
+
+// HasRequiredClaims creates a Validator that checks that the token
+// contains all of the claims required by RFC9068: "iss", "exp", "aud",
+// "sub", "client_id", "iat", and "jti".
+//
+// The errors returned for missing claims can be identified using
+// `jwt.ErrRequiredClaim()`.
+func HasRequiredClaims() jwt.Validator {
+	return jwt.ValidatorFunc(hasRequiredClaims)
+}
+
+func hasRequiredClaims(ctx context.Context, t jwt.Token) jwt.ValidationError {
+	for _, name := range requiredClaims {
+		if err := jwt.IsRequired(name).Validate(ctx, t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
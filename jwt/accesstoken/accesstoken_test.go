@@ -0,0 +1,119 @@
+package accesstoken_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwe"
+	"github.com/lestrrat-go/jwx/v2/jws"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/lestrrat-go/jwx/v2/jwt/accesstoken"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	key, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+
+	now := time.Now().Truncate(time.Second).UTC()
+	newBuilder := func() *accesstoken.Builder {
+		return accesstoken.NewBuilder().
+			Issuer(`https://as.example.com`).
+			Expiration(now.Add(time.Hour)).
+			Audience([]string{`https://rs.example.com`}).
+			Subject(`5ba552d67`).
+			ClientID(`s6BhdRkqt3`).
+			IssuedAt(now).
+			JwtID(`dbe39bf3a3ba4238a513f51d6e1691c4`).
+			Scope(`openid profile reademail`).
+			AuthTime(now.Add(-time.Minute)).
+			Acr(`urn:mace:incommon:iap:silver`).
+			Amr([]string{`pwd`, `mfa`}).
+			Groups([]string{`admin`}).
+			Roles([]string{`reader`, `writer`}).
+			Entitlements([]string{`premium`})
+	}
+	sign := func(t *testing.T, tok jwt.Token, typ string) []byte {
+		t.Helper()
+		hdrs := jws.NewHeaders()
+		if typ != "" {
+			require.NoError(t, hdrs.Set(jws.TypeKey, typ), `hdrs.Set should succeed`)
+		}
+		signed, err := jwt.Sign(tok, jwt.WithKey(jwa.HS256, key, jws.WithProtectedHeaders(hdrs)))
+		require.NoError(t, err, `jwt.Sign should succeed`)
+		return signed
+	}
+
+	tok, err := newBuilder().Build()
+	require.NoError(t, err, `builder.Build should succeed`)
+
+	t.Run("Valid token", func(t *testing.T) {
+		for _, typ := range []string{`at+jwt`, `application/at+jwt`, `AT+JWT`} {
+			parsed, err := accesstoken.Parse(sign(t, tok, typ), jwt.WithKey(jwa.HS256, key), jwt.WithRequiredScopes(`profile`))
+			require.NoError(t, err, `accesstoken.Parse should succeed (typ = %q)`, typ)
+			require.Equal(t, `s6BhdRkqt3`, parsed.ClientID())
+			require.Equal(t, `openid profile reademail`, parsed.Scope())
+			require.Equal(t, now.Add(-time.Minute), parsed.AuthTime())
+			require.Equal(t, `urn:mace:incommon:iap:silver`, parsed.Acr())
+			require.Equal(t, []string{`pwd`, `mfa`}, parsed.Amr())
+			require.Equal(t, []string{`admin`}, parsed.Groups())
+			require.Equal(t, []string{`reader`, `writer`}, parsed.Roles())
+			require.Equal(t, []string{`premium`}, parsed.Entitlements())
+		}
+	})
+	t.Run("Invalid typ", func(t *testing.T) {
+		for _, typ := range []string{``, `JWT`, `application/jwt`} {
+			_, err := accesstoken.Parse(sign(t, tok, typ), jwt.WithKey(jwa.HS256, key))
+			require.Error(t, err, `accesstoken.Parse should fail (typ = %q)`, typ)
+		}
+	})
+	t.Run("Insufficient scope", func(t *testing.T) {
+		_, err := accesstoken.Parse(sign(t, tok, `at+jwt`), jwt.WithKey(jwa.HS256, key), jwt.WithRequiredScopes(`writeemail`))
+		require.True(t, errors.Is(err, jwt.ErrInsufficientScope()), `error should be ErrInsufficientScope`)
+	})
+	t.Run("Missing required claim", func(t *testing.T) {
+		for _, name := range []string{accesstoken.ClientIDKey, accesstoken.JwtIDKey, accesstoken.AudienceKey} {
+			incomplete, err := newBuilder().Build()
+			require.NoError(t, err, `builder.Build should succeed`)
+			require.NoError(t, incomplete.Remove(name), `incomplete.Remove should succeed`)
+
+			_, err = accesstoken.Parse(sign(t, incomplete, `at+jwt`), jwt.WithKey(jwa.HS256, key))
+			require.True(t, errors.Is(err, jwt.ErrRequiredClaim()), `error should be ErrRequiredClaim (missing %q)`, name)
+		}
+	})
+	t.Run("Encrypted token", func(t *testing.T) {
+		encKey := []byte(`0123456789abcdef`)
+		for _, typ := range []string{`at+jwt`, `JWT`} {
+			encrypted, err := jwe.Encrypt(sign(t, tok, typ), jwe.WithKey(jwa.A128KW, encKey))
+			require.NoError(t, err, `jwe.Encrypt should succeed`)
+
+			parsed, err := accesstoken.Parse(encrypted, jwt.WithDecryptOption(jwe.WithKey(jwa.A128KW, encKey)), jwt.WithKey(jwa.HS256, key))
+			if typ != `at+jwt` {
+				require.Error(t, err, `accesstoken.Parse should fail (typ = %q)`, typ)
+				continue
+			}
+			require.NoError(t, err, `accesstoken.Parse should succeed`)
+			require.Equal(t, `s6BhdRkqt3`, parsed.ClientID())
+
+			_, err = accesstoken.Parse(encrypted, jwt.WithKey(jwa.HS256, key))
+			require.Error(t, err, `accesstoken.Parse should fail without jwt.WithDecryptOption`)
+		}
+	})
+	t.Run("JSON round trip", func(t *testing.T) {
+		buf, err := json.Marshal(tok)
+		require.NoError(t, err, `json.Marshal should succeed`)
+
+		decoded := accesstoken.New()
+		require.NoError(t, json.Unmarshal(buf, decoded), `json.Unmarshal should succeed`)
+		require.Equal(t, tok.Roles(), decoded.Roles())
+		require.Equal(t, tok.AuthTime(), decoded.AuthTime())
+
+		cloned, err := tok.Clone()
+		require.NoError(t, err, `tok.Clone should succeed`)
+		require.Equal(t, tok.ClientID(), cloned.(accesstoken.Token).ClientID())
+	})
+}
@@ -0,0 +1,839 @@
+// Code generated by tools/cmd/genjwt/main.go. DO NOT EDIT.
+
+package accesstoken
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/iter/mapiter"
+	"github.com/lestrrat-go/jwx/v2/internal/base64"
+	"github.com/lestrrat-go/jwx/v2/internal/iter"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/internal/pool"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/lestrrat-go/jwx/v2/jwt/internal/types"
+)
+
+const (
+	AcrKey          = "acr"
+	AmrKey          = "amr"
+	AudienceKey     = "aud"
+	AuthTimeKey     = "auth_time"
+	ClientIDKey     = "client_id"
+	EntitlementsKey = "entitlements"
+	ExpirationKey   = "exp"
+	GroupsKey       = "groups"
+	IssuedAtKey     = "iat"
+	IssuerKey       = "iss"
+	JwtIDKey        = "jti"
+	NotBeforeKey    = "nbf"
+	RolesKey        = "roles"
+	ScopeKey        = "scope"
+	SubjectKey      = "sub"
+)
+
+type Token interface {
+
+	// Acr returns the value for "acr" field of the token
+	Acr() string
+
+	// Amr returns the value for "amr" field of the token
+	Amr() []string
+
+	// Audience returns the value for "aud" field of the token
+	Audience() []string
+
+	// AuthTime returns the value for "auth_time" field of the token
+	AuthTime() time.Time
+
+	// ClientID returns the value for "client_id" field of the token
+	ClientID() string
+
+	// Entitlements returns the value for "entitlements" field of the token
+	Entitlements() []string
+
+	// Expiration returns the value for "exp" field of the token
+	Expiration() time.Time
+
+	// Groups returns the value for "groups" field of the token
+	Groups() []string
+
+	// IssuedAt returns the value for "iat" field of the token
+	IssuedAt() time.Time
+
+	// Issuer returns the value for "iss" field of the token
+	Issuer() string
+
+	// JwtID returns the value for "jti" field of the token
+	JwtID() string
+
+	// NotBefore returns the value for "nbf" field of the token
+	NotBefore() time.Time
+
+	// Roles returns the value for "roles" field of the token
+	Roles() []string
+
+	// Scope returns the value for "scope" field of the token
+	Scope() string
+
+	// Subject returns the value for "sub" field of the token
+	Subject() string
+
+	// PrivateClaims return the entire set of fields (claims) in the token
+	// *other* than the pre-defined fields such as `iss`, `nbf`, `iat`, etc.
+	PrivateClaims() map[string]interface{}
+
+	// Get returns the value of the corresponding field in the token, such as
+	// `nbf`, `exp`, `iat`, and other user-defined fields. If the field does not
+	// exist in the token, the second return value will be `false`
+	//
+	// If you need to access fields like `alg`, `kid`, `jku`, etc, you need
+	// to access the corresponding fields in the JWS/JWE message. For this,
+	// you will need to access them by directly parsing the payload using
+	// `jws.Parse` and `jwe.Parse`
+	Get(string) (interface{}, bool)
+
+	// Set assigns a value to the corresponding field in the token. Some
+	// pre-defined fields such as `nbf`, `iat`, `iss` need their values to
+	// be of a specific type. See the other getter methods in this interface
+	// for the types of each of these fields
+	Set(string, interface{}) error
+	Remove(string) error
+
+	// Options returns the per-token options associated with this token.
+	// The options set value will be copied when the token is cloned via `Clone()`
+	// but it will not survive when the token goes through marshaling/unmarshaling
+	// such as `json.Marshal` and `json.Unmarshal`
+	Options() *jwt.TokenOptionSet
+	Clone() (jwt.Token, error)
+	Iterate(context.Context) Iterator
+	Walk(context.Context, Visitor) error
+	AsMap(context.Context) (map[string]interface{}, error)
+}
+type stdToken struct {
+	mu            *sync.RWMutex
+	dc            DecodeCtx          // per-object context for decoding
+	options       jwt.TokenOptionSet // per-object option
+	acr           *string            // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.1
+	amr           types.StringList   // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.1
+	audience      types.StringList   // https://tools.ietf.org/html/rfc7519#section-4.1.3
+	authTime      *types.NumericDate // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.1
+	clientID      *string            // https://www.rfc-editor.org/rfc/rfc9068#section-2.2
+	entitlements  types.StringList   // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3.1
+	expiration    *types.NumericDate // https://tools.ietf.org/html/rfc7519#section-4.1.4
+	groups        types.StringList   // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3.1
+	issuedAt      *types.NumericDate // https://tools.ietf.org/html/rfc7519#section-4.1.6
+	issuer        *string            // https://tools.ietf.org/html/rfc7519#section-4.1.1
+	jwtID         *string            // https://tools.ietf.org/html/rfc7519#section-4.1.7
+	notBefore     *types.NumericDate // https://tools.ietf.org/html/rfc7519#section-4.1.5
+	roles         types.StringList   // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3.1
+	scope         *string            // https://www.rfc-editor.org/rfc/rfc9068#section-2.2.3
+	subject       *string            // https://tools.ietf.org/html/rfc7519#section-4.1.2
+	privateClaims map[string]interface{}
+}
+
+// New creates a standard token, with minimal knowledge of
+// possible claims. Standard claims include"acr", "amr", "aud", "auth_time", "client_id", "entitlements", "exp", "groups", "iat", "iss", "jti", "nbf", "roles", "scope" and "sub".
+// Convenience accessors are provided for these standard claims
+func New() Token {
+	return &stdToken{
+		mu:            &sync.RWMutex{},
+		privateClaims: make(map[string]interface{}),
+		options:       jwt.DefaultOptionSet(),
+	}
+}
+
+func (t *stdToken) Options() *jwt.TokenOptionSet {
+	return &t.options
+}
+
+func (t *stdToken) Get(name string) (interface{}, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	switch name {
+	case AcrKey:
+		if t.acr == nil {
+			return nil, false
+		}
+		v := *(t.acr)
+		return v, true
+	case AmrKey:
+		if t.amr == nil {
+			return nil, false
+		}
+		v := t.amr.Get()
+		return v, true
+	case AudienceKey:
+		if t.audience == nil {
+			return nil, false
+		}
+		v := t.audience.Get()
+		return v, true
+	case AuthTimeKey:
+		if t.authTime == nil {
+			return nil, false
+		}
+		v := t.authTime.Get()
+		return v, true
+	case ClientIDKey:
+		if t.clientID == nil {
+			return nil, false
+		}
+		v := *(t.clientID)
+		return v, true
+	case EntitlementsKey:
+		if t.entitlements == nil {
+			return nil, false
+		}
+		v := t.entitlements.Get()
+		return v, true
+	case ExpirationKey:
+		if t.expiration == nil {
+			return nil, false
+		}
+		v := t.expiration.Get()
+		return v, true
+	case GroupsKey:
+		if t.groups == nil {
+			return nil, false
+		}
+		v := t.groups.Get()
+		return v, true
+	case IssuedAtKey:
+		if t.issuedAt == nil {
+			return nil, false
+		}
+		v := t.issuedAt.Get()
+		return v, true
+	case IssuerKey:
+		if t.issuer == nil {
+			return nil, false
+		}
+		v := *(t.issuer)
+		return v, true
+	case JwtIDKey:
+		if t.jwtID == nil {
+			return nil, false
+		}
+		v := *(t.jwtID)
+		return v, true
+	case NotBeforeKey:
+		if t.notBefore == nil {
+			return nil, false
+		}
+		v := t.notBefore.Get()
+		return v, true
+	case RolesKey:
+		if t.roles == nil {
+			return nil, false
+		}
+		v := t.roles.Get()
+		return v, true
+	case ScopeKey:
+		if t.scope == nil {
+			return nil, false
+		}
+		v := *(t.scope)
+		return v, true
+	case SubjectKey:
+		if t.subject == nil {
+			return nil, false
+		}
+		v := *(t.subject)
+		return v, true
+	default:
+		v, ok := t.privateClaims[name]
+		return v, ok
+	}
+}
+
+func (t *stdToken) Remove(key string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch key {
+	case AcrKey:
+		t.acr = nil
+	case AmrKey:
+		t.amr = nil
+	case AudienceKey:
+		t.audience = nil
+	case AuthTimeKey:
+		t.authTime = nil
+	case ClientIDKey:
+		t.clientID = nil
+	case EntitlementsKey:
+		t.entitlements = nil
+	case ExpirationKey:
+		t.expiration = nil
+	case GroupsKey:
+		t.groups = nil
+	case IssuedAtKey:
+		t.issuedAt = nil
+	case IssuerKey:
+		t.issuer = nil
+	case JwtIDKey:
+		t.jwtID = nil
+	case NotBeforeKey:
+		t.notBefore = nil
+	case RolesKey:
+		t.roles = nil
+	case ScopeKey:
+		t.scope = nil
+	case SubjectKey:
+		t.subject = nil
+	default:
+		delete(t.privateClaims, key)
+	}
+	return nil
+}
+
+func (t *stdToken) Set(name string, value interface{}) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.setNoLock(name, value)
+}
+
+func (t *stdToken) DecodeCtx() DecodeCtx {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.dc
+}
+
+func (t *stdToken) SetDecodeCtx(v DecodeCtx) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.dc = v
+}
+
+func (t *stdToken) setNoLock(name string, value interface{}) error {
+	switch name {
+	case AcrKey:
+		if v, ok := value.(string); ok {
+			t.acr = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, AcrKey, value)
+	case AmrKey:
+		var acceptor types.StringList
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, AmrKey, err)
+		}
+		t.amr = acceptor
+		return nil
+	case AudienceKey:
+		var acceptor types.StringList
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, AudienceKey, err)
+		}
+		t.audience = acceptor
+		return nil
+	case AuthTimeKey:
+		var acceptor types.NumericDate
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, AuthTimeKey, err)
+		}
+		t.authTime = &acceptor
+		return nil
+	case ClientIDKey:
+		if v, ok := value.(string); ok {
+			t.clientID = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, ClientIDKey, value)
+	case EntitlementsKey:
+		var acceptor types.StringList
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, EntitlementsKey, err)
+		}
+		t.entitlements = acceptor
+		return nil
+	case ExpirationKey:
+		var acceptor types.NumericDate
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, ExpirationKey, err)
+		}
+		t.expiration = &acceptor
+		return nil
+	case GroupsKey:
+		var acceptor types.StringList
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, GroupsKey, err)
+		}
+		t.groups = acceptor
+		return nil
+	case IssuedAtKey:
+		var acceptor types.NumericDate
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, IssuedAtKey, err)
+		}
+		t.issuedAt = &acceptor
+		return nil
+	case IssuerKey:
+		if v, ok := value.(string); ok {
+			t.issuer = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, IssuerKey, value)
+	case JwtIDKey:
+		if v, ok := value.(string); ok {
+			t.jwtID = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, JwtIDKey, value)
+	case NotBeforeKey:
+		var acceptor types.NumericDate
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, NotBeforeKey, err)
+		}
+		t.notBefore = &acceptor
+		return nil
+	case RolesKey:
+		var acceptor types.StringList
+		if err := acceptor.Accept(value); err != nil {
+			return fmt.Errorf(`invalid value for %s key: %w`, RolesKey, err)
+		}
+		t.roles = acceptor
+		return nil
+	case ScopeKey:
+		if v, ok := value.(string); ok {
+			t.scope = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, ScopeKey, value)
+	case SubjectKey:
+		if v, ok := value.(string); ok {
+			t.subject = &v
+			return nil
+		}
+		return fmt.Errorf(`invalid value for %s key: %T`, SubjectKey, value)
+	default:
+		if t.privateClaims == nil {
+			t.privateClaims = map[string]interface{}{}
+		}
+		t.privateClaims[name] = value
+	}
+	return nil
+}
+
+func (t *stdToken) Acr() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.acr != nil {
+		return *(t.acr)
+	}
+	return ""
+}
+
+func (t *stdToken) Amr() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.amr != nil {
+		return t.amr.Get()
+	}
+	return nil
+}
+
+func (t *stdToken) Audience() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.audience != nil {
+		return t.audience.Get()
+	}
+	return nil
+}
+
+func (t *stdToken) AuthTime() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.authTime != nil {
+		return t.authTime.Get()
+	}
+	return time.Time{}
+}
+
+func (t *stdToken) ClientID() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.clientID != nil {
+		return *(t.clientID)
+	}
+	return ""
+}
+
+func (t *stdToken) Entitlements() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.entitlements != nil {
+		return t.entitlements.Get()
+	}
+	return nil
+}
+
+func (t *stdToken) Expiration() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.expiration != nil {
+		return t.expiration.Get()
+	}
+	return time.Time{}
+}
+
+func (t *stdToken) Groups() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.groups != nil {
+		return t.groups.Get()
+	}
+	return nil
+}
+
+func (t *stdToken) IssuedAt() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.issuedAt != nil {
+		return t.issuedAt.Get()
+	}
+	return time.Time{}
+}
+
+func (t *stdToken) Issuer() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.issuer != nil {
+		return *(t.issuer)
+	}
+	return ""
+}
+
+func (t *stdToken) JwtID() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.jwtID != nil {
+		return *(t.jwtID)
+	}
+	return ""
+}
+
+func (t *stdToken) NotBefore() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.notBefore != nil {
+		return t.notBefore.Get()
+	}
+	return time.Time{}
+}
+
+func (t *stdToken) Roles() []string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.roles != nil {
+		return t.roles.Get()
+	}
+	return nil
+}
+
+func (t *stdToken) Scope() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.scope != nil {
+		return *(t.scope)
+	}
+	return ""
+}
+
+func (t *stdToken) Subject() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.subject != nil {
+		return *(t.subject)
+	}
+	return ""
+}
+
+func (t *stdToken) PrivateClaims() map[string]interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.privateClaims
+}
+
+func (t *stdToken) makePairs() []*ClaimPair {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	pairs := make([]*ClaimPair, 0, 15)
+	if t.acr != nil {
+		v := *(t.acr)
+		pairs = append(pairs, &ClaimPair{Key: AcrKey, Value: v})
+	}
+	if t.amr != nil {
+		v := t.amr.Get()
+		pairs = append(pairs, &ClaimPair{Key: AmrKey, Value: v})
+	}
+	if t.audience != nil {
+		v := t.audience.Get()
+		pairs = append(pairs, &ClaimPair{Key: AudienceKey, Value: v})
+	}
+	if t.authTime != nil {
+		v := t.authTime.Get()
+		pairs = append(pairs, &ClaimPair{Key: AuthTimeKey, Value: v})
+	}
+	if t.clientID != nil {
+		v := *(t.clientID)
+		pairs = append(pairs, &ClaimPair{Key: ClientIDKey, Value: v})
+	}
+	if t.entitlements != nil {
+		v := t.entitlements.Get()
+		pairs = append(pairs, &ClaimPair{Key: EntitlementsKey, Value: v})
+	}
+	if t.expiration != nil {
+		v := t.expiration.Get()
+		pairs = append(pairs, &ClaimPair{Key: ExpirationKey, Value: v})
+	}
+	if t.groups != nil {
+		v := t.groups.Get()
+		pairs = append(pairs, &ClaimPair{Key: GroupsKey, Value: v})
+	}
+	if t.issuedAt != nil {
+		v := t.issuedAt.Get()
+		pairs = append(pairs, &ClaimPair{Key: IssuedAtKey, Value: v})
+	}
+	if t.issuer != nil {
+		v := *(t.issuer)
+		pairs = append(pairs, &ClaimPair{Key: IssuerKey, Value: v})
+	}
+	if t.jwtID != nil {
+		v := *(t.jwtID)
+		pairs = append(pairs, &ClaimPair{Key: JwtIDKey, Value: v})
+	}
+	if t.notBefore != nil {
+		v := t.notBefore.Get()
+		pairs = append(pairs, &ClaimPair{Key: NotBeforeKey, Value: v})
+	}
+	if t.roles != nil {
+		v := t.roles.Get()
+		pairs = append(pairs, &ClaimPair{Key: RolesKey, Value: v})
+	}
+	if t.scope != nil {
+		v := *(t.scope)
+		pairs = append(pairs, &ClaimPair{Key: ScopeKey, Value: v})
+	}
+	if t.subject != nil {
+		v := *(t.subject)
+		pairs = append(pairs, &ClaimPair{Key: SubjectKey, Value: v})
+	}
+	for k, v := range t.privateClaims {
+		pairs = append(pairs, &ClaimPair{Key: k, Value: v})
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i].Key.(string) < pairs[j].Key.(string)
+	})
+	return pairs
+}
+
+func (t *stdToken) UnmarshalJSON(buf []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.acr = nil
+	t.amr = nil
+	t.audience = nil
+	t.authTime = nil
+	t.clientID = nil
+	t.entitlements = nil
+	t.expiration = nil
+	t.groups = nil
+	t.issuedAt = nil
+	t.issuer = nil
+	t.jwtID = nil
+	t.notBefore = nil
+	t.roles = nil
+	t.scope = nil
+	t.subject = nil
+	dec := json.NewDecoder(bytes.NewReader(buf))
+LOOP:
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf(`error reading token: %w`, err)
+		}
+		switch tok := tok.(type) {
+		case json.Delim:
+			// Assuming we're doing everything correctly, we should ONLY
+			// get either '{' or '}' here.
+			if tok == '}' { // End of object
+				break LOOP
+			} else if tok != '{' {
+				return fmt.Errorf(`expected '{', but got '%c'`, tok)
+			}
+		case string: // Objects can only have string keys
+			switch tok {
+			case AcrKey:
+				if err := json.AssignNextStringToken(&t.acr, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, AcrKey, err)
+				}
+			case AmrKey:
+				var decoded types.StringList
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, AmrKey, err)
+				}
+				t.amr = decoded
+			case AudienceKey:
+				var decoded types.StringList
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, AudienceKey, err)
+				}
+				t.audience = decoded
+			case AuthTimeKey:
+				var decoded types.NumericDate
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, AuthTimeKey, err)
+				}
+				t.authTime = &decoded
+			case ClientIDKey:
+				if err := json.AssignNextStringToken(&t.clientID, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, ClientIDKey, err)
+				}
+			case EntitlementsKey:
+				var decoded types.StringList
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, EntitlementsKey, err)
+				}
+				t.entitlements = decoded
+			case ExpirationKey:
+				var decoded types.NumericDate
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, ExpirationKey, err)
+				}
+				t.expiration = &decoded
+			case GroupsKey:
+				var decoded types.StringList
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, GroupsKey, err)
+				}
+				t.groups = decoded
+			case IssuedAtKey:
+				var decoded types.NumericDate
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, IssuedAtKey, err)
+				}
+				t.issuedAt = &decoded
+			case IssuerKey:
+				if err := json.AssignNextStringToken(&t.issuer, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, IssuerKey, err)
+				}
+			case JwtIDKey:
+				if err := json.AssignNextStringToken(&t.jwtID, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, JwtIDKey, err)
+				}
+			case NotBeforeKey:
+				var decoded types.NumericDate
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, NotBeforeKey, err)
+				}
+				t.notBefore = &decoded
+			case RolesKey:
+				var decoded types.StringList
+				if err := dec.Decode(&decoded); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, RolesKey, err)
+				}
+				t.roles = decoded
+			case ScopeKey:
+				if err := json.AssignNextStringToken(&t.scope, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, ScopeKey, err)
+				}
+			case SubjectKey:
+				if err := json.AssignNextStringToken(&t.subject, dec); err != nil {
+					return fmt.Errorf(`failed to decode value for key %s: %w`, SubjectKey, err)
+				}
+			default:
+				if dc := t.dc; dc != nil {
+					if localReg := dc.Registry(); localReg != nil {
+						decoded, err := localReg.Decode(dec, tok)
+						if err == nil {
+							t.setNoLock(tok, decoded)
+							continue
+						}
+					}
+				}
+				decoded, err := registry.Decode(dec, tok)
+				if err == nil {
+					t.setNoLock(tok, decoded)
+					continue
+				}
+				return fmt.Errorf(`could not decode field %s: %w`, tok, err)
+			}
+		default:
+			return fmt.Errorf(`invalid token %T`, tok)
+		}
+	}
+	return nil
+}
+
+func (t stdToken) MarshalJSON() ([]byte, error) {
+	buf := pool.GetBytesBuffer()
+	defer pool.ReleaseBytesBuffer(buf)
+	buf.WriteByte('{')
+	enc := json.NewEncoder(buf)
+	for i, pair := range t.makePairs() {
+		f := pair.Key.(string)
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteRune('"')
+		buf.WriteString(f)
+		buf.WriteString(`":`)
+		switch f {
+		case AudienceKey:
+			if err := json.EncodeAudience(enc, pair.Value.([]string), t.options.IsEnabled(jwt.FlattenAudience)); err != nil {
+				return nil, fmt.Errorf(`failed to encode "aud": %w`, err)
+			}
+			continue
+		case AuthTimeKey, ExpirationKey, IssuedAtKey, NotBeforeKey:
+			enc.Encode(pair.Value.(time.Time).Unix())
+			continue
+		}
+		switch v := pair.Value.(type) {
+		case []byte:
+			buf.WriteRune('"')
+			buf.WriteString(base64.EncodeToString(v))
+			buf.WriteRune('"')
+		default:
+			if err := enc.Encode(v); err != nil {
+				return nil, fmt.Errorf(`failed to marshal field %s: %w`, f, err)
+			}
+			buf.Truncate(buf.Len() - 1)
+		}
+	}
+	buf.WriteByte('}')
+	ret := make([]byte, buf.Len())
+	copy(ret, buf.Bytes())
+	return ret, nil
+}
+
+func (t *stdToken) Iterate(ctx context.Context) Iterator {
+	pairs := t.makePairs()
+	ch := make(chan *ClaimPair, len(pairs))
+	go func(ctx context.Context, ch chan *ClaimPair, pairs []*ClaimPair) {
+		defer close(ch)
+		for _, pair := range pairs {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- pair:
+			}
+		}
+	}(ctx, ch, pairs)
+	return mapiter.New(ch)
+}
+
+func (t *stdToken) Walk(ctx context.Context, visitor Visitor) error {
+	return iter.WalkMap(ctx, t, visitor)
+}
+
+func (t *stdToken) AsMap(ctx context.Context) (map[string]interface{}, error) {
+	return iter.AsMap(ctx, t)
+}
@@ -0,0 +1,106 @@
+package jwt
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwt/internal/types"
+)
+
+// StandardClaims contains the registered claims defined in RFC7519.
+// It is meant to be embedded in user-defined structs that are used with
+// `jwt.ParseInto()`, `jwt.UnmarshalClaims()`, and `jwt.NewFromStruct()`.
+type StandardClaims struct {
+	Audience   Audience     `json:"aud,omitempty"`
+	Expiration *NumericDate `json:"exp,omitempty"`
+	IssuedAt   *NumericDate `json:"iat,omitempty"`
+	Issuer     string       `json:"iss,omitempty"`
+	JwtID      string       `json:"jti,omitempty"`
+	NotBefore  *NumericDate `json:"nbf,omitempty"`
+	Subject    string       `json:"sub,omitempty"`
+}
+
+// Audience represents the "aud" claim. When decoding, both a single
+// string and a list of strings are accepted. It is always encoded
+// as a list of strings.
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var list types.StringList
+	if err := list.UnmarshalJSON(data); err != nil {
+		return fmt.Errorf(`failed to unmarshal "aud": %w`, err)
+	}
+	*a = Audience(list)
+	return nil
+}
+
+// NumericDate represents the date format used in the "exp", "iat",
+// and "nbf" claims. When decoding, the same formats as `jwt.Parse()`
+// are accepted. It is encoded as the number of seconds since the epoch.
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate creates a new NumericDate from `t`.
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{Time: t.UTC()}
+}
+
+func (n NumericDate) MarshalJSON() ([]byte, error) {
+	if n.IsZero() {
+		return json.Marshal(nil)
+	}
+	return json.Marshal(n.Unix())
+}
+
+func (n *NumericDate) UnmarshalJSON(data []byte) error {
+	var v types.NumericDate
+	if err := v.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	n.Time = v.Time
+	return nil
+}
+
+// UnmarshalClaims decodes the claims in `t` into `dst`, which is typically
+// a pointer to a struct with `json` tags (possibly embedding
+// `jwt.StandardClaims`). Use this instead of `jwt.ParseInto()` when you
+// already have a `jwt.Token`, such as one retrieved using
+// `jwt.TokenFromContext()`.
+//
+// `t` may be any implementation of `jwt.Token`, including `openid.Token`.
+// It is first encoded into JSON, and then decoded into `dst`.
+func UnmarshalClaims(t Token, dst interface{}) error {
+	buf, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf(`failed to marshal token: %w`, err)
+	}
+	if err := json.Unmarshal(buf, dst); err != nil {
+		return fmt.Errorf(`failed to unmarshal claims: %w`, err)
+	}
+	return nil
+}
+
+// NewFromStruct creates a new `jwt.Token` from `v`, which is typically
+// a struct with `json` tags (possibly embedding `jwt.StandardClaims`).
+// This is the reverse of `jwt.ParseInto()`: the resulting token can be
+// signed using `jwt.Sign()`.
+//
+// `v` is first encoded into JSON, and then decoded into the token,
+// so the claims are subject to the same rules as `jwt.Parse()`.
+// For example, "exp" must be a valid NumericDate, and private claims
+// are decoded as generic JSON values unless registered using
+// `jwt.RegisterCustomField()`.
+func NewFromStruct(v interface{}) (Token, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to marshal %T: %w`, v, err)
+	}
+
+	t := New()
+	if err := json.Unmarshal(buf, t); err != nil {
+		return nil, fmt.Errorf(`failed to create token from %T: %w`, v, err)
+	}
+	return t, nil
+}
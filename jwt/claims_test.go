@@ -0,0 +1,79 @@
+package jwt_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+type testClaims struct {
+	jwt.StandardClaims
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+func TestParseInto(t *testing.T) {
+	key, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+	otherKey, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+
+	now := time.Now().Truncate(time.Second).UTC()
+	src := testClaims{
+		StandardClaims: jwt.StandardClaims{
+			Audience:   jwt.Audience{`app`},
+			Expiration: jwt.NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:   jwt.NewNumericDate(now),
+			Issuer:     `https://github.com/lestrrat-go/jwx`,
+			Subject:    `john.doe`,
+		},
+		Email: `john.doe@example.com`,
+		Roles: []string{`admin`, `user`},
+	}
+
+	tok, err := jwt.NewFromStruct(src)
+	require.NoError(t, err, `jwt.NewFromStruct should succeed`)
+	require.Equal(t, src.Issuer, tok.Issuer())
+	require.Equal(t, []string(src.Audience), tok.Audience())
+	require.True(t, src.Expiration.Equal(tok.Expiration()), `"exp" should match`)
+	email, ok := tok.Get(`email`)
+	require.True(t, ok, `"email" should exist`)
+	require.Equal(t, src.Email, email)
+
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.HS256, key))
+	require.NoError(t, err, `jwt.Sign should succeed`)
+
+	t.Run("ParseInto", func(t *testing.T) {
+		var dst testClaims
+		require.NoError(t, jwt.ParseInto(signed, &dst, jwt.WithKey(jwa.HS256, key)), `jwt.ParseInto should succeed`)
+		require.Equal(t, src, dst)
+	})
+	t.Run("ParseInto with wrong key", func(t *testing.T) {
+		var dst testClaims
+		require.Error(t, jwt.ParseInto(signed, &dst, jwt.WithKey(jwa.HS256, otherKey)), `jwt.ParseInto should fail`)
+		require.Equal(t, testClaims{}, dst, `dst should not be modified`)
+	})
+	t.Run("ParseInto with failed validation", func(t *testing.T) {
+		var dst testClaims
+		require.Error(t, jwt.ParseInto(signed, &dst, jwt.WithKey(jwa.HS256, key), jwt.WithAudience(`other`)), `jwt.ParseInto should fail`)
+		require.Equal(t, testClaims{}, dst, `dst should not be modified`)
+	})
+	t.Run("UnmarshalClaims", func(t *testing.T) {
+		parsed, err := jwt.Parse(signed, jwt.WithKey(jwa.HS256, key))
+		require.NoError(t, err, `jwt.Parse should succeed`)
+
+		var dst testClaims
+		require.NoError(t, jwt.UnmarshalClaims(parsed, &dst), `jwt.UnmarshalClaims should succeed`)
+		require.Equal(t, src, dst)
+	})
+	t.Run("Single audience", func(t *testing.T) {
+		var dst testClaims
+		require.NoError(t, jwt.ParseInto([]byte(`{"aud":"app","exp":"1700000000"}`), &dst, jwt.WithVerify(false), jwt.WithValidate(false)), `jwt.ParseInto should succeed`)
+		require.Equal(t, jwt.Audience{`app`}, dst.Audience)
+		require.Equal(t, int64(1700000000), dst.Expiration.Unix())
+	})
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -10,6 +11,29 @@ import (
 	"github.com/lestrrat-go/jwx/v2/internal/pool"
 )
 
+var errTokenNotFound = errors.New(`token not found in request`)
+
+// ErrTokenNotFound returns the immutable error used when `jwt.ParseRequest()`
+// could not find a token in any of the locations that it searched.
+// It is not returned if a token was found but failed to be parsed.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrTokenNotFound() error {
+	return errTokenNotFound
+}
+
+type tokenNotFoundError struct {
+	msg string
+}
+
+func (err *tokenNotFoundError) Error() string {
+	return err.msg
+}
+
+func (*tokenNotFoundError) Unwrap() error {
+	return errTokenNotFound
+}
+
 // ParseHeader parses a JWT stored in a http.Header.
 //
 // For the header "Authorization", it will strip the prefix "Bearer " and will
@@ -40,11 +64,28 @@ func ParseForm(values url.Values, name string, options ...ParseOption) (Token, e
 	return ParseString(v, options...)
 }
 
+// ParseCookie parses a JWT stored in the cookie `name` of a http.Request.
+func ParseCookie(req *http.Request, name string, options ...ParseOption) (Token, error) {
+	c, err := req.Cookie(name)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to get cookie (%s): %w`, name, err)
+	}
+
+	v := strings.TrimSpace(c.Value)
+	if v == "" {
+		return nil, fmt.Errorf(`empty cookie (%s)`, name)
+	}
+
+	return ParseString(v, options...)
+}
+
 // ParseRequest searches a http.Request object for a JWT token.
 //
 // Specifying WithHeaderKey() will tell it to search under a specific
 // header key. Specifying WithFormKey() will tell it to search under
-// a specific form field.
+// a specific form field. Specifying WithCookieKey() will tell it to
+// search under a specific cookie. Headers are searched first, then
+// form fields, then cookies.
 //
 // By default, "Authorization" header will be searched.
 //
@@ -58,9 +99,13 @@ func ParseForm(values url.Values, name string, options ...ParseOption) (Token, e
 //
 //	# searches for "Authorization" AND "x-my-token"
 //	jwt.ParseRequest(req, jwt.WithHeaderKey("Authorization"), jwt.WithHeaderKey("x-my-token"))
+//
+// If none of the locations contain a token, the returned error can be
+// compared against `jwt.ErrTokenNotFound()` using `errors.Is()`
 func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 	var hdrkeys []string
 	var formkeys []string
+	var cookiekeys []string
 	var parseOptions []ParseOption
 	for _, option := range options {
 		//nolint:forcetypeassert
@@ -69,6 +114,8 @@ func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 			hdrkeys = append(hdrkeys, option.Value().(string))
 		case identFormKey{}:
 			formkeys = append(formkeys, option.Value().(string))
+		case identCookieKey{}:
+			cookiekeys = append(cookiekeys, option.Value().(string))
 		default:
 			parseOptions = append(parseOptions, option)
 		}
@@ -81,6 +128,8 @@ func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 	defer pool.ReleaseKeyToErrorMap(mhdrs)
 	mfrms := pool.GetKeyToErrorMap()
 	defer pool.ReleaseKeyToErrorMap(mfrms)
+	mcookies := pool.GetKeyToErrorMap()
+	defer pool.ReleaseKeyToErrorMap(mcookies)
 
 	for _, hdrkey := range hdrkeys {
 		// Check presence via a direct map lookup
@@ -118,6 +167,20 @@ func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 		return tok, nil
 	}
 
+	for _, cookiekey := range cookiekeys {
+		if _, err := req.Cookie(cookiekey); err != nil {
+			// if non-existent, not error
+			continue
+		}
+
+		tok, err := ParseCookie(req, cookiekey, parseOptions...)
+		if err != nil {
+			mcookies[cookiekey] = err
+			continue
+		}
+		return tok, nil
+	}
+
 	// Everything below is a preulde to error reporting.
 	var triedHdrs strings.Builder
 	for i, hdrkey := range hdrkeys {
@@ -135,6 +198,14 @@ func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 		triedForms.WriteString(strconv.Quote(formkey))
 	}
 
+	var triedCookies strings.Builder
+	for i, cookiekey := range cookiekeys {
+		if i > 0 {
+			triedCookies.WriteString(", ")
+		}
+		triedCookies.WriteString(strconv.Quote(cookiekey))
+	}
+
 	var b strings.Builder
 	b.WriteString(`failed to find a valid token in any location of the request (tried: [header keys: `)
 	b.WriteString(triedHdrs.String())
@@ -144,45 +215,71 @@ func ParseRequest(req *http.Request, options ...ParseOption) (Token, error) {
 		b.WriteString(triedForms.String())
 		b.WriteByte(']')
 	}
+	if triedCookies.Len() > 0 {
+		b.WriteString(", cookie keys: [")
+		b.WriteString(triedCookies.String())
+		b.WriteByte(']')
+	}
 	b.WriteByte(')')
 
 	lmhdrs := len(mhdrs)
 	lmfrms := len(mfrms)
-	if lmhdrs > 0 || lmfrms > 0 {
-		b.WriteString(". Additionally, errors were encountered during attempts to parse")
-
-		if lmhdrs > 0 {
-			b.WriteString(" headers: (")
-			count := 0
-			for hdrkey, err := range mhdrs {
-				if count > 0 {
-					b.WriteString(", ")
-				}
-				b.WriteString("[header key: ")
-				b.WriteString(strconv.Quote(hdrkey))
-				b.WriteString(", error: ")
-				b.WriteString(strconv.Quote(err.Error()))
-				b.WriteString("]")
-				count++
+	lmcookies := len(mcookies)
+	if lmhdrs == 0 && lmfrms == 0 && lmcookies == 0 {
+		return nil, &tokenNotFoundError{msg: b.String()}
+	}
+
+	b.WriteString(". Additionally, errors were encountered during attempts to parse")
+
+	if lmhdrs > 0 {
+		b.WriteString(" headers: (")
+		count := 0
+		for hdrkey, err := range mhdrs {
+			if count > 0 {
+				b.WriteString(", ")
 			}
-			b.WriteString(")")
+			b.WriteString("[header key: ")
+			b.WriteString(strconv.Quote(hdrkey))
+			b.WriteString(", error: ")
+			b.WriteString(strconv.Quote(err.Error()))
+			b.WriteString("]")
+			count++
 		}
+		b.WriteString(")")
+	}
+
+	if lmfrms > 0 {
+		count := 0
+		b.WriteString(" forms: (")
+		for formkey, err := range mfrms {
+			if count > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("[form key: ")
+			b.WriteString(strconv.Quote(formkey))
+			b.WriteString(", error: ")
+			b.WriteString(strconv.Quote(err.Error()))
+			b.WriteString("]")
+			count++
+		}
+		b.WriteString(")")
+	}
 
-		if lmfrms > 0 {
-			count := 0
-			b.WriteString(" forms: (")
-			for formkey, err := range mfrms {
-				if count > 0 {
-					b.WriteString(", ")
-				}
-				b.WriteString("[form key: ")
-				b.WriteString(strconv.Quote(formkey))
-				b.WriteString(", error: ")
-				b.WriteString(strconv.Quote(err.Error()))
-				b.WriteString("]")
-				count++
+	if lmcookies > 0 {
+		count := 0
+		b.WriteString(" cookies: (")
+		for cookiekey, err := range mcookies {
+			if count > 0 {
+				b.WriteString(", ")
 			}
+			b.WriteString("[cookie key: ")
+			b.WriteString(strconv.Quote(cookiekey))
+			b.WriteString(", error: ")
+			b.WriteString(strconv.Quote(err.Error()))
+			b.WriteString("]")
+			count++
 		}
+		b.WriteString(")")
 	}
 	return nil, fmt.Errorf(b.String())
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/lestrrat-go/jwx/v2"
 	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwe"
 	"github.com/lestrrat-go/jwx/v2/jws"
 	"github.com/lestrrat-go/jwx/v2/jwt/internal/types"
 )
@@ -101,9 +102,10 @@ func ParseString(s string, options ...ParseOption) (Token, error) {
 // Parse parses the JWT token payload and creates a new `jwt.Token` object.
 // The token must be encoded in either JSON format or compact format.
 //
-// This function can only work with either raw JWT (JSON) and JWS (Compact or JSON).
-// If you need JWE support on top of it, you will need to rollout your
-// own workaround.
+// This function can work with raw JWT (JSON), JWS (Compact or JSON), and
+// JWE (Compact or JSON). Encrypted tokens are only accepted if
+// `jwt.WithDecryptOption()` is specified, and the decrypted payload must
+// be signed unless `jwt.WithVerify(false)` is specified.
 //
 // If the token is signed and you want to verify the payload matches the signature,
 // you must pass the jwt.WithKey(alg, key) or jwt.WithKeySet(jwk.Set) option.
@@ -127,6 +129,33 @@ func Parse(s []byte, options ...ParseOption) (Token, error) {
 	return parseBytes(s, options...)
 }
 
+// ParseInto is exactly the same as Parse(), except that after the token
+// has been verified and validated, its payload is additionally decoded into
+// `dst`, which is typically a pointer to a struct with `json` tags.
+// This allows you to access the claims without type assertions:
+//
+//	type MyClaims struct {
+//	  jwt.StandardClaims
+//	  Email string   `json:"email"`
+//	  Roles []string `json:"roles"`
+//	}
+//
+//	var claims MyClaims
+//	if err := jwt.ParseInto(data, &claims, jwt.WithKey(alg, key)); err != nil {
+//	  ...
+//	}
+//
+// The same options as Parse() are accepted, and signature verification
+// as well as `jwt.Validate()` are performed in exactly the same way.
+// `dst` is not modified if either of them fail.
+func ParseInto(s []byte, dst interface{}, options ...ParseOption) error {
+	if dst == nil {
+		return fmt.Errorf(`jwt.ParseInto: destination must not be nil`)
+	}
+	_, err := parseBytesInto(s, dst, options...)
+	return err
+}
+
 // ParseInsecure is exactly the same as Parse(), but it disables
 // signature verification and token validation.
 //
@@ -157,8 +186,10 @@ func ParseReader(src io.Reader, options ...ParseOption) (Token, error) {
 
 type parseCtx struct {
 	token            Token
+	dst              interface{}
 	validateOpts     []ValidateOption
 	verifyOpts       []jws.VerifyOption
+	decryptOpts      []jwe.DecryptOption
 	localReg         *json.Registry
 	pedantic         bool
 	skipVerification bool
@@ -166,7 +197,12 @@ type parseCtx struct {
 }
 
 func parseBytes(data []byte, options ...ParseOption) (Token, error) {
+	return parseBytesInto(data, nil, options...)
+}
+
+func parseBytesInto(data []byte, dst interface{}, options ...ParseOption) (Token, error) {
 	var ctx parseCtx
+	ctx.dst = dst
 
 	// Validation is turned on by default. You need to specify
 	// jwt.WithValidate(false) if you want to disable it
@@ -178,6 +214,7 @@ func parseBytes(data []byte, options ...ParseOption) (Token, error) {
 	verification := true
 
 	var verifyOpts []Option
+	var critOpts []Option
 	for _, o := range options {
 		if v, ok := o.(ValidateOption); ok {
 			ctx.validateOpts = append(ctx.validateOpts, v)
@@ -186,8 +223,13 @@ func parseBytes(data []byte, options ...ParseOption) (Token, error) {
 
 		//nolint:forcetypeassert
 		switch o.Ident() {
-		case identKey{}, identKeySet{}, identVerifyAuto{}, identKeyProvider{}:
+		case identKey{}, identKeySet{}, identCachedKeySet{}, identVerifyAuto{}, identKeyProvider{}:
 			verifyOpts = append(verifyOpts, o)
+		case identCriticalExtensions{}:
+			// not counted as a source of keys
+			critOpts = append(critOpts, o)
+		case identDecryptOption{}:
+			ctx.decryptOpts = append(ctx.decryptOpts, o.Value().(jwe.DecryptOption))
 		case identToken{}:
 			token, ok := o.Value().(Token)
 			if !ok {
@@ -215,7 +257,7 @@ func parseBytes(data []byte, options ...ParseOption) (Token, error) {
 	}
 
 	if lvo > 0 {
-		converted, err := toVerifyOptions(verifyOpts...)
+		converted, err := toVerifyOptions(append(verifyOpts, critOpts...)...)
 		if err != nil {
 			return nil, fmt.Errorf(`jwt.Parse: failed to convert options into jws.VerifyOption: %w`, err)
 		}
@@ -253,6 +295,10 @@ func parse(ctx *parseCtx, data []byte) (Token, error) {
 	// If cty = `JWT`, we expect this to be a nested structure
 	var expectNested bool
 
+	// If the payload was decrypted, the JWT inside it must still be
+	// verified, as anybody can encrypt a token using a public key
+	var decrypted bool
+
 OUTER:
 	for i := 0; i < maxDecodeLevels; i++ {
 		switch kind := jwx.GuessFormat(payload); kind {
@@ -263,7 +309,7 @@ OUTER:
 				}
 			}
 
-			if i == 0 {
+			if i == 0 || decrypted {
 				// We were NOT enveloped in other formats
 				if !ctx.skipVerification {
 					if _, _, err := verifyJWS(ctx, payload); err != nil {
@@ -282,7 +328,7 @@ OUTER:
 				return nil, fmt.Errorf(`unknown JWT format (pedantic)`)
 			}
 
-			if i == 0 {
+			if i == 0 || decrypted {
 				// We were NOT enveloped in other formats
 				if !ctx.skipVerification {
 					if _, _, err := verifyJWS(ctx, payload); err != nil {
@@ -326,11 +372,23 @@ OUTER:
 			}
 
 			// No verification.
-			m, err := jws.Parse(data)
+			m, err := jws.Parse(payload)
 			if err != nil {
 				return nil, fmt.Errorf(`invalid jws message: %w`, err)
 			}
 			payload = m.Payload()
+		case jwx.JWE:
+			if len(ctx.decryptOpts) == 0 {
+				return nil, fmt.Errorf(`jwt.Parse: no options for decryption are provided (use jwt.WithDecryptOption() to specify them)`)
+			}
+
+			v, err := jwe.Decrypt(payload, ctx.decryptOpts...)
+			if err != nil {
+				return nil, fmt.Errorf(`failed to decrypt payload: %w`, err)
+			}
+			payload = v
+			decrypted = true
+			continue
 		default:
 			return nil, fmt.Errorf(`unsupported format (layer: #%d)`, i+1)
 		}
@@ -360,6 +418,12 @@ OUTER:
 			return nil, err
 		}
 	}
+
+	if ctx.dst != nil {
+		if err := json.Unmarshal(payload, ctx.dst); err != nil {
+			return nil, fmt.Errorf(`failed to decode claims: %w`, err)
+		}
+	}
 	return ctx.token, nil
 }
 
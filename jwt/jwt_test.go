@@ -926,6 +926,29 @@ func TestParseRequest(t *testing.T) {
 			},
 			Error: true,
 		},
+		{
+			Name: "Token in access_token cookie (w/ option)",
+			Request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, u, nil)
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: string(signed)})
+				return req
+			},
+			Parse: func(req *http.Request) (jwt.Token, error) {
+				return jwt.ParseRequest(req, jwt.WithCookieKey("access_token"), jwt.WithKey(jwa.ES256, pubkey))
+			},
+		},
+		{
+			Name: "Invalid token in access_token cookie",
+			Request: func() *http.Request {
+				req := httptest.NewRequest(http.MethodGet, u, nil)
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: string(signed) + "foobarbaz"})
+				return req
+			},
+			Parse: func(req *http.Request) (jwt.Token, error) {
+				return jwt.ParseRequest(req, jwt.WithCookieKey("access_token"), jwt.WithKey(jwa.ES256, pubkey))
+			},
+			Error: true,
+		},
 		{
 			Name: "Invalid token in access_token form field",
 			Request: func() *http.Request {
@@ -1667,3 +1690,63 @@ func TestGH850(t *testing.T) {
 	_, err := jwt.Parse([]byte(testToken), jwt.WithVerify(false))
 	require.True(t, errors.Is(err, jwt.ErrInvalidJWT()))
 }
+
+func TestParseCriticalExtensions(t *testing.T) {
+	key := []byte(`abracadabra`)
+	tok, err := jwt.NewBuilder().Issuer(`github.com/lestrrat-go/jwx`).Build()
+	require.NoError(t, err, `jwt.NewBuilder should succeed`)
+
+	hdrs := jws.NewHeaders()
+	require.NoError(t, hdrs.Set(jws.CriticalKey, []string{`myext`}), `hdrs.Set should succeed`)
+	require.NoError(t, hdrs.Set(`myext`, `foo`), `hdrs.Set should succeed`)
+	signed, err := jwt.Sign(tok, jwt.WithKey(jwa.HS256, key, jws.WithProtectedHeaders(hdrs)))
+	require.NoError(t, err, `jwt.Sign should succeed`)
+
+	_, err = jwt.Parse(signed, jwt.WithKey(jwa.HS256, key))
+	require.Error(t, err, `jwt.Parse should fail when "crit" lists an undeclared extension`)
+
+	_, err = jwt.Parse(signed, jwt.WithKey(jwa.HS256, key), jwt.WithCriticalExtensions(`otherext`))
+	require.Error(t, err, `jwt.Parse should fail when "crit" lists an undeclared extension`)
+
+	parsed, err := jwt.Parse(signed, jwt.WithKey(jwa.HS256, key), jwt.WithCriticalExtensions(`myext`))
+	require.NoError(t, err, `jwt.Parse should succeed`)
+	require.Equal(t, tok.Issuer(), parsed.Issuer())
+
+	_, err = jwt.Parse(signed, jwt.WithCriticalExtensions(`myext`))
+	require.Error(t, err, `jwt.WithCriticalExtensions should not count as a key`)
+}
+
+func TestParseEncrypted(t *testing.T) {
+	signKey := []byte(`abracadabra`)
+	encKey := []byte(`0123456789abcdef`)
+	tok, err := jwt.NewBuilder().Issuer(`github.com/lestrrat-go/jwx`).Build()
+	require.NoError(t, err, `jwt.NewBuilder should succeed`)
+
+	signed, err := jwt.NewSerializer().
+		Sign(jwt.WithKey(jwa.HS256, signKey)).
+		Encrypt(jwt.WithKey(jwa.A128KW, encKey)).
+		Serialize(tok)
+	require.NoError(t, err, `Serialize should succeed`)
+
+	parsed, err := jwt.Parse(signed, jwt.WithDecryptOption(jwe.WithKey(jwa.A128KW, encKey)), jwt.WithKey(jwa.HS256, signKey))
+	require.NoError(t, err, `jwt.Parse should succeed`)
+	require.Equal(t, tok.Issuer(), parsed.Issuer())
+
+	_, err = jwt.Parse(signed, jwt.WithKey(jwa.HS256, signKey))
+	require.Error(t, err, `jwt.Parse should fail without jwt.WithDecryptOption`)
+
+	_, err = jwt.Parse(signed, jwt.WithDecryptOption(jwe.WithKey(jwa.A128KW, encKey)), jwt.WithKey(jwa.HS256, []byte(`other`)))
+	require.Error(t, err, `jwt.Parse should fail with the wrong verification key`)
+
+	unsigned, err := jwt.NewSerializer().
+		Encrypt(jwt.WithKey(jwa.A128KW, encKey)).
+		Serialize(tok)
+	require.NoError(t, err, `Serialize should succeed`)
+
+	_, err = jwt.Parse(unsigned, jwt.WithDecryptOption(jwe.WithKey(jwa.A128KW, encKey)), jwt.WithKey(jwa.HS256, signKey))
+	require.Error(t, err, `jwt.Parse should fail if the decrypted token is not signed`)
+
+	parsed, err = jwt.Parse(unsigned, jwt.WithDecryptOption(jwe.WithKey(jwa.A128KW, encKey)), jwt.WithVerify(false))
+	require.NoError(t, err, `jwt.Parse should succeed with jwt.WithVerify(false)`)
+	require.Equal(t, tok.Issuer(), parsed.Issuer())
+}
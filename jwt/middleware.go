@@ -0,0 +1,132 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+type tokenContextKey struct{}
+
+// ContextWithToken returns a copy of `ctx` that carries `tok`.
+// The token can be retrieved using `jwt.TokenFromContext()`.
+func ContextWithToken(ctx context.Context, tok Token) context.Context {
+	return context.WithValue(ctx, tokenContextKey{}, tok)
+}
+
+// TokenFromContext returns the token stored in `ctx` by the middleware
+// created by `jwt.NewMiddleware()` or by `jwt.ContextWithToken()`.
+// The second return value is false if no token is stored.
+func TokenFromContext(ctx context.Context) (Token, bool) {
+	tok, ok := ctx.Value(tokenContextKey{}).(Token)
+	return tok, ok
+}
+
+// NewMiddleware creates a middleware that authenticates HTTP requests
+// using a JWT bearer token. The token is searched for, verified, and
+// validated using `jwt.ParseRequest()` with the given options, and
+// is stored in the request context for the wrapped handler to retrieve
+// using `jwt.TokenFromContext()`:
+//
+//	mw := jwt.NewMiddleware(
+//	  jwt.WithKeySet(set),
+//	  jwt.WithCookieKey(`access_token`),
+//	  jwt.WithRequiredScopes(`read`),
+//	)
+//	http.Handle(`/`, mw(handler))
+//
+// Requests that fail are rejected with a response compliant with RFC6750:
+//
+//   - If the request does not contain a token, 401 with `WWW-Authenticate: Bearer`
+//   - If the scopes specified by `jwt.WithRequiredScopes()` are not granted
+//     (and the token is otherwise valid), 403 with `WWW-Authenticate: Bearer error="insufficient_scope", ...`
+//   - Otherwise, 401 with `WWW-Authenticate: Bearer error="invalid_token", ...`
+//
+// Note that `jwt.WithValidate(false)` disables all validation, including
+// the scopes.
+func NewMiddleware(options ...ParseOption) func(http.Handler) http.Handler {
+	validate := true
+	var validateOptions []ValidateOption
+	for _, option := range options {
+		if v, ok := option.(ValidateOption); ok {
+			validateOptions = append(validateOptions, v)
+			continue
+		}
+		if option.Ident() == (identValidate{}) {
+			validate = option.Value().(bool) //nolint:forcetypeassert
+		}
+	}
+
+	// Validation is performed separately, so that the errors are not
+	// buried in the error returned from ParseRequest
+	parseOptions := append(append([]ParseOption(nil), options...), WithValidate(false))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			tok, err := ParseRequest(req, parseOptions...)
+			if err != nil {
+				if errors.Is(err, ErrTokenNotFound()) {
+					w.Header().Set(`WWW-Authenticate`, `Bearer`)
+					http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+					return
+				}
+				writeBearerError(w, http.StatusUnauthorized, `invalid_token`, `the access token is invalid`)
+				return
+			}
+
+			if validate {
+				if err := Validate(tok, validateOptions...); err != nil {
+					if isInsufficientScopeOnly(err) {
+						writeBearerError(w, http.StatusForbidden, `insufficient_scope`, err.Error())
+						return
+					}
+					writeBearerError(w, http.StatusUnauthorized, `invalid_token`, err.Error())
+					return
+				}
+			}
+
+			next.ServeHTTP(w, req.WithContext(ContextWithToken(req.Context(), tok)))
+		})
+	}
+}
+
+// isInsufficientScopeOnly returns true if the only reason that err was
+// returned is that the required scopes were not granted. When
+// `jwt.WithValidateAll(true)` is specified, the token may have failed
+// other validations as well, in which case the token is invalid.
+func isInsufficientScopeOnly(err error) bool {
+	var merr MultiValidationError
+	if !errors.As(err, &merr) {
+		return errors.Is(err, ErrInsufficientScope())
+	}
+
+	for _, f := range merr.Failures() {
+		if !errors.Is(f.Err, ErrInsufficientScope()) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeBearerError(w http.ResponseWriter, status int, code, description string) {
+	w.Header().Set(`WWW-Authenticate`, `Bearer error="`+code+`", error_description="`+sanitizeErrorDescription(description)+`"`)
+	http.Error(w, http.StatusText(status), status)
+}
+
+// sanitizeErrorDescription removes the characters that are not allowed
+// in the error_description attribute (RFC6750 section 3)
+func sanitizeErrorDescription(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '"':
+			return '\''
+		case r == '\\':
+			return '/'
+		case r < 0x20 || r > 0x7e:
+			return -1
+		default:
+			return r
+		}
+	}, s)
+}
@@ -0,0 +1,182 @@
+package jwt_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+	key, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+	otherKey, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+
+	sign := func(t *testing.T, key interface{}, exp time.Time, scope string) string {
+		t.Helper()
+		tok, err := jwt.NewBuilder().
+			Subject(`john.doe`).
+			Expiration(exp).
+			Claim(`scope`, scope).
+			Build()
+		require.NoError(t, err, `jwt.NewBuilder should succeed`)
+		signed, err := jwt.Sign(tok, jwt.WithKey(jwa.HS256, key))
+		require.NoError(t, err, `jwt.Sign should succeed`)
+		return string(signed)
+	}
+
+	mw := jwt.NewMiddleware(
+		jwt.WithKey(jwa.HS256, key),
+		jwt.WithHeaderKey(`Authorization`),
+		jwt.WithCookieKey(`access_token`),
+		jwt.WithRequiredScopes(`read`),
+	)
+	handler := mw(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tok, ok := jwt.TokenFromContext(req.Context())
+		if !ok {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(tok.Subject()))
+	}))
+
+	valid := sign(t, key, time.Now().Add(time.Hour), `read write`)
+	testcases := []struct {
+		Name           string
+		Request        func(*http.Request)
+		Status         int
+		Authenticate   string
+		ExpectedResult string
+	}{
+		{
+			Name:           "Authorization header",
+			Request:        func(req *http.Request) { req.Header.Set(`Authorization`, `Bearer `+valid) },
+			Status:         http.StatusOK,
+			ExpectedResult: `john.doe`,
+		},
+		{
+			Name:           "Cookie",
+			Request:        func(req *http.Request) { req.AddCookie(&http.Cookie{Name: `access_token`, Value: valid}) },
+			Status:         http.StatusOK,
+			ExpectedResult: `john.doe`,
+		},
+		{
+			Name:         "No token",
+			Request:      func(*http.Request) {},
+			Status:       http.StatusUnauthorized,
+			Authenticate: `Bearer`,
+		},
+		{
+			Name: "Invalid signature",
+			Request: func(req *http.Request) {
+				req.Header.Set(`Authorization`, `Bearer `+sign(t, otherKey, time.Now().Add(time.Hour), `read`))
+			},
+			Status:       http.StatusUnauthorized,
+			Authenticate: `Bearer error="invalid_token", error_description="the access token is invalid"`,
+		},
+		{
+			Name: "Expired",
+			Request: func(req *http.Request) {
+				req.Header.Set(`Authorization`, `Bearer `+sign(t, key, time.Now().Add(-time.Hour), `read`))
+			},
+			Status:       http.StatusUnauthorized,
+			Authenticate: `Bearer error="invalid_token", error_description="'exp' not satisfied"`,
+		},
+		{
+			Name: "Insufficient scope",
+			Request: func(req *http.Request) {
+				req.Header.Set(`Authorization`, `Bearer `+sign(t, key, time.Now().Add(time.Hour), `write`))
+			},
+			Status:       http.StatusForbidden,
+			Authenticate: `Bearer error="insufficient_scope", error_description="'scope' not satisfied: scope 'read' is not granted"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, `https://example.com`, nil)
+			tc.Request(req)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.Status, rec.Code, `status code should match`)
+			require.Equal(t, tc.Authenticate, rec.Header().Get(`WWW-Authenticate`), `WWW-Authenticate should match`)
+			if tc.ExpectedResult != "" {
+				require.Equal(t, tc.ExpectedResult, rec.Body.String())
+			}
+		})
+	}
+
+	t.Run("WithValidateAll", func(t *testing.T) {
+		handler := jwt.NewMiddleware(
+			jwt.WithKey(jwa.HS256, key),
+			jwt.WithRequiredScopes(`read`),
+			jwt.WithValidateAll(true),
+		)(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+
+		testcases := []struct {
+			Name   string
+			Token  string
+			Status int
+			Error  string
+		}{
+			{Name: "Insufficient scope", Token: sign(t, key, time.Now().Add(time.Hour), `write`), Status: http.StatusForbidden, Error: `insufficient_scope`},
+			{Name: "Expired and insufficient scope", Token: sign(t, key, time.Now().Add(-time.Hour), `write`), Status: http.StatusUnauthorized, Error: `invalid_token`},
+		}
+		for _, tc := range testcases {
+			req := httptest.NewRequest(http.MethodGet, `https://example.com`, nil)
+			req.Header.Set(`Authorization`, `Bearer `+tc.Token)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			require.Equal(t, tc.Status, rec.Code, `status code should match (%s)`, tc.Name)
+			require.Contains(t, rec.Header().Get(`WWW-Authenticate`), `error="`+tc.Error+`"`, `WWW-Authenticate should match (%s)`, tc.Name)
+		}
+	})
+	t.Run("ErrTokenNotFound", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, `https://example.com`, nil)
+		_, err := jwt.ParseRequest(req, jwt.WithKey(jwa.HS256, key))
+		require.True(t, errors.Is(err, jwt.ErrTokenNotFound()), `error should be ErrTokenNotFound`)
+
+		req.Header.Set(`Authorization`, `Bearer garbage`)
+		_, err = jwt.ParseRequest(req, jwt.WithKey(jwa.HS256, key))
+		require.Error(t, err, `jwt.ParseRequest should fail`)
+		require.False(t, errors.Is(err, jwt.ErrTokenNotFound()), `error should not be ErrTokenNotFound`)
+	})
+}
+
+func TestHasScopes(t *testing.T) {
+	testcases := []struct {
+		Name  string
+		Scope interface{}
+		Error bool
+	}{
+		{Name: "Space-delimited string", Scope: `read write`},
+		{Name: "List of strings", Scope: []string{`read`, `write`}},
+		{Name: "Missing scope", Scope: `read`, Error: true},
+		{Name: "Invalid type", Scope: 1, Error: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			tok := jwt.New()
+			require.NoError(t, tok.Set(`scope`, tc.Scope), `tok.Set should succeed`)
+			err := jwt.Validate(tok, jwt.WithRequiredScopes(`read`, `write`))
+			if !tc.Error {
+				require.NoError(t, err, `jwt.Validate should succeed`)
+				return
+			}
+			require.True(t, errors.Is(err, jwt.ErrInsufficientScope()), `error should be ErrInsufficientScope`)
+			require.True(t, jwt.IsValidationError(err), `error should be a validation error`)
+		})
+	}
+}
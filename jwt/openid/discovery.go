@@ -0,0 +1,312 @@
+package openid
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/httprc"
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+)
+
+const (
+	// OpenIDConfigurationPath is the path appended to the issuer to
+	// locate its OpenID Connect Discovery document
+	OpenIDConfigurationPath = `/.well-known/openid-configuration`
+	// AuthorizationServerMetadataPath is the path inserted between the
+	// host and the path of the issuer to locate its OAuth 2.0
+	// Authorization Server Metadata document (RFC8414)
+	AuthorizationServerMetadataPath = `/.well-known/oauth-authorization-server`
+)
+
+// ProviderMetadata represents the metadata of an OpenID Provider
+// (OpenID Connect Discovery 1.0, Section 3) or an OAuth 2.0 Authorization
+// Server (RFC8414, Section 2).
+type ProviderMetadata struct {
+	Issuer                                     string                           `json:"issuer"`
+	AuthorizationEndpoint                      string                           `json:"authorization_endpoint,omitempty"`
+	TokenEndpoint                              string                           `json:"token_endpoint,omitempty"`
+	UserinfoEndpoint                           string                           `json:"userinfo_endpoint,omitempty"`
+	JWKSURI                                    string                           `json:"jwks_uri,omitempty"`
+	RegistrationEndpoint                       string                           `json:"registration_endpoint,omitempty"`
+	RevocationEndpoint                         string                           `json:"revocation_endpoint,omitempty"`
+	IntrospectionEndpoint                      string                           `json:"introspection_endpoint,omitempty"`
+	EndSessionEndpoint                         string                           `json:"end_session_endpoint,omitempty"`
+	ScopesSupported                            []string                         `json:"scopes_supported,omitempty"`
+	ResponseTypesSupported                     []string                         `json:"response_types_supported,omitempty"`
+	ResponseModesSupported                     []string                         `json:"response_modes_supported,omitempty"`
+	GrantTypesSupported                        []string                         `json:"grant_types_supported,omitempty"`
+	SubjectTypesSupported                      []string                         `json:"subject_types_supported,omitempty"`
+	ClaimsSupported                            []string                         `json:"claims_supported,omitempty"`
+	CodeChallengeMethodsSupported              []string                         `json:"code_challenge_methods_supported,omitempty"`
+	TokenEndpointAuthMethodsSupported          []string                         `json:"token_endpoint_auth_methods_supported,omitempty"`
+	TokenEndpointAuthSigningAlgValuesSupported []jwa.SignatureAlgorithm         `json:"token_endpoint_auth_signing_alg_values_supported,omitempty"`
+	IDTokenSigningAlgValuesSupported           []jwa.SignatureAlgorithm         `json:"id_token_signing_alg_values_supported,omitempty"`
+	IDTokenEncryptionAlgValuesSupported        []jwa.KeyEncryptionAlgorithm     `json:"id_token_encryption_alg_values_supported,omitempty"`
+	IDTokenEncryptionEncValuesSupported        []jwa.ContentEncryptionAlgorithm `json:"id_token_encryption_enc_values_supported,omitempty"`
+	UserinfoSigningAlgValuesSupported          []jwa.SignatureAlgorithm         `json:"userinfo_signing_alg_values_supported,omitempty"`
+	RequestObjectSigningAlgValuesSupported     []jwa.SignatureAlgorithm         `json:"request_object_signing_alg_values_supported,omitempty"`
+	ServiceDocumentation                       string                           `json:"service_documentation,omitempty"`
+}
+
+// SupportsSigningAlgorithm returns true if `alg` is listed in the
+// "id_token_signing_alg_values_supported" field of the metadata.
+func (m *ProviderMetadata) SupportsSigningAlgorithm(alg jwa.SignatureAlgorithm) bool {
+	for _, v := range m.IDTokenSigningAlgValuesSupported {
+		if v == alg {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscoveryURL returns the URL of the provider metadata document for
+// the given issuer. If `authServer` is true, the location defined in
+// RFC8414 is returned instead of the OpenID Connect Discovery location.
+func DiscoveryURL(issuer string, authServer bool) (string, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return "", fmt.Errorf(`failed to parse issuer %q: %w`, issuer, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf(`issuer %q must be an absolute URL`, issuer)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf(`issuer %q must not contain query or fragment components`, issuer)
+	}
+
+	path := strings.TrimSuffix(u.Path, `/`)
+	if authServer {
+		u.Path = AuthorizationServerMetadataPath + path
+	} else {
+		u.Path = path + OpenIDConfigurationPath
+	}
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// metadataTransform is a httprc.Transformer that parses the
+// provider metadata, and verifies that it was issued by the
+// expected issuer
+type metadataTransform struct {
+	issuer string
+}
+
+func (t *metadataTransform) Transform(u string, res *http.Response) (interface{}, error) {
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(`failed to fetch %q: unexpected status code %d`, u, res.StatusCode)
+	}
+
+	buf, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to read response body for %q: %w`, u, err)
+	}
+
+	var md ProviderMetadata
+	if err := json.Unmarshal(buf, &md); err != nil {
+		return nil, fmt.Errorf(`failed to parse provider metadata from %q: %w`, u, err)
+	}
+
+	// The issuer in the metadata MUST be identical to the issuer
+	// that was used to construct the URL
+	if md.Issuer != t.issuer {
+		return nil, fmt.Errorf(`issuer in provider metadata %q does not match expected issuer %q`, md.Issuer, t.issuer)
+	}
+	return &md, nil
+}
+
+var globalFetcher httprc.Fetcher
+var globalFetcherOnce sync.Once
+
+// Discover fetches the provider metadata for `issuer`, and verifies
+// that the "issuer" field in the metadata matches `issuer`.
+//
+// If you need the metadata for long periods of time, consider using
+// `openid.DiscoveryCache`, which automatically refreshes the metadata
+// behind the scenes.
+func Discover(ctx context.Context, issuer string, options ...DiscoverOption) (*ProviderMetadata, error) {
+	var hrfopts []httprc.FetchOption
+	var authServer bool
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identHTTPClient{}:
+			hrfopts = append(hrfopts, httprc.WithHTTPClient(option.Value().(jwk.HTTPClient)))
+		case identFetchWhitelist{}:
+			hrfopts = append(hrfopts, httprc.WithWhitelist(option.Value().(jwk.Whitelist)))
+		case identAuthorizationServerMetadata{}:
+			authServer = option.Value().(bool)
+		}
+	}
+
+	u, err := DiscoveryURL(issuer, authServer)
+	if err != nil {
+		return nil, err
+	}
+
+	globalFetcherOnce.Do(func() {
+		globalFetcher = httprc.NewFetcher(context.Background())
+	})
+	res, err := globalFetcher.Fetch(ctx, u, hrfopts...)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to fetch %q: %w`, u, err)
+	}
+	defer res.Body.Close()
+
+	v, err := (&metadataTransform{issuer: issuer}).Transform(u, res)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:forcetypeassert
+	return v.(*ProviderMetadata), nil
+}
+
+// DiscoveryCache keeps track of provider metadata by their issuers.
+// Like `jwk.Cache`, the metadata is stored in memory and refreshed
+// automatically behind the scenes.
+//
+// Issuers must be registered using `Register()` before their metadata
+// can be retrieved:
+//
+//	dc := openid.NewDiscoveryCache(ctx)
+//	dc.Register(issuer, openid.WithFetchWhitelist(wl))
+//
+//	c := jwk.NewCache(ctx)
+//	jwksURI, err := dc.RegisterJWKS(ctx, c, issuer)
+//	...
+//	jwt.Parse(data, jwt.WithCachedKeySet(c, jwksURI), jwt.WithIssuer(issuer))
+type DiscoveryCache struct {
+	cache *httprc.Cache
+
+	mu      sync.RWMutex
+	issuers map[string]*discoveryEntry
+}
+
+type discoveryEntry struct {
+	url       string
+	client    jwk.HTTPClient
+	whitelist jwk.Whitelist
+}
+
+// NewDiscoveryCache creates a new `openid.DiscoveryCache`. Refreshes
+// stop when `ctx` is canceled.
+func NewDiscoveryCache(ctx context.Context) *DiscoveryCache {
+	return &DiscoveryCache{
+		cache:   httprc.NewCache(ctx),
+		issuers: make(map[string]*discoveryEntry),
+	}
+}
+
+// Register registers an issuer to be managed by the cache. The
+// metadata is not fetched until `Metadata()` or `RegisterJWKS()`
+// is called.
+func (dc *DiscoveryCache) Register(issuer string, options ...RegisterOption) error {
+	var hrropts []httprc.RegisterOption
+	var authServer bool
+	entry := &discoveryEntry{}
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identHTTPClient{}:
+			entry.client = option.Value().(jwk.HTTPClient)
+			hrropts = append(hrropts, httprc.WithHTTPClient(entry.client))
+		case identFetchWhitelist{}:
+			entry.whitelist = option.Value().(jwk.Whitelist)
+			hrropts = append(hrropts, httprc.WithWhitelist(entry.whitelist))
+		case identAuthorizationServerMetadata{}:
+			authServer = option.Value().(bool)
+		case identRefreshInterval{}:
+			hrropts = append(hrropts, httprc.WithRefreshInterval(option.Value().(time.Duration)))
+		case identMinRefreshInterval{}:
+			hrropts = append(hrropts, httprc.WithMinRefreshInterval(option.Value().(time.Duration)))
+		}
+	}
+
+	u, err := DiscoveryURL(issuer, authServer)
+	if err != nil {
+		return err
+	}
+	entry.url = u
+
+	hrropts = append(hrropts, httprc.WithTransformer(&metadataTransform{issuer: issuer}))
+	if err := dc.cache.Register(u, hrropts...); err != nil {
+		return fmt.Errorf(`failed to register %q: %w`, u, err)
+	}
+
+	dc.mu.Lock()
+	dc.issuers[issuer] = entry
+	dc.mu.Unlock()
+	return nil
+}
+
+func (dc *DiscoveryCache) entry(issuer string) (*discoveryEntry, error) {
+	dc.mu.RLock()
+	defer dc.mu.RUnlock()
+	entry, ok := dc.issuers[issuer]
+	if !ok {
+		return nil, fmt.Errorf(`issuer %q is not registered`, issuer)
+	}
+	return entry, nil
+}
+
+// Metadata returns the provider metadata for `issuer`. If the metadata
+// has not been fetched yet, it is fetched synchronously.
+func (dc *DiscoveryCache) Metadata(ctx context.Context, issuer string) (*ProviderMetadata, error) {
+	entry, err := dc.entry(issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	v, err := dc.cache.Get(ctx, entry.url)
+	if err != nil {
+		return nil, fmt.Errorf(`failed to fetch provider metadata for %q: %w`, issuer, err)
+	}
+
+	md, ok := v.(*ProviderMetadata)
+	if !ok {
+		return nil, fmt.Errorf(`cached object is not a ProviderMetadata (was %T)`, v)
+	}
+	return md, nil
+}
+
+// RegisterJWKS looks up the "jwks_uri" of `issuer`, and registers it
+// with the `jwk.Cache`. The URL is returned so that it can be used with
+// `jwk.Cache.Get()`, `jws.WithCachedKeySet()`, `jwt.WithCachedKeySet()`, etc.
+//
+// The HTTP client and whitelist given to `Register()` are used to
+// fetch the JWKS as well, unless `options` specify otherwise.
+func (dc *DiscoveryCache) RegisterJWKS(ctx context.Context, c *jwk.Cache, issuer string, options ...jwk.RegisterOption) (string, error) {
+	entry, err := dc.entry(issuer)
+	if err != nil {
+		return "", err
+	}
+
+	md, err := dc.Metadata(ctx, issuer)
+	if err != nil {
+		return "", err
+	}
+	if md.JWKSURI == "" {
+		return "", fmt.Errorf(`provider metadata for %q does not contain "jwks_uri"`, issuer)
+	}
+
+	var jwkopts []jwk.RegisterOption
+	if entry.client != nil {
+		jwkopts = append(jwkopts, jwk.WithHTTPClient(entry.client))
+	}
+	if entry.whitelist != nil {
+		jwkopts = append(jwkopts, jwk.WithFetchWhitelist(entry.whitelist))
+	}
+	jwkopts = append(jwkopts, options...)
+
+	if err := c.Register(md.JWKSURI, jwkopts...); err != nil {
+		return "", fmt.Errorf(`failed to register %q: %w`, md.JWKSURI, err)
+	}
+	return md.JWKSURI, nil
+}
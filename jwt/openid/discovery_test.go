@@ -0,0 +1,151 @@
+package openid_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/lestrrat-go/jwx/v2/internal/json"
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/lestrrat-go/jwx/v2/jwt/openid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoveryURL(t *testing.T) {
+	testcases := []struct {
+		Issuer     string
+		AuthServer bool
+		Expected   string
+		Error      bool
+	}{
+		{Issuer: `https://example.com`, Expected: `https://example.com/.well-known/openid-configuration`},
+		{Issuer: `https://example.com/`, Expected: `https://example.com/.well-known/openid-configuration`},
+		{Issuer: `https://example.com/tenant`, Expected: `https://example.com/tenant/.well-known/openid-configuration`},
+		{Issuer: `https://example.com`, AuthServer: true, Expected: `https://example.com/.well-known/oauth-authorization-server`},
+		{Issuer: `https://example.com/tenant`, AuthServer: true, Expected: `https://example.com/.well-known/oauth-authorization-server/tenant`},
+		{Issuer: `example.com`, Error: true},
+		{Issuer: `https://example.com?foo=bar`, Error: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Issuer, func(t *testing.T) {
+			u, err := openid.DiscoveryURL(tc.Issuer, tc.AuthServer)
+			if tc.Error {
+				require.Error(t, err, `openid.DiscoveryURL should fail`)
+				return
+			}
+			require.NoError(t, err, `openid.DiscoveryURL should succeed`)
+			require.Equal(t, tc.Expected, u)
+		})
+	}
+}
+
+func TestDiscovery(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	key, err := jwxtest.GenerateRsaJwk()
+	require.NoError(t, err, `jwxtest.GenerateRsaJwk should succeed`)
+	require.NoError(t, key.Set(jwk.KeyIDKey, `my-key`), `key.Set should succeed`)
+	require.NoError(t, key.Set(jwk.AlgorithmKey, jwa.RS256), `key.Set should succeed`)
+	pubkey, err := jwk.PublicKeyOf(key)
+	require.NoError(t, err, `jwk.PublicKeyOf should succeed`)
+	set := jwk.NewSet()
+	require.NoError(t, set.AddKey(pubkey), `set.AddKey should succeed`)
+
+	var mu sync.Mutex
+	var issuer string
+	hits := make(map[string]int)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case `/.well-known/openid-configuration`:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				`issuer`:                                issuer,
+				`jwks_uri`:                              issuer + `/jwks`,
+				`id_token_signing_alg_values_supported`: []string{`RS256`, `ES256`},
+			})
+		case `/bogus/.well-known/openid-configuration`:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				`issuer`:   `https://attacker.example.com`,
+				`jwks_uri`: issuer + `/jwks`,
+			})
+		case `/jwks`:
+			json.NewEncoder(w).Encode(set)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	issuer = srv.URL
+
+	t.Run("Discover", func(t *testing.T) {
+		md, err := openid.Discover(ctx, issuer)
+		require.NoError(t, err, `openid.Discover should succeed`)
+		require.Equal(t, issuer, md.Issuer)
+		require.Equal(t, issuer+`/jwks`, md.JWKSURI)
+		require.True(t, md.SupportsSigningAlgorithm(jwa.RS256), `RS256 should be supported`)
+		require.False(t, md.SupportsSigningAlgorithm(jwa.HS256), `HS256 should not be supported`)
+	})
+	t.Run("Issuer mismatch", func(t *testing.T) {
+		_, err := openid.Discover(ctx, issuer+`/bogus`)
+		require.Error(t, err, `openid.Discover should fail`)
+	})
+	t.Run("Not found", func(t *testing.T) {
+		_, err := openid.Discover(ctx, issuer, openid.WithAuthorizationServerMetadata(true))
+		require.Error(t, err, `openid.Discover should fail`)
+	})
+	t.Run("Whitelist", func(t *testing.T) {
+		wl := jwk.NewMapWhitelist().Add(`https://example.com/.well-known/openid-configuration`)
+		_, err := openid.Discover(ctx, issuer, openid.WithFetchWhitelist(wl))
+		require.Error(t, err, `openid.Discover should fail`)
+	})
+	t.Run("DiscoveryCache", func(t *testing.T) {
+		dc := openid.NewDiscoveryCache(ctx)
+		_, err := dc.Metadata(ctx, issuer)
+		require.Error(t, err, `dc.Metadata should fail for unregistered issuers`)
+
+		require.NoError(t, dc.Register(issuer, openid.WithHTTPClient(srv.Client())), `dc.Register should succeed`)
+
+		mu.Lock()
+		before := hits[`/.well-known/openid-configuration`]
+		mu.Unlock()
+		for i := 0; i < 3; i++ {
+			md, err := dc.Metadata(ctx, issuer)
+			require.NoError(t, err, `dc.Metadata should succeed`)
+			require.Equal(t, issuer+`/jwks`, md.JWKSURI)
+		}
+		mu.Lock()
+		require.Equal(t, before+1, hits[`/.well-known/openid-configuration`], `metadata should have been fetched once`)
+		mu.Unlock()
+
+		c := jwk.NewCache(ctx)
+		u, err := dc.RegisterJWKS(ctx, c, issuer)
+		require.NoError(t, err, `dc.RegisterJWKS should succeed`)
+		require.Equal(t, issuer+`/jwks`, u)
+		require.True(t, c.IsRegistered(u), `JWKS should be registered`)
+
+		token := jwt.New()
+		require.NoError(t, token.Set(jwt.IssuerKey, issuer), `token.Set should succeed`)
+		signed, err := jwt.Sign(token, jwt.WithKey(jwa.RS256, key))
+		require.NoError(t, err, `jwt.Sign should succeed`)
+
+		_, err = jwt.Parse(signed, jwt.WithCachedKeySet(c, u), jwt.WithIssuer(issuer))
+		require.NoError(t, err, `jwt.Parse should succeed`)
+	})
+	t.Run("DiscoveryCache issuer mismatch", func(t *testing.T) {
+		dc := openid.NewDiscoveryCache(ctx)
+		require.NoError(t, dc.Register(issuer+`/bogus`), `dc.Register should succeed`)
+		_, err := dc.Metadata(ctx, issuer+`/bogus`)
+		require.Error(t, err, `dc.Metadata should fail`)
+	})
+}
@@ -0,0 +1,111 @@
+// Code generated by tools/cmd/genoptions/main.go. DO NOT EDIT.
+
+package openid
+
+import (
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwk"
+	"github.com/lestrrat-go/option"
+)
+
+type Option = option.Interface
+
+// DiscoverOption is a type of Option that can be passed to `openid.Discover()`.
+// DiscoverOption also implements the `RegisterOption`, and thus can
+// safely be passed to `(*openid.DiscoveryCache).Register()`
+type DiscoverOption interface {
+	Option
+	discoverOption()
+	registerOption()
+}
+
+type discoverOption struct {
+	Option
+}
+
+func (*discoverOption) discoverOption() {}
+
+func (*discoverOption) registerOption() {}
+
+// RegisterOption describes options that can be passed to `(*openid.DiscoveryCache).Register()`
+type RegisterOption interface {
+	Option
+	registerOption()
+}
+
+type registerOption struct {
+	Option
+}
+
+func (*registerOption) registerOption() {}
+
+type identAuthorizationServerMetadata struct{}
+type identFetchWhitelist struct{}
+type identHTTPClient struct{}
+type identMinRefreshInterval struct{}
+type identRefreshInterval struct{}
+
+func (identAuthorizationServerMetadata) String() string {
+	return "WithAuthorizationServerMetadata"
+}
+
+func (identFetchWhitelist) String() string {
+	return "WithFetchWhitelist"
+}
+
+func (identHTTPClient) String() string {
+	return "WithHTTPClient"
+}
+
+func (identMinRefreshInterval) String() string {
+	return "WithMinRefreshInterval"
+}
+
+func (identRefreshInterval) String() string {
+	return "WithRefreshInterval"
+}
+
+// WithAuthorizationServerMetadata specifies that the provider metadata
+// should be fetched from the OAuth 2.0 Authorization Server Metadata
+// location defined in RFC8414 (`/.well-known/oauth-authorization-server`
+// inserted between the host and the path of the issuer), instead of
+// the OpenID Connect Discovery location (`/.well-known/openid-configuration`
+// appended to the issuer)
+func WithAuthorizationServerMetadata(v bool) DiscoverOption {
+	return &discoverOption{option.New(identAuthorizationServerMetadata{}, v)}
+}
+
+// WithFetchWhitelist specifies the Whitelist object to use when
+// fetching the provider metadata.
+//
+// When used with `(*openid.DiscoveryCache).Register()`, the same whitelist
+// is applied to the JWKS registered via `(*openid.DiscoveryCache).RegisterJWKS()`
+func WithFetchWhitelist(v jwk.Whitelist) DiscoverOption {
+	return &discoverOption{option.New(identFetchWhitelist{}, v)}
+}
+
+// WithHTTPClient allows users to specify the "net/http".Client object that
+// is used when fetching the provider metadata.
+//
+// When used with `(*openid.DiscoveryCache).Register()`, the same client
+// is used to fetch the JWKS registered via `(*openid.DiscoveryCache).RegisterJWKS()`
+func WithHTTPClient(v jwk.HTTPClient) DiscoverOption {
+	return &discoverOption{option.New(identHTTPClient{}, v)}
+}
+
+// WithMinRefreshInterval specifies the minimum refresh interval of
+// the provider metadata controlled by `openid.DiscoveryCache`.
+//
+// See `jwk.WithMinRefreshInterval()` for details
+func WithMinRefreshInterval(v time.Duration) RegisterOption {
+	return &registerOption{option.New(identMinRefreshInterval{}, v)}
+}
+
+// WithRefreshInterval specifies the static interval between refreshes
+// of the provider metadata controlled by `openid.DiscoveryCache`.
+//
+// See `jwk.WithRefreshInterval()` for details
+func WithRefreshInterval(v time.Duration) RegisterOption {
+	return &registerOption{option.New(identRefreshInterval{}, v)}
+}
@@ -0,0 +1,17 @@
+// Code generated by tools/cmd/genoptions/main.go. DO NOT EDIT.
+
+package openid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionIdent(t *testing.T) {
+	require.Equal(t, "WithAuthorizationServerMetadata", identAuthorizationServerMetadata{}.String())
+	require.Equal(t, "WithFetchWhitelist", identFetchWhitelist{}.String())
+	require.Equal(t, "WithHTTPClient", identHTTPClient{}.String())
+	require.Equal(t, "WithMinRefreshInterval", identMinRefreshInterval{}.String())
+	require.Equal(t, "WithRefreshInterval", identRefreshInterval{}.String())
+}
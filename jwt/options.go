@@ -13,8 +13,10 @@ import (
 
 type identKey struct{}
 type identKeySet struct{}
+type identCachedKeySet struct{}
 type identTypedClaim struct{}
 type identVerifyAuto struct{}
+type identCriticalExtensions struct{}
 
 func toSignOptions(options ...Option) ([]jws.SignOption, error) {
 	var soptions []jws.SignOption
@@ -89,8 +91,20 @@ func toVerifyOptions(options ...Option) ([]jws.VerifyOption, error) {
 			}
 
 			voptions = append(voptions, jws.WithKeySet(wks.set, wkssoptions...))
-		case identVerifyAuto{}:
-			// this one doesn't need conversion. just get the stored option
+		case identCachedKeySet{}:
+			wcks := option.Value().(*withCachedKeySet) // this always succeeds
+			var wkssoptions []jws.WithKeySetSuboption
+			for _, subopt := range wcks.options {
+				wkssopt, ok := subopt.(jws.WithKeySetSuboption)
+				if !ok {
+					return nil, fmt.Errorf(`expected optional arguments in jwt.WithCachedKeySet to be jws.WithKeySetSuboption, but got %T`, subopt)
+				}
+				wkssoptions = append(wkssoptions, wkssopt)
+			}
+
+			voptions = append(voptions, jws.WithCachedKeySet(wcks.cache, wcks.url, wkssoptions...))
+		case identVerifyAuto{}, identCriticalExtensions{}:
+			// these don't need conversion. just get the stored option
 			voptions = append(voptions, option.Value().(jws.VerifyOption))
 		case identKeyProvider{}:
 			kp, ok := option.Value().(jws.KeyProvider)
@@ -164,6 +178,28 @@ func WithKeySet(set jwk.Set, options ...interface{}) ParseOption {
 	})}
 }
 
+type withCachedKeySet struct {
+	cache   *jwk.Cache
+	url     string
+	options []interface{}
+}
+
+// WithCachedKeySet forces the Parse method to verify the JWT message
+// using one of the keys in the JWKS that is stored in the `jwk.Cache`
+// under the URL `u`.
+//
+// This works like `jwt.WithKeySet()`, except that when the token is
+// signed by a key ID that is not found in the JWKS, the JWKS is refreshed
+// (at most once per minimum refresh interval of the URL) and the key is
+// looked up again. See `jws.WithCachedKeySet()` for details.
+func WithCachedKeySet(cache *jwk.Cache, u string, options ...interface{}) ParseOption {
+	return &parseOption{option.New(identCachedKeySet{}, &withCachedKeySet{
+		cache:   cache,
+		url:     u,
+		options: options,
+	})}
+}
+
 // WithIssuer specifies that expected issuer value. If not specified,
 // the value of issuer is not verified at all.
 func WithIssuer(s string) ValidateOption {
@@ -234,6 +270,12 @@ func WithRequiredClaim(name string) ValidateOption {
 	return WithValidator(IsRequired(name))
 }
 
+// WithRequiredScopes specifies that the "scope" claim of the token
+// must grant all of the given scopes. See `jwt.HasScopes()`
+func WithRequiredScopes(scopes ...string) ValidateOption {
+	return WithValidator(HasScopes(scopes...))
+}
+
 // WithMaxDelta specifies that given two claims `c1` and `c2` that represent time, the difference in
 // time.Duration must be less than equal to the value specified by `d`. If `c1` or `c2` is the
 // empty string, the current time (as computed by `time.Now` or the object passed via
@@ -294,3 +336,14 @@ func WithMinDelta(dur time.Duration, c1, c2 string) ValidateOption {
 func WithVerifyAuto(f jwk.Fetcher, options ...jwk.FetchOption) ParseOption {
 	return &parseOption{option.New(identVerifyAuto{}, jws.WithVerifyAuto(f, options...))}
 }
+
+// WithCriticalExtensions specifies the names of the extension header
+// parameters listed in the "crit" header parameter of the JWS message
+// that the caller understands and processes. Messages that list
+// extensions that have not been declared using this option are rejected.
+//
+// This option is passed to `jws.Verify()` as `jws.WithCriticalExtensions()`.
+// It does not provide keys for verification by itself.
+func WithCriticalExtensions(names ...string) ParseOption {
+	return &parseOption{option.New(identCriticalExtensions{}, jws.WithCriticalExtensions(names...))}
+}
@@ -67,6 +67,18 @@ type readFileOption struct {
 
 func (*readFileOption) readFileOption() {}
 
+// ReplayOption is a type of `Option` that can be passed to `jwt.IsNotReplayed()`
+type ReplayOption interface {
+	Option
+	replayOption()
+}
+
+type replayOption struct {
+	Option
+}
+
+func (*replayOption) replayOption() {}
+
 // SignParseOption describes an Option that can be passed to both `jwt.Sign()` or
 // `jwt.Parse()`
 type SignEncryptParseOption interface {
@@ -125,6 +137,8 @@ func (*validateOption) validateOption() {}
 type identAcceptableSkew struct{}
 type identClock struct{}
 type identContext struct{}
+type identCookieKey struct{}
+type identDecryptOption struct{}
 type identEncryptOption struct{}
 type identFS struct{}
 type identFlattenAudience struct{}
@@ -135,10 +149,12 @@ type identNumericDateFormatPrecision struct{}
 type identNumericDateParsePedantic struct{}
 type identNumericDateParsePrecision struct{}
 type identPedantic struct{}
+type identReplayPerIssuer struct{}
 type identSignOption struct{}
 type identToken struct{}
 type identTruncation struct{}
 type identValidate struct{}
+type identValidateAll struct{}
 type identValidator struct{}
 type identVerify struct{}
 
@@ -154,6 +170,14 @@ func (identContext) String() string {
 	return "WithContext"
 }
 
+func (identCookieKey) String() string {
+	return "WithCookieKey"
+}
+
+func (identDecryptOption) String() string {
+	return "WithDecryptOption"
+}
+
 func (identEncryptOption) String() string {
 	return "WithEncryptOption"
 }
@@ -194,6 +218,10 @@ func (identPedantic) String() string {
 	return "WithPedantic"
 }
 
+func (identReplayPerIssuer) String() string {
+	return "WithReplayPerIssuer"
+}
+
 func (identSignOption) String() string {
 	return "WithSignOption"
 }
@@ -210,6 +238,10 @@ func (identValidate) String() string {
 	return "WithValidate"
 }
 
+func (identValidateAll) String() string {
+	return "WithValidateAll"
+}
+
 func (identValidator) String() string {
 	return "WithValidator"
 }
@@ -240,6 +272,23 @@ func WithContext(v context.Context) ValidateOption {
 	return &validateOption{option.New(identContext{}, v)}
 }
 
+// WithCookieKey is used to specify cookie names to search for tokens.
+//
+// While the type system allows this option to be passed to `jwt.Parse()` directly,
+// doing so will have no effect. Only use it for HTTP request parsing functions
+func WithCookieKey(v string) ParseOption {
+	return &parseOption{option.New(identCookieKey{}, v)}
+}
+
+// WithDecryptOption specifies an option to be passed to `jwe.Decrypt()`
+// when `jwt.Parse()` encounters an encrypted (JWE) token, such as
+// the key to decrypt it with (e.g. `jwt.WithDecryptOption(jwe.WithKey(alg, key))`).
+// This option may be specified multiple times. Encrypted tokens
+// cannot be parsed unless this option is specified.
+func WithDecryptOption(v jwe.DecryptOption) ParseOption {
+	return &parseOption{option.New(identDecryptOption{}, v)}
+}
+
 // WithEncryptOption provides an escape hatch for cases where extra options to
 // `(jws.Serializer).Encrypt()` must be specified when usng `jwt.Sign()`. Normally you do not
 // need to use this.
@@ -318,6 +367,13 @@ func WithPedantic(v bool) ParseOption {
 	return &parseOption{option.New(identPedantic{}, v)}
 }
 
+// WithReplayPerIssuer specifies that the "jti" claims should be tracked
+// separately for each issuer ("iss" claim), so that tokens from different
+// issuers that happen to use the same "jti" are not treated as replays.
+func WithReplayPerIssuer(v bool) ReplayOption {
+	return &replayOption{option.New(identReplayPerIssuer{}, v)}
+}
+
 // WithSignOption provides an escape hatch for cases where extra options to
 // `jws.Sign()` must be specified when usng `jwt.Sign()`. Normally you do not
 // need to use this.
@@ -352,6 +408,19 @@ func WithValidate(v bool) ParseOption {
 	return &parseOption{option.New(identValidate{}, v)}
 }
 
+// WithValidateAll specifies that `jwt.Validate()` should run all of the
+// validators, instead of stopping at the first failure.
+//
+// When enabled and one or more validators fail, the returned error
+// can be converted to a `jwt.MultiValidationError` using `errors.As()`,
+// which allows you to enumerate each failure along with the name of
+// the claim that it relates to. The error still works with `errors.Is()`,
+// so checks such as `errors.Is(err, jwt.ErrTokenExpired())` will
+// succeed if any of the failures match.
+func WithValidateAll(v bool) ValidateOption {
+	return &validateOption{option.New(identValidateAll{}, v)}
+}
+
 // WithValidator validates the token with the given Validator.
 //
 // For example, in order to validate tokens that are only valid during August, you would write
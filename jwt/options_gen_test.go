@@ -12,6 +12,8 @@ func TestOptionIdent(t *testing.T) {
 	require.Equal(t, "WithAcceptableSkew", identAcceptableSkew{}.String())
 	require.Equal(t, "WithClock", identClock{}.String())
 	require.Equal(t, "WithContext", identContext{}.String())
+	require.Equal(t, "WithCookieKey", identCookieKey{}.String())
+	require.Equal(t, "WithDecryptOption", identDecryptOption{}.String())
 	require.Equal(t, "WithEncryptOption", identEncryptOption{}.String())
 	require.Equal(t, "WithFS", identFS{}.String())
 	require.Equal(t, "WithFlattenAudience", identFlattenAudience{}.String())
@@ -22,10 +24,12 @@ func TestOptionIdent(t *testing.T) {
 	require.Equal(t, "WithNumericDateParsePedantic", identNumericDateParsePedantic{}.String())
 	require.Equal(t, "WithNumericDateParsePrecision", identNumericDateParsePrecision{}.String())
 	require.Equal(t, "WithPedantic", identPedantic{}.String())
+	require.Equal(t, "WithReplayPerIssuer", identReplayPerIssuer{}.String())
 	require.Equal(t, "WithSignOption", identSignOption{}.String())
 	require.Equal(t, "WithToken", identToken{}.String())
 	require.Equal(t, "WithTruncation", identTruncation{}.String())
 	require.Equal(t, "WithValidate", identValidate{}.String())
+	require.Equal(t, "WithValidateAll", identValidateAll{}.String())
 	require.Equal(t, "WithValidator", identValidator{}.String())
 	require.Equal(t, "WithVerify", identVerify{}.String())
 }
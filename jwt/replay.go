@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReplayCache is used by the Validator created by `jwt.IsNotReplayed()`
+// to remember the "jti" claims of tokens that have already been seen.
+//
+// Implementations must be safe for concurrent use. If the cache is shared
+// among multiple processes (e.g. using an external key-value store), `Add`
+// must be atomic across them.
+type ReplayCache interface {
+	// Add records `key` until `expiresAt`. It returns false if `key`
+	// has already been recorded and has not expired as of `now`.
+	//
+	// `now` is the time according to the `jwt.Clock` used for validation,
+	// and should be used instead of the system time to determine if
+	// the entries have expired.
+	Add(ctx context.Context, key string, now, expiresAt time.Time) (bool, error)
+}
+
+const defaultReplayCacheSize = 10000
+
+// MemoryReplayCache is an in-memory ReplayCache that holds up to a
+// fixed number of entries. Expired entries are purged as new entries
+// are added.
+type MemoryReplayCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*replayEntry
+	queue   replayQueue
+}
+
+type replayEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// NewMemoryReplayCache creates a new MemoryReplayCache that holds up to
+// `size` entries. If `size` is not positive, a default value of 10000
+// is used.
+//
+// When the cache is full and none of the entries have expired, `Add`
+// returns an error instead of evicting entries that are still valid,
+// as doing so would allow the corresponding tokens to be replayed.
+func NewMemoryReplayCache(size int) *MemoryReplayCache {
+	if size <= 0 {
+		size = defaultReplayCacheSize
+	}
+	return &MemoryReplayCache{
+		size:    size,
+		entries: make(map[string]*replayEntry),
+	}
+}
+
+func (c *MemoryReplayCache) Add(_ context.Context, key string, now, expiresAt time.Time) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.purge(now)
+	if _, ok := c.entries[key]; ok {
+		return false, nil
+	}
+
+	if len(c.entries) >= c.size {
+		return false, fmt.Errorf(`replay cache is full (%d entries)`, c.size)
+	}
+
+	entry := &replayEntry{key: key, expiresAt: expiresAt}
+	heap.Push(&c.queue, entry)
+	c.entries[key] = entry
+	return true, nil
+}
+
+// Len returns the number of entries in the cache, including those
+// that have expired but have not been purged yet.
+func (c *MemoryReplayCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *MemoryReplayCache) purge(now time.Time) {
+	for len(c.queue) > 0 && !c.queue[0].expiresAt.After(now) {
+		//nolint:forcetypeassert
+		entry := heap.Pop(&c.queue).(*replayEntry)
+		delete(c.entries, entry.key)
+	}
+}
+
+// replayQueue is a min-heap of entries ordered by their expiration
+type replayQueue []*replayEntry
+
+func (q replayQueue) Len() int { return len(q) }
+
+func (q replayQueue) Less(i, j int) bool {
+	return q[i].expiresAt.Before(q[j].expiresAt)
+}
+
+func (q replayQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *replayQueue) Push(v interface{}) {
+	//nolint:forcetypeassert
+	*q = append(*q, v.(*replayEntry))
+}
+
+func (q *replayQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return entry
+}
+
+type isNotReplayed struct {
+	cache     ReplayCache
+	perIssuer bool
+}
+
+// IsNotReplayed creates a Validator that rejects tokens whose "jti" claim
+// has already been seen, which is useful for one-time tokens such as
+// client assertions, DPoP proofs, and magic links. The "jti" claims are
+// remembered in `cache` until the tokens expire (taking the acceptable
+// skew into account), and therefore the tokens must have both the "jti"
+// and the "exp" claims.
+//
+// Because the "jti" is recorded as soon as this validator is executed,
+// it should be specified after all other validators, so that tokens
+// that fail other validations are not recorded:
+//
+//	cache := jwt.NewMemoryReplayCache(0)
+//	err := jwt.Validate(token,
+//	  jwt.WithIssuer(`https://example.com`),
+//	  jwt.WithValidator(jwt.IsNotReplayed(cache)),
+//	)
+//	if errors.Is(err, jwt.ErrTokenReplayed()) {
+//	  ...
+//	}
+//
+// By default the "jti" claims are tracked globally. Use
+// `jwt.WithReplayPerIssuer(true)` to track them for each issuer.
+func IsNotReplayed(cache ReplayCache, options ...ReplayOption) Validator {
+	var perIssuer bool
+	for _, option := range options {
+		//nolint:forcetypeassert
+		switch option.Ident() {
+		case identReplayPerIssuer{}:
+			perIssuer = option.Value().(bool)
+		}
+	}
+	return &isNotReplayed{
+		cache:     cache,
+		perIssuer: perIssuer,
+	}
+}
+
+func (v *isNotReplayed) Validate(ctx context.Context, t Token) ValidationError {
+	jti := t.JwtID()
+	if jti == "" {
+		return &missingRequiredClaimError{claim: JwtIDKey}
+	}
+
+	exp := t.Expiration()
+	if exp.IsZero() || exp.Unix() == 0 {
+		return &missingRequiredClaimError{claim: ExpirationKey}
+	}
+
+	key := jti
+	if v.perIssuer {
+		// "iss" is length-prefixed so that the keys are unambiguous
+		iss := t.Issuer()
+		key = fmt.Sprintf(`%d:%s:%s`, len(iss), iss, jti)
+	}
+
+	clock := ValidationCtxClock(ctx) // MUST be populated
+	skew := ValidationCtxSkew(ctx)   // MUST be populated
+	now := clock.Now()
+	expiresAt := exp.Add(skew)
+	if !now.Before(expiresAt) {
+		// expired tokens are rejected by IsExpirationValid, and need
+		// not be remembered
+		return nil
+	}
+
+	added, err := v.cache.Add(ctx, key, now, expiresAt)
+	if err != nil {
+		return newClaimValidationError(JwtIDKey, fmt.Errorf(`failed to record "jti": %w`, err))
+	}
+	if !added {
+		return ErrTokenReplayed()
+	}
+	return nil
+}
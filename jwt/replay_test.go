@@ -0,0 +1,113 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsNotReplayed(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	clock := jwt.ClockFunc(func() time.Time { return now })
+
+	newToken := func(t *testing.T, iss, jti string, exp time.Time) jwt.Token {
+		t.Helper()
+		builder := jwt.NewBuilder().Issuer(iss).JwtID(jti)
+		if !exp.IsZero() {
+			builder = builder.Expiration(exp)
+		}
+		tok, err := builder.Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+		return tok
+	}
+
+	t.Run("Replay", func(t *testing.T) {
+		v := jwt.IsNotReplayed(jwt.NewMemoryReplayCache(0))
+		tok := newToken(t, `iss1`, `jti1`, now.Add(time.Minute))
+		require.NoError(t, jwt.Validate(tok, jwt.WithClock(clock), jwt.WithValidator(v)), `first use should succeed`)
+
+		err := jwt.Validate(tok, jwt.WithClock(clock), jwt.WithValidator(v))
+		require.Error(t, err, `second use should fail`)
+		require.True(t, errors.Is(err, jwt.ErrTokenReplayed()), `error should be ErrTokenReplayed`)
+		require.True(t, jwt.IsValidationError(err), `error should be a validation error`)
+
+		// Different jti
+		require.NoError(t, jwt.Validate(newToken(t, `iss1`, `jti2`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+		// Same jti, different issuer
+		require.Error(t, jwt.Validate(newToken(t, `iss2`, `jti1`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+	})
+	t.Run("Per issuer", func(t *testing.T) {
+		v := jwt.IsNotReplayed(jwt.NewMemoryReplayCache(0), jwt.WithReplayPerIssuer(true))
+		require.NoError(t, jwt.Validate(newToken(t, `iss1`, `jti1`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+		require.NoError(t, jwt.Validate(newToken(t, `iss2`, `jti1`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+		require.Error(t, jwt.Validate(newToken(t, `iss2`, `jti1`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+	})
+	t.Run("Missing claims", func(t *testing.T) {
+		v := jwt.IsNotReplayed(jwt.NewMemoryReplayCache(0))
+		err := jwt.Validate(newToken(t, `iss1`, ``, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v))
+		require.True(t, errors.Is(err, jwt.ErrRequiredClaim()), `missing "jti" should be rejected`)
+
+		err = jwt.Validate(newToken(t, `iss1`, `jti1`, time.Time{}), jwt.WithClock(clock), jwt.WithValidator(v))
+		require.True(t, errors.Is(err, jwt.ErrRequiredClaim()), `missing "exp" should be rejected`)
+	})
+	t.Run("Expiration", func(t *testing.T) {
+		c := jwt.NewMemoryReplayCache(0)
+		v := jwt.IsNotReplayed(c)
+		tok := newToken(t, `iss1`, `jti1`, now.Add(time.Minute))
+		require.NoError(t, jwt.Validate(tok, jwt.WithClock(clock), jwt.WithAcceptableSkew(time.Minute), jwt.WithValidator(v)))
+		require.Equal(t, 1, c.Len())
+
+		// The entry is retained until "exp" + skew
+		later := jwt.ClockFunc(func() time.Time { return now.Add(90 * time.Second) })
+		require.Error(t, jwt.Validate(tok, jwt.WithClock(later), jwt.WithAcceptableSkew(time.Minute), jwt.WithValidator(v)))
+
+		// Once expired, the token is rejected by "exp" alone, and
+		// is not recorded again
+		later = jwt.ClockFunc(func() time.Time { return now.Add(3 * time.Minute) })
+		err := jwt.Validate(tok, jwt.WithClock(later), jwt.WithAcceptableSkew(time.Minute), jwt.WithValidator(v))
+		require.True(t, errors.Is(err, jwt.ErrTokenExpired()), `error should be ErrTokenExpired`)
+		require.False(t, errors.Is(err, jwt.ErrTokenReplayed()), `error should not be ErrTokenReplayed`)
+
+		// Expired entries are purged when new entries are added
+		require.NoError(t, jwt.Validate(newToken(t, `iss1`, `jti2`, now.Add(time.Hour)), jwt.WithClock(later), jwt.WithValidator(v)))
+		require.Equal(t, 1, c.Len())
+	})
+	t.Run("Full cache", func(t *testing.T) {
+		c := jwt.NewMemoryReplayCache(2)
+		v := jwt.IsNotReplayed(c)
+		for i := 0; i < 2; i++ {
+			require.NoError(t, jwt.Validate(newToken(t, `iss1`, fmt.Sprintf(`jti%d`, i), now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v)))
+		}
+		err := jwt.Validate(newToken(t, `iss1`, `jti2`, now.Add(time.Minute)), jwt.WithClock(clock), jwt.WithValidator(v))
+		require.Error(t, err, `jwt.Validate should fail when the cache is full`)
+		require.False(t, errors.Is(err, jwt.ErrTokenReplayed()), `error should not be ErrTokenReplayed`)
+	})
+	t.Run("Concurrency", func(t *testing.T) {
+		v := jwt.IsNotReplayed(jwt.NewMemoryReplayCache(0))
+		tok := newToken(t, `iss1`, `jti1`, now.Add(time.Minute))
+		ctx := jwt.SetValidationCtxSkew(jwt.SetValidationCtxClock(context.Background(), clock), 0)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var accepted int
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := v.Validate(ctx, tok); err == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, 1, accepted, `exactly one use should be accepted`)
+	})
+}
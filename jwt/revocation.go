@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RevocationStore is used by the Validator created by `jwt.IsNotRevoked()`
+// to determine if a token has been revoked before its expiration.
+//
+// Implementations must be safe for concurrent use.
+type RevocationStore interface {
+	// IsRevoked returns true if `t` has been revoked. If an error is
+	// returned, the token is rejected.
+	IsRevoked(ctx context.Context, t Token) (bool, error)
+}
+
+// MemoryRevocationStore is an in-memory RevocationStore. Tokens can be
+// revoked by their "jti" claim, by their "sub" claim, or by their "sub"
+// claim combined with the time they were issued.
+//
+// Revocations by "jti" are kept until the time given to `RevokeJwtID`,
+// which is usually the expiration of the token. Expired entries are
+// purged as new entries are added. When called from `jwt.Validate()`,
+// `IsRevoked` determines if a revocation has expired using the
+// `jwt.Clock` used for validation (see `jwt.WithClock()`).
+type MemoryRevocationStore struct {
+	mu             sync.RWMutex
+	jwtIDs         map[string]*replayEntry
+	queue          replayQueue
+	subjects       map[string]struct{}
+	subjectsBefore map[string]time.Time
+}
+
+// NewMemoryRevocationStore creates a new empty MemoryRevocationStore.
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		jwtIDs:         make(map[string]*replayEntry),
+		subjects:       make(map[string]struct{}),
+		subjectsBefore: make(map[string]time.Time),
+	}
+}
+
+// RevokeJwtID revokes the token whose "jti" claim is `jti` until
+// `expiresAt`. As tokens are rejected once they expire, `expiresAt`
+// should be set to the token's "exp" claim plus the acceptable skew.
+// If `expiresAt` is the zero value, the revocation never expires.
+//
+// If called multiple times for the same "jti", the latest `expiresAt`
+// is used.
+func (s *MemoryRevocationStore) RevokeJwtID(jti string, expiresAt time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.purge(time.Now())
+	if cur, ok := s.jwtIDs[jti]; ok {
+		// keep the revocation that lasts longer
+		if cur.expiresAt.IsZero() || (!expiresAt.IsZero() && !cur.expiresAt.Before(expiresAt)) {
+			return
+		}
+	}
+
+	entry := &replayEntry{key: jti, expiresAt: expiresAt}
+	if !expiresAt.IsZero() {
+		heap.Push(&s.queue, entry)
+	}
+	s.jwtIDs[jti] = entry
+}
+
+// UnrevokeJwtID removes the revocation of the token whose "jti" claim
+// is `jti`.
+func (s *MemoryRevocationStore) UnrevokeJwtID(jti string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jwtIDs, jti)
+}
+
+// RevokeSubject revokes all tokens whose "sub" claim is `sub`,
+// regardless of when they were issued.
+func (s *MemoryRevocationStore) RevokeSubject(sub string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subjects[sub] = struct{}{}
+}
+
+// RevokeSubjectBefore revokes all tokens whose "sub" claim is `sub`
+// and were issued before `t`, such as when a user logs out of all
+// sessions. Tokens without the "iat" claim are considered to have
+// been issued before `t`.
+//
+// If called multiple times for the same subject, the latest `t` is used.
+func (s *MemoryRevocationStore) RevokeSubjectBefore(sub string, t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur, ok := s.subjectsBefore[sub]; ok && cur.After(t) {
+		return
+	}
+	s.subjectsBefore[sub] = t
+}
+
+// UnrevokeSubject removes the revocations made using `RevokeSubject`
+// and `RevokeSubjectBefore` for `sub`.
+func (s *MemoryRevocationStore) UnrevokeSubject(sub string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subjects, sub)
+	delete(s.subjectsBefore, sub)
+}
+
+// Len returns the number of revoked "jti" and "sub" claims, including
+// those that have expired but have not been purged yet.
+func (s *MemoryRevocationStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.jwtIDs) + len(s.subjects) + len(s.subjectsBefore)
+}
+
+func (s *MemoryRevocationStore) purge(now time.Time) {
+	for len(s.queue) > 0 && !s.queue[0].expiresAt.After(now) {
+		//nolint:forcetypeassert
+		entry := heap.Pop(&s.queue).(*replayEntry)
+		// the entry may have been replaced or removed since it was queued
+		if s.jwtIDs[entry.key] == entry {
+			delete(s.jwtIDs, entry.key)
+		}
+	}
+}
+
+func (s *MemoryRevocationStore) IsRevoked(ctx context.Context, t Token) (bool, error) {
+	// IsRevoked may be called outside of jwt.Validate(), in which
+	// case the context does not carry a clock
+	now := time.Now()
+	if clock, ok := ctx.Value(identValidationCtxClock{}).(Clock); ok {
+		now = clock.Now()
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if jti := t.JwtID(); jti != "" {
+		if entry, ok := s.jwtIDs[jti]; ok && (entry.expiresAt.IsZero() || entry.expiresAt.After(now)) {
+			return true, nil
+		}
+	}
+
+	sub := t.Subject()
+	if sub == "" {
+		return false, nil
+	}
+
+	if _, ok := s.subjects[sub]; ok {
+		return true, nil
+	}
+
+	if before, ok := s.subjectsBefore[sub]; ok {
+		iat := t.IssuedAt()
+		if iat.IsZero() || iat.Before(before) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type isNotRevoked struct {
+	store RevocationStore
+}
+
+// IsNotRevoked creates a Validator that rejects tokens that have been
+// revoked according to `store`. The error returned for revoked tokens
+// can be identified using `jwt.ErrTokenRevoked()`.
+//
+//	store := jwt.NewMemoryRevocationStore()
+//	store.RevokeJwtID(`...`, exp)
+//
+//	tok, err := jwt.Parse(src,
+//	  jwt.WithKey(alg, key),
+//	  jwt.WithValidator(jwt.IsNotRevoked(store)),
+//	)
+//	if errors.Is(err, jwt.ErrTokenRevoked()) {
+//	  ...
+//	}
+func IsNotRevoked(store RevocationStore) Validator {
+	return &isNotRevoked{store: store}
+}
+
+func (v *isNotRevoked) Validate(ctx context.Context, t Token) ValidationError {
+	revoked, err := v.store.IsRevoked(ctx, t)
+	if err != nil {
+		return NewValidationError(fmt.Errorf(`failed to check if token has been revoked: %w`, err))
+	}
+	if revoked {
+		return ErrTokenRevoked()
+	}
+	return nil
+}
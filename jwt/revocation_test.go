@@ -0,0 +1,147 @@
+package jwt_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lestrrat-go/jwx/v2/internal/jwxtest"
+	"github.com/lestrrat-go/jwx/v2/jwa"
+	"github.com/lestrrat-go/jwx/v2/jwt"
+	"github.com/stretchr/testify/require"
+)
+
+type failingRevocationStore struct{}
+
+func (failingRevocationStore) IsRevoked(context.Context, jwt.Token) (bool, error) {
+	return false, fmt.Errorf(`store unavailable`)
+}
+
+func TestIsNotRevoked(t *testing.T) {
+	key, err := jwxtest.GenerateSymmetricJwk()
+	require.NoError(t, err, `jwxtest.GenerateSymmetricJwk should succeed`)
+
+	now := time.Now().Truncate(time.Second)
+	sign := func(t *testing.T, sub, jti string, iat time.Time) []byte {
+		t.Helper()
+		builder := jwt.NewBuilder().Subject(sub).JwtID(jti)
+		if !iat.IsZero() {
+			builder = builder.IssuedAt(iat)
+		}
+		tok, err := builder.Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+		signed, err := jwt.Sign(tok, jwt.WithKey(jwa.HS256, key))
+		require.NoError(t, err, `jwt.Sign should succeed`)
+		return signed
+	}
+
+	store := jwt.NewMemoryRevocationStore()
+	store.RevokeJwtID(`revoked-jti`, now.Add(time.Hour))
+	store.RevokeSubject(`revoked-sub`)
+	store.RevokeSubjectBefore(`logged-out-sub`, now)
+
+	testcases := []struct {
+		Name     string
+		Sub      string
+		JwtID    string
+		IssuedAt time.Time
+		Revoked  bool
+	}{
+		{Name: "Not revoked", Sub: `sub`, JwtID: `jti`, IssuedAt: now},
+		{Name: "Revoked jti", Sub: `sub`, JwtID: `revoked-jti`, IssuedAt: now, Revoked: true},
+		{Name: "Revoked sub", Sub: `revoked-sub`, JwtID: `jti`, IssuedAt: now, Revoked: true},
+		{Name: "Issued before revocation", Sub: `logged-out-sub`, JwtID: `jti`, IssuedAt: now.Add(-time.Minute), Revoked: true},
+		{Name: "Issued after revocation", Sub: `logged-out-sub`, JwtID: `jti`, IssuedAt: now.Add(time.Minute)},
+		{Name: "Missing iat", Sub: `logged-out-sub`, JwtID: `jti`, Revoked: true},
+	}
+
+	for _, tc := range testcases {
+		tc := tc
+		t.Run(tc.Name, func(t *testing.T) {
+			signed := sign(t, tc.Sub, tc.JwtID, tc.IssuedAt)
+			_, err := jwt.Parse(signed, jwt.WithKey(jwa.HS256, key), jwt.WithAcceptableSkew(2*time.Minute), jwt.WithValidator(jwt.IsNotRevoked(store)))
+			if !tc.Revoked {
+				require.NoError(t, err, `jwt.Parse should succeed`)
+				return
+			}
+			require.Error(t, err, `jwt.Parse should fail`)
+			require.True(t, errors.Is(err, jwt.ErrTokenRevoked()), `error should be ErrTokenRevoked`)
+			require.True(t, jwt.IsValidationError(err), `error should be a validation error`)
+		})
+	}
+
+	t.Run("Later RevokeSubjectBefore", func(t *testing.T) {
+		s := jwt.NewMemoryRevocationStore()
+		s.RevokeSubjectBefore(`sub`, now)
+		s.RevokeSubjectBefore(`sub`, now.Add(-time.Hour))
+
+		tok, err := jwt.NewBuilder().Subject(`sub`).IssuedAt(now.Add(-time.Minute)).Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+		err = jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(s)))
+		require.True(t, errors.Is(err, jwt.ErrTokenRevoked()), `the latest revocation time should be used`)
+	})
+	t.Run("Expired revocation", func(t *testing.T) {
+		s := jwt.NewMemoryRevocationStore()
+		s.RevokeJwtID(`forever`, time.Time{})
+		s.RevokeJwtID(`expired`, time.Now().Add(-time.Second))
+		require.Equal(t, 2, s.Len(), `expired entries are not purged until new ones are added`)
+
+		tok, err := jwt.NewBuilder().JwtID(`expired`).Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+		require.NoError(t, jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(s))), `expired revocation should be ignored`)
+
+		// revocations that never expire are not shortened
+		s.RevokeJwtID(`forever`, time.Now().Add(-time.Second))
+		require.Equal(t, 1, s.Len(), `expired entries should be purged`)
+
+		s.RevokeJwtID(`active`, time.Now().Add(time.Hour))
+		require.Equal(t, 2, s.Len(), `active entries should be kept`)
+
+		for _, jti := range []string{`forever`, `active`} {
+			tok, err := jwt.NewBuilder().JwtID(jti).Build()
+			require.NoError(t, err, `builder.Build should succeed`)
+			err = jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(s)))
+			require.True(t, errors.Is(err, jwt.ErrTokenRevoked()), `%q should be revoked`, jti)
+		}
+	})
+	t.Run("Clock", func(t *testing.T) {
+		s := jwt.NewMemoryRevocationStore()
+		s.RevokeJwtID(`active`, time.Now().Add(time.Hour))
+
+		tok, err := jwt.NewBuilder().JwtID(`active`).Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+
+		clock := jwt.ClockFunc(func() time.Time { return time.Now().Add(2 * time.Hour) })
+		require.NoError(t, jwt.Validate(tok, jwt.WithClock(clock), jwt.WithValidator(jwt.IsNotRevoked(s))), `revocation should be expired according to the clock`)
+
+		revoked, err := s.IsRevoked(context.Background(), tok)
+		require.NoError(t, err, `s.IsRevoked should succeed`)
+		require.True(t, revoked, `revocation should be active according to the system time`)
+	})
+	t.Run("Unrevoke", func(t *testing.T) {
+		s := jwt.NewMemoryRevocationStore()
+		s.RevokeJwtID(`jti`, now.Add(time.Hour))
+		s.RevokeSubject(`sub`)
+		s.RevokeSubjectBefore(`sub`, now)
+
+		tok, err := jwt.NewBuilder().Subject(`sub`).JwtID(`jti`).IssuedAt(now.Add(-time.Minute)).Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+
+		s.UnrevokeJwtID(`jti`)
+		err = jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(s)))
+		require.True(t, errors.Is(err, jwt.ErrTokenRevoked()), `token should still be revoked by "sub"`)
+
+		s.UnrevokeSubject(`sub`)
+		require.NoError(t, jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(s))), `token should not be revoked`)
+		require.Equal(t, 0, s.Len(), `store should be empty`)
+	})
+	t.Run("Store error", func(t *testing.T) {
+		tok, err := jwt.NewBuilder().Subject(`sub`).Build()
+		require.NoError(t, err, `builder.Build should succeed`)
+		err = jwt.Validate(tok, jwt.WithValidator(jwt.IsNotRevoked(failingRevocationStore{})))
+		require.Error(t, err, `jwt.Validate should fail`)
+		require.False(t, errors.Is(err, jwt.ErrTokenRevoked()), `error should not be ErrTokenRevoked`)
+	})
+}
@@ -2,8 +2,10 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -48,6 +50,7 @@ func Validate(t Token, options ...ValidateOption) error {
 
 	var clock Clock = ClockFunc(time.Now)
 	var skew time.Duration
+	var validateAll bool
 	var validators = []Validator{
 		IsIssuedAtValid(),
 		IsExpirationValid(),
@@ -64,6 +67,8 @@ func Validate(t Token, options ...ValidateOption) error {
 			trunc = o.Value().(time.Duration)
 		case identContext{}:
 			ctx = o.Value().(context.Context)
+		case identValidateAll{}:
+			validateAll = o.Value().(bool)
 		case identValidator{}:
 			v := o.Value().(Validator)
 			switch v := v.(type) {
@@ -88,15 +93,97 @@ func Validate(t Token, options ...ValidateOption) error {
 	ctx = SetValidationCtxSkew(ctx, skew)
 	ctx = SetValidationCtxClock(ctx, clock)
 	ctx = SetValidationCtxTruncation(ctx, trunc)
+	var failures []ValidationFailure
 	for _, v := range validators {
 		if err := v.Validate(ctx, t); err != nil {
-			return err
+			if !validateAll {
+				return err
+			}
+			failures = append(failures, ValidationFailure{
+				Claim: claimOf(err),
+				Err:   err,
+			})
 		}
 	}
 
+	if len(failures) > 0 {
+		return &multiValidationError{failures: failures}
+	}
 	return nil
 }
 
+// ValidationFailure describes a single failure reported by `jwt.Validate()`
+// when `jwt.WithValidateAll(true)` is specified.
+type ValidationFailure struct {
+	// Claim is the name of the claim that the failure relates to. It is
+	// empty if the failure could not be attributed to a specific claim,
+	// such as when a custom validator returned an error created via
+	// `jwt.NewValidationError()`
+	Claim string
+	// Err is the error reported by the validator
+	Err ValidationError
+}
+
+// MultiValidationError is the error returned from `jwt.Validate()` when
+// `jwt.WithValidateAll(true)` is specified and one or more validators
+// have failed.
+type MultiValidationError interface {
+	ValidationError
+	// Failures returns the list of failures, in the order that the
+	// validators were executed
+	Failures() []ValidationFailure
+}
+
+type multiValidationError struct {
+	failures []ValidationFailure
+}
+
+func (*multiValidationError) isValidationError() {}
+func (*multiValidationError) Unwrap() error      { return nil }
+
+func (err *multiValidationError) Failures() []ValidationFailure {
+	return err.failures
+}
+
+func (err *multiValidationError) Error() string {
+	var sb strings.Builder
+	for i, f := range err.failures {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(f.Err.Error())
+	}
+	return sb.String()
+}
+
+// Is returns true if any of the individual failures match target
+func (err *multiValidationError) Is(target error) bool {
+	for _, f := range err.failures {
+		if errors.Is(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first individual failure that matches target
+func (err *multiValidationError) As(target interface{}) bool {
+	for _, f := range err.failures {
+		if errors.As(f.Err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// claimOf returns the name of the claim that err relates to, if known
+func claimOf(err ValidationError) string {
+	if v, ok := err.(interface{ claimName() string }); ok {
+		return v.claimName()
+	}
+	return ""
+}
+
 type isInTimeRange struct {
 	c1   string
 	c2   string
@@ -134,16 +221,25 @@ func (iitr *isInTimeRange) Validate(ctx context.Context, t Token) ValidationErro
 	if iitr.less { // t1 - t2 <= iitr.dur
 		// t1 - t2 < iitr.dur + skew
 		if t1.Sub(t2) > iitr.dur+skew {
-			return NewValidationError(fmt.Errorf(`iitr between %s and %s exceeds %s (skew %s)`, iitr.c1, iitr.c2, iitr.dur, skew))
+			return newClaimValidationError(iitr.claim(), fmt.Errorf(`iitr between %s and %s exceeds %s (skew %s)`, iitr.c1, iitr.c2, iitr.dur, skew))
 		}
 	} else {
 		if t1.Sub(t2) < iitr.dur-skew {
-			return NewValidationError(fmt.Errorf(`iitr between %s and %s is less than %s (skew %s)`, iitr.c1, iitr.c2, iitr.dur, skew))
+			return newClaimValidationError(iitr.claim(), fmt.Errorf(`iitr between %s and %s is less than %s (skew %s)`, iitr.c1, iitr.c2, iitr.dur, skew))
 		}
 	}
 	return nil
 }
 
+// claim returns the claim that is being compared against. If c1 is
+// the current time, c2 is returned instead
+func (iitr *isInTimeRange) claim() string {
+	if iitr.c1 != "" {
+		return iitr.c1
+	}
+	return iitr.c2
+}
+
 type ValidationError interface {
 	error
 	isValidationError()
@@ -154,12 +250,20 @@ func NewValidationError(err error) ValidationError {
 	return &validationError{error: err}
 }
 
+func newClaimValidationError(claim string, err error) ValidationError {
+	return &validationError{error: err, claim: claim}
+}
+
 // This is a generic validation error.
 type validationError struct {
 	error
+	claim string
 }
 
 func (validationError) isValidationError() {}
+func (err *validationError) claimName() string {
+	return err.claim
+}
 func (err *validationError) Unwrap() error {
 	return err.error
 }
@@ -178,6 +282,7 @@ func (err *missingRequiredClaimError) Is(target error) bool {
 }
 
 func (err *missingRequiredClaimError) isValidationError() {}
+func (err *missingRequiredClaimError) claimName() string  { return err.claim }
 func (*missingRequiredClaimError) Unwrap() error          { return nil }
 
 type invalidAudienceError struct {
@@ -190,6 +295,7 @@ func (err *invalidAudienceError) Is(target error) bool {
 }
 
 func (err *invalidAudienceError) isValidationError() {}
+func (err *invalidAudienceError) claimName() string  { return AudienceKey }
 func (err *invalidAudienceError) Unwrap() error {
 	return err.error
 }
@@ -211,6 +317,7 @@ func (err *invalidIssuerError) Is(target error) bool {
 }
 
 func (err *invalidIssuerError) isValidationError() {}
+func (err *invalidIssuerError) claimName() string  { return IssuerKey }
 func (err *invalidIssuerError) Unwrap() error {
 	return err.error
 }
@@ -222,12 +329,37 @@ func (err *invalidIssuerError) Error() string {
 	return err.error.Error()
 }
 
-var errTokenExpired = NewValidationError(fmt.Errorf(`"exp" not satisfied`))
-var errInvalidIssuedAt = NewValidationError(fmt.Errorf(`"iat" not satisfied`))
-var errTokenNotYetValid = NewValidationError(fmt.Errorf(`"nbf" not satisfied`))
+type insufficientScopeError struct {
+	error
+}
+
+func (err *insufficientScopeError) Is(target error) bool {
+	_, ok := target.(*insufficientScopeError)
+	return ok
+}
+
+func (err *insufficientScopeError) isValidationError() {}
+func (err *insufficientScopeError) claimName() string  { return scopeClaim }
+func (err *insufficientScopeError) Unwrap() error {
+	return err.error
+}
+
+func (err *insufficientScopeError) Error() string {
+	if err.error == nil {
+		return `"scope" not satisfied`
+	}
+	return err.error.Error()
+}
+
+var errTokenExpired = newClaimValidationError(ExpirationKey, fmt.Errorf(`"exp" not satisfied`))
+var errInvalidIssuedAt = newClaimValidationError(IssuedAtKey, fmt.Errorf(`"iat" not satisfied`))
+var errTokenNotYetValid = newClaimValidationError(NotBeforeKey, fmt.Errorf(`"nbf" not satisfied`))
 var errInvalidAudience = &invalidAudienceError{}
 var errInvalidIssuer = &invalidIssuerError{}
 var errRequiredClaim = &missingRequiredClaimError{}
+var errInsufficientScope = &insufficientScopeError{}
+var errTokenReplayed = newClaimValidationError(JwtIDKey, fmt.Errorf(`"jti" has already been used`))
+var errTokenRevoked = NewValidationError(fmt.Errorf(`token has been revoked`))
 
 // ErrTokenExpired returns the immutable error used when `exp` claim
 // is not satisfied.
@@ -288,6 +420,32 @@ func ErrRequiredClaim() ValidationError {
 	return errRequiredClaim
 }
 
+// ErrInsufficientScope returns the immutable error used when the
+// scopes specified by `jwt.HasScopes()` are not granted by the token.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrInsufficientScope() ValidationError {
+	return errInsufficientScope
+}
+
+// ErrTokenReplayed returns the immutable error used when the `jti`
+// claim has already been seen by the validator created by
+// `jwt.IsNotReplayed()`.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrTokenReplayed() ValidationError {
+	return errTokenReplayed
+}
+
+// ErrTokenRevoked returns the immutable error used when the token
+// has been revoked according to the `jwt.RevocationStore` passed to
+// `jwt.IsNotRevoked()`.
+//
+// The return value should only be used for comparison using `errors.Is()`
+func ErrTokenRevoked() ValidationError {
+	return errTokenRevoked
+}
+
 // Validator describes interface to validate a Token.
 type Validator interface {
 	// Validate should return an error if a required conditions is not met.
@@ -444,18 +602,18 @@ func ClaimContainsString(name, value string) Validator {
 	return claimContainsString{
 		name:    name,
 		value:   value,
-		makeErr: NewValidationError,
+		makeErr: makeClaimValidationError(name),
 	}
 }
 
 // IsValidationError returns true if the error is a validation error
 func IsValidationError(err error) bool {
 	switch err {
-	case errTokenExpired, errTokenNotYetValid, errInvalidIssuedAt:
+	case errTokenExpired, errTokenNotYetValid, errInvalidIssuedAt, errTokenReplayed, errTokenRevoked:
 		return true
 	default:
 		switch err.(type) {
-		case *validationError, *invalidAudienceError, *invalidIssuerError, *missingRequiredClaimError:
+		case *validationError, *invalidAudienceError, *invalidIssuerError, *missingRequiredClaimError, *insufficientScopeError, *multiValidationError:
 			return true
 		default:
 			return false
@@ -482,6 +640,12 @@ func (ccs claimContainsString) Validate(_ context.Context, t Token) ValidationEr
 	return ccs.makeErr(fmt.Errorf(`%q not satisfied`, ccs.name))
 }
 
+func makeClaimValidationError(name string) func(error) ValidationError {
+	return func(err error) ValidationError {
+		return newClaimValidationError(name, err)
+	}
+}
+
 func makeInvalidAudienceError(err error) ValidationError {
 	return &invalidAudienceError{error: err}
 }
@@ -510,7 +674,7 @@ func ClaimValueIs(name string, value interface{}) Validator {
 	return &claimValueIs{
 		name:    name,
 		value:   value,
-		makeErr: NewValidationError,
+		makeErr: makeClaimValidationError(name),
 	}
 }
 
@@ -555,3 +719,53 @@ func (ir isRequired) Validate(_ context.Context, t Token) ValidationError {
 	}
 	return nil
 }
+
+const scopeClaim = "scope"
+
+// HasScopes creates a Validator that checks if the "scope" claim of the
+// token grants all of the given scopes. The claim may either be a
+// space-delimited string as defined in RFC8693, or a list of strings.
+func HasScopes(scopes ...string) Validator {
+	return hasScopes(scopes)
+}
+
+type hasScopes []string
+
+func (hs hasScopes) Validate(_ context.Context, t Token) ValidationError {
+	v, ok := t.Get(scopeClaim)
+	if !ok {
+		return &insufficientScopeError{error: fmt.Errorf(`%q not satisfied: claim %q does not exist`, scopeClaim, scopeClaim)}
+	}
+
+	var granted []string
+	switch v := v.(type) {
+	case string:
+		granted = strings.Fields(v)
+	case []string:
+		granted = v
+	case []interface{}:
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return &insufficientScopeError{error: fmt.Errorf(`%q not satisfied: invalid list element type %T`, scopeClaim, e)}
+			}
+			granted = append(granted, s)
+		}
+	default:
+		return &insufficientScopeError{error: fmt.Errorf(`%q not satisfied: invalid claim type %T`, scopeClaim, v)}
+	}
+
+	for _, scope := range hs {
+		var found bool
+		for _, g := range granted {
+			if g == scope {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return &insufficientScopeError{error: fmt.Errorf(`%q not satisfied: scope %q is not granted`, scopeClaim, scope)}
+		}
+	}
+	return nil
+}
@@ -567,3 +567,68 @@ func TestClaimValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAll(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	tok := jwt.New()
+	require.NoError(t, tok.Set(jwt.ExpirationKey, now.Add(-time.Hour)), `tok.Set should succeed`)
+	require.NoError(t, tok.Set(jwt.IssuerKey, "github.com/lestrrat-go/jwx"), `tok.Set should succeed`)
+	require.NoError(t, tok.Set(jwt.AudienceKey, []string{"foo"}), `tok.Set should succeed`)
+
+	options := []jwt.ValidateOption{
+		jwt.WithIssuer("github.com/lestrrat-go/jwx/v2"),
+		jwt.WithAudience("bar"),
+		jwt.WithRequiredClaim("custom"),
+		jwt.WithClaimValue("private", "value"),
+	}
+
+	t.Run("Stop at first failure", func(t *testing.T) {
+		t.Parallel()
+		err := jwt.Validate(tok, options...)
+		require.Error(t, err, `jwt.Validate should fail`)
+		require.True(t, errors.Is(err, jwt.ErrTokenExpired()), `error should be ErrTokenExpired`)
+		require.False(t, errors.Is(err, jwt.ErrInvalidIssuer()), `error should not be ErrInvalidIssuer`)
+
+		var merr jwt.MultiValidationError
+		require.False(t, errors.As(err, &merr), `error should not be a MultiValidationError`)
+	})
+	t.Run("Validate all", func(t *testing.T) {
+		t.Parallel()
+		err := jwt.Validate(tok, append(options, jwt.WithValidateAll(true))...)
+		require.Error(t, err, `jwt.Validate should fail`)
+		require.True(t, jwt.IsValidationError(err), `error should be a validation error`)
+		require.True(t, errors.Is(err, jwt.ErrTokenExpired()), `error should be ErrTokenExpired`)
+		require.True(t, errors.Is(err, jwt.ErrInvalidIssuer()), `error should be ErrInvalidIssuer`)
+		require.True(t, errors.Is(err, jwt.ErrInvalidAudience()), `error should be ErrInvalidAudience`)
+		require.True(t, errors.Is(err, jwt.ErrRequiredClaim()), `error should be ErrRequiredClaim`)
+		require.False(t, errors.Is(err, jwt.ErrTokenNotYetValid()), `error should not be ErrTokenNotYetValid`)
+
+		var merr jwt.MultiValidationError
+		require.True(t, errors.As(err, &merr), `error should be a MultiValidationError`)
+
+		var claims []string
+		for _, f := range merr.Failures() {
+			require.Error(t, f.Err, `each failure should have an error`)
+			claims = append(claims, f.Claim)
+		}
+		require.Equal(t, []string{jwt.ExpirationKey, jwt.IssuerKey, jwt.AudienceKey, "custom", "private"}, claims, `claims should match`)
+	})
+	t.Run("Validate all with no failures", func(t *testing.T) {
+		t.Parallel()
+		err := jwt.Validate(tok, jwt.WithValidateAll(true), jwt.WithClock(jwt.ClockFunc(func() time.Time { return now.Add(-2 * time.Hour) })))
+		require.NoError(t, err, `jwt.Validate should succeed`)
+	})
+	t.Run("Through jwt.Parse", func(t *testing.T) {
+		t.Parallel()
+		buf, err := json.Marshal(tok)
+		require.NoError(t, err, `json.Marshal should succeed`)
+
+		_, err = jwt.Parse(buf, jwt.WithVerify(false), jwt.WithValidateAll(true), jwt.WithIssuer("bogus"))
+		require.Error(t, err, `jwt.Parse should fail`)
+
+		var merr jwt.MultiValidationError
+		require.True(t, errors.As(err, &merr), `error should be a MultiValidationError`)
+		require.Len(t, merr.Failures(), 2, `there should be two failures`)
+	})
+}
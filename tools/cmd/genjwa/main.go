@@ -73,6 +73,16 @@ func _main() error {
 					value:   `A256GCM`,
 					comment: `AES-GCM (256)`,
 				},
+				{
+					name:    `C20P`,
+					value:   `C20P`,
+					comment: `ChaCha20-Poly1305`,
+				},
+				{
+					name:    `XC20P`,
+					value:   `XC20P`,
+					comment: `XChaCha20-Poly1305`,
+				},
 			},
 		},
 		{
@@ -289,6 +299,26 @@ func _main() error {
 					value:   "ECDH-ES+A256KW",
 					comment: `ECDH-ES + AES key wrap (256)`,
 				},
+				{
+					name:    `ECDH_1PU`,
+					value:   "ECDH-1PU",
+					comment: `ECDH-1PU`,
+				},
+				{
+					name:    `ECDH_1PU_A128KW`,
+					value:   "ECDH-1PU+A128KW",
+					comment: `ECDH-1PU + AES key wrap (128)`,
+				},
+				{
+					name:    `ECDH_1PU_A192KW`,
+					value:   "ECDH-1PU+A192KW",
+					comment: `ECDH-1PU + AES key wrap (192)`,
+				},
+				{
+					name:    `ECDH_1PU_A256KW`,
+					value:   "ECDH-1PU+A256KW",
+					comment: `ECDH-1PU + AES key wrap (256)`,
+				},
 				{
 					name:    `A128GCMKW`,
 					value:   "A128GCMKW",
@@ -408,11 +438,38 @@ func (t typ) Generate() error {
 	}
 	o.L("}")
 
-	o.LL("var list%sOnce sync.Once", t.name)
+	o.LL("var mu%[1]ss sync.RWMutex", t.name)
 	o.L("var list%[1]s []%[1]s", t.name)
-	o.LL("// %[1]ss returns a list of all available values for %[1]s", t.name)
-	o.L("func %[1]ss() []%[1]s {", t.name)
-	o.L("list%sOnce.Do(func() {", t.name)
+
+	o.LL("func init() {")
+	o.L("mu%ss.Lock()", t.name)
+	o.L("defer mu%ss.Unlock()", t.name)
+	o.L("rebuild%s()", t.name)
+	o.L("}")
+
+	o.LL("// Register%[1]s registers a new %[1]s so that the jwx can properly handle the new value.", t.name)
+	o.L("// Duplicates will silently be ignored")
+	o.L("func Register%[1]s(v %[1]s) {", t.name)
+	o.L("mu%ss.Lock()", t.name)
+	o.L("defer mu%ss.Unlock()", t.name)
+	o.L("if _, ok := all%ss[v]; !ok {", t.name)
+	o.L("all%ss[v] = struct{}{}", t.name)
+	o.L("rebuild%s()", t.name)
+	o.L("}")
+	o.L("}")
+
+	o.LL("// Unregister%[1]s unregisters a %[1]s from its known database.", t.name)
+	o.L("// Non-existent entries will silently be ignored")
+	o.L("func Unregister%[1]s(v %[1]s) {", t.name)
+	o.L("mu%ss.Lock()", t.name)
+	o.L("defer mu%ss.Unlock()", t.name)
+	o.L("if _, ok := all%ss[v]; ok {", t.name)
+	o.L("delete(all%ss, v)", t.name)
+	o.L("rebuild%s()", t.name)
+	o.L("}")
+	o.L("}")
+
+	o.LL("func rebuild%s() {", t.name)
 	o.L("list%[1]s = make([]%[1]s, 0, len(all%[1]ss))", t.name)
 	o.L("for v := range all%ss {", t.name)
 	o.L("list%[1]s = append(list%[1]s, v)", t.name)
@@ -420,7 +477,12 @@ func (t typ) Generate() error {
 	o.L("sort.Slice(list%s, func(i, j int) bool {", t.name)
 	o.L("return string(list%[1]s[i]) < string(list%[1]s[j])", t.name)
 	o.L("})")
-	o.L("})")
+	o.L("}")
+
+	o.LL("// %[1]ss returns a list of all available values for %[1]s", t.name)
+	o.L("func %[1]ss() []%[1]s {", t.name)
+	o.L("mu%ss.RLock()", t.name)
+	o.L("defer mu%ss.RUnlock()", t.name)
 	o.L("return list%s", t.name)
 	o.L("}")
 
@@ -443,6 +505,8 @@ func (t typ) Generate() error {
 	o.L("tmp = %s(s)", t.name)
 	o.L("}")
 
+	o.LL("mu%ss.RLock()", t.name)
+	o.L("defer mu%ss.RUnlock()", t.name)
 	o.L("if _, ok := all%ss[tmp]; !ok {", t.name)
 	o.L("return fmt.Errorf(`invalid jwa.%s value`)", t.name)
 	o.L("}")